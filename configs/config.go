@@ -18,11 +18,18 @@ type Config struct {
 		Pool int    `yaml:"pool"`
 	} `yaml:"REDIS"`
 	NitradoService struct {
-		URL string `yaml:"url"`
+		URL              string `yaml:"url"`
+		Timeout          int    `yaml:"timeout"`
+		Retries          int    `yaml:"retries"`
+		RetryDelay       int    `yaml:"retry_delay"`
+		MaxInFlight      int    `yaml:"max_in_flight"`
+		BreakerThreshold int    `yaml:"breaker_threshold"`
+		BreakerCooldown  int    `yaml:"breaker_cooldown"`
 	} `yaml:"NITRADO_SERVICE"`
 	GuildConfigService struct {
-		Host     string `yaml:"host"`
-		BasePath string `yaml:"base_path"`
+		Host         string `yaml:"host"`
+		BasePath     string `yaml:"base_path"`
+		FeedLocalTTL int    `yaml:"feed_local_ttl"`
 	} `yaml:"GUILD_CONFIG_SERVICE"`
 	CacheSettings struct {
 		ActivationToken                    CacheSetting `yaml:"activation_token"`
@@ -42,6 +49,26 @@ type Config struct {
 		RemoveRoleReaction                 CacheSetting `yaml:"remove_role_reaction"`
 		OnlinePlayersOutputChannelMessages CacheSetting `yaml:"online_players_output_channel_messages"`
 		RefreshBansReaction                CacheSetting `yaml:"refresh_bans_reaction"`
+		PvPStats                           CacheSetting `yaml:"pvp_stats"`
+		PvPLeaderboardPosts                CacheSetting `yaml:"pvp_leaderboard_posts"`
+		ServerStats                        CacheSetting `yaml:"server_stats"`
+		DailyDigest                        CacheSetting `yaml:"daily_digest"`
+		LogCheckpoints                     CacheSetting `yaml:"log_checkpoints"`
+		SeenLogs                           CacheSetting `yaml:"seen_logs"`
+		LogArchive                         CacheSetting `yaml:"log_archive"`
+		Outbox                             CacheSetting `yaml:"outbox"`
+		OutputWebhooks                     CacheSetting `yaml:"output_webhooks"`
+		HTTPSinks                          CacheSetting `yaml:"http_sinks"`
+		OnlinePlayers                      CacheSetting `yaml:"online_players"`
+		EventStreams                       CacheSetting `yaml:"event_streams"`
+		LiveFeed                           CacheSetting `yaml:"live_feed"`
+		LiveFeedTokens                     CacheSetting `yaml:"live_feed_tokens"`
+		GuildFeed                          CacheSetting `yaml:"guild_feed"`
+		CommandDedupe                      CacheSetting `yaml:"command_dedupe"`
+		RunnerJobs                         CacheSetting `yaml:"runner_jobs"`
+		PollSchedule                       CacheSetting `yaml:"poll_schedule"`
+		ShardGuilds                        CacheSetting `yaml:"shard_guilds"`
+		GuildWelcomes                      CacheSetting `yaml:"guild_welcomes"`
 	} `yaml:"CACHE_SETTINGS"`
 	Bot struct {
 		Prefix           string `yaml:"prefix"`
@@ -56,9 +83,106 @@ type Config struct {
 		ErrorThumbnail   string `yaml:"error_thumbnail"`
 	} `yaml:"BOT"`
 	Runners struct {
-		Logs    Runner `yaml:"logs"`
-		Players Runner `yaml:"players"`
+		Logs         Runner `yaml:"logs"`
+		Players      Runner `yaml:"players"`
+		Leaderboard  Runner `yaml:"leaderboard"`
+		Digest       Runner `yaml:"digest"`
+		ArchivePrune Runner `yaml:"archive_prune"`
+		Outbox       Runner `yaml:"outbox"`
 	} `yaml:"RUNNERS"`
+	AdminLogs struct {
+		FlaggedCommands []string `yaml:"flagged_commands"`
+	} `yaml:"ADMIN_LOGS"`
+	LogArchive struct {
+		Enabled     bool          `yaml:"enabled"`
+		Retention   time.Duration `yaml:"retention"`
+		PageSize    int           `yaml:"page_size"`
+		ExportLimit int           `yaml:"export_limit"`
+	} `yaml:"LOG_ARCHIVE"`
+	Outbox struct {
+		MaxAttempts    int           `yaml:"max_attempts"`
+		BaseBackoff    time.Duration `yaml:"base_backoff"`
+		MaxBackoff     time.Duration `yaml:"max_backoff"`
+		BatchSize      int           `yaml:"batch_size"`
+		MaxDeadLetters int           `yaml:"max_dead_letters"`
+	} `yaml:"OUTBOX"`
+	OutputWebhooks struct {
+		Name      string `yaml:"name"`
+		AvatarURL string `yaml:"avatar_url"`
+	} `yaml:"OUTPUT_WEBHOOKS"`
+	HTTPSinks struct {
+		MaxEndpoints    int           `yaml:"max_endpoints"`
+		Workers         int           `yaml:"workers"`
+		Timeout         time.Duration `yaml:"timeout"`
+		MaxAttempts     int           `yaml:"max_attempts"`
+		BaseBackoff     time.Duration `yaml:"base_backoff"`
+		MaxBackoff      time.Duration `yaml:"max_backoff"`
+		DeliveryLogSize int           `yaml:"delivery_log_size"`
+	} `yaml:"HTTP_SINKS"`
+	DiscordScheduler struct {
+		Workers int `yaml:"workers"`
+	} `yaml:"DISCORD_SCHEDULER"`
+	LiveFeed struct {
+		Enabled        bool          `yaml:"enabled"`
+		BufferSize     int           `yaml:"buffer_size"`
+		MaxSubscribers int           `yaml:"max_subscribers"`
+		PingInterval   time.Duration `yaml:"ping_interval"`
+		WriteTimeout   time.Duration `yaml:"write_timeout"`
+		AllowedOrigins []string      `yaml:"allowed_origins"`
+	} `yaml:"LIVE_FEED"`
+	EventStreams struct {
+		MaxLength int `yaml:"max_length"`
+	} `yaml:"EVENT_STREAMS"`
+	EventBus struct {
+		BufferSize     int           `yaml:"buffer_size"`
+		Workers        int           `yaml:"workers"`
+		PublishTimeout time.Duration `yaml:"publish_timeout"`
+	} `yaml:"EVENT_BUS"`
+	Health struct {
+		CheckTimeout      time.Duration `yaml:"check_timeout"`
+		GatewayStaleAfter time.Duration `yaml:"gateway_stale_after"`
+	} `yaml:"HEALTH"`
+	Shutdown struct {
+		Timeout time.Duration `yaml:"timeout"`
+	} `yaml:"SHUTDOWN"`
+	Panics struct {
+		OperatorChannelID string        `yaml:"operator_channel_id"`
+		ReportInterval    time.Duration `yaml:"report_interval"`
+	} `yaml:"PANICS"`
+	LeaderElection struct {
+		Enabled       bool          `yaml:"enabled"`
+		Key           string        `yaml:"key"`
+		LeaseTTL      time.Duration `yaml:"lease_ttl"`
+		RenewInterval time.Duration `yaml:"renew_interval"`
+	} `yaml:"LEADER_ELECTION"`
+	JobQueue struct {
+		VisibilityTimeout time.Duration `yaml:"visibility_timeout"`
+		MaxAttempts       int           `yaml:"max_attempts"`
+		RetryDelay        time.Duration `yaml:"retry_delay"`
+		PollInterval      time.Duration `yaml:"poll_interval"`
+	} `yaml:"JOB_QUEUE"`
+	PollSchedule struct {
+		Tick                time.Duration `yaml:"tick"`
+		Refresh             time.Duration `yaml:"refresh"`
+		BatchSize           int           `yaml:"batch_size"`
+		Jitter              float64       `yaml:"jitter"`
+		MaxBackoff          int           `yaml:"max_backoff"`
+		SlowAfter           time.Duration `yaml:"slow_after"`
+		InFlightTimeout     time.Duration `yaml:"in_flight_timeout"`
+		MaxInFlightPerToken int           `yaml:"max_in_flight_per_token"`
+	} `yaml:"POLL_SCHEDULE"`
+	Sharding struct {
+		Auto             bool          `yaml:"auto"`
+		ShardID          int           `yaml:"shard_id"`
+		ShardCount       int           `yaml:"shard_count"`
+		IdentifyInterval time.Duration `yaml:"identify_interval"`
+		PublishInterval  time.Duration `yaml:"publish_interval"`
+	} `yaml:"SHARDING"`
+	GuildLifecycle struct {
+		WelcomeEnabled bool          `yaml:"welcome_enabled"`
+		WelcomeWindow  time.Duration `yaml:"welcome_window"`
+		WelcomeSteps   []string      `yaml:"welcome_steps"`
+	} `yaml:"GUILD_LIFECYCLE"`
 	Commands  []Command           `yaml:"COMMANDS"`
 	Reactions map[string]Reaction `yaml:"REACTIONS"`
 }
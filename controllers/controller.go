@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"runtime/debug"
 
-	"github.com/bwmarrin/discordgo"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/livefeed"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/shards"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/viewmodels"
@@ -19,8 +21,10 @@ import (
 type Controller struct {
 	Config             *configs.Config
 	Cache              *cache.Cache
-	DiscordSession     *discordgo.Session
+	Shards             *shards.Manager
 	GuildConfigService *guildconfigservice.GuildConfigService
+	LiveFeed           *livefeed.Hub
+	EventBus           *eventbus.Bus
 }
 
 // Response sends a response to the client
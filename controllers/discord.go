@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
 
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
@@ -10,67 +9,56 @@ import (
 	"go.uber.org/zap"
 )
 
-// GetAllGuilds responds with all connected guilds from the cached state
+// GetAllGuilds responds with the connected guilds of every shard
 func (c *Controller) GetAllGuilds(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	if !c.DiscordSession.StateEnabled {
-		Error(ctx, w, "Discord state is not enabled", errors.New("discord state not enabled"), http.StatusNotFound)
-	}
-
-	guilds := c.DiscordSession.State.Guilds
-	if guilds == nil {
-		Error(ctx, w, "No guilds in Discord state", errors.New("discord state has no guilds"), http.StatusNotFound)
+	guilds, missing, agErr := c.Shards.AllGuilds(ctx, c.Cache, c.Config.CacheSettings.ShardGuilds)
+	if agErr != nil {
+		Error(ctx, w, agErr.Message, agErr.Err, http.StatusNotFound)
+		return
 	}
 
 	var outputGuilds []*viewmodels.SmallGuild
 	for _, aGuild := range guilds {
-		var guild viewmodels.SmallGuild
-		guild.ID = aGuild.ID
-		guild.MemberCount = aGuild.MemberCount
-		guild.OwnerID = aGuild.OwnerID
-
-		dGuild, dgErr := c.DiscordSession.Guild(aGuild.ID)
-		if dgErr == nil {
-			guild.Name = dGuild.Name
-		}
-
-		outputGuilds = append(outputGuilds, &guild)
+		outputGuilds = append(outputGuilds, &viewmodels.SmallGuild{
+			ID:          aGuild.ID,
+			Name:        aGuild.Name,
+			OwnerID:     aGuild.OwnerID,
+			MemberCount: aGuild.MemberCount,
+			Shard:       aGuild.Shard,
+		})
 	}
 
 	Response(ctx, w, viewmodels.GetAllGuildsResponse{
-		Message: "Found guilds",
-		Count:   len(guilds),
-		Guilds:  outputGuilds,
+		Message:       "Found guilds",
+		Count:         len(guilds),
+		Guilds:        outputGuilds,
+		MissingShards: missing,
 	}, http.StatusOK)
 }
 
-// GetAllGuilds responds with all connected guilds from the cached state
+// VerifySubscriberGuilds responds with the connected guilds of every shard and whether each one subscribes to the bot
 func (c *Controller) VerifySubscriberGuilds(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	if !c.DiscordSession.StateEnabled {
-		Error(ctx, w, "Discord state is not enabled", errors.New("discord state not enabled"), http.StatusNotFound)
-	}
-
-	guilds := c.DiscordSession.State.Guilds
-	if guilds == nil {
-		Error(ctx, w, "No guilds in Discord state", errors.New("discord state has no guilds"), http.StatusNotFound)
+	guilds, missing, agErr := c.Shards.AllGuilds(ctx, c.Cache, c.Config.CacheSettings.ShardGuilds)
+	if agErr != nil {
+		Error(ctx, w, agErr.Message, agErr.Err, http.StatusNotFound)
+		return
 	}
 
 	var outputGuilds []*viewmodels.VerifiedSmallGuild
 	var countVerified int = 0
 	for _, aGuild := range guilds {
-		var guild viewmodels.VerifiedSmallGuild
-		guild.ID = aGuild.ID
-		guild.MemberCount = aGuild.MemberCount
-		guild.OwnerID = aGuild.OwnerID
-
-		dGuild, dgErr := c.DiscordSession.Guild(aGuild.ID)
-		if dgErr == nil {
-			guild.Name = dGuild.Name
+		guild := viewmodels.VerifiedSmallGuild{
+			ID:          aGuild.ID,
+			Name:        aGuild.Name,
+			OwnerID:     aGuild.OwnerID,
+			MemberCount: aGuild.MemberCount,
+			Shard:       aGuild.Shard,
 		}
 
 		guildFeedOK, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, aGuild.ID)
@@ -101,5 +89,6 @@ func (c *Controller) VerifySubscriberGuilds(w http.ResponseWriter, r *http.Reque
 		VerifiedCount:   countVerified,
 		UnverifiedCount: len(guilds) - countVerified,
 		Guilds:          outputGuilds,
+		MissingShards:   missing,
 	}, http.StatusOK)
 }
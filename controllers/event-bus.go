@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/viewmodels"
+	"go.uber.org/zap"
+)
+
+// GetEventBusStats responds with the queue length and delivered and dropped batch counts of each event bus subscriber
+func (c *Controller) GetEventBusStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	subscribers := []viewmodels.EventBusSubscriber{}
+	for _, stats := range c.EventBus.Stats() {
+		subscribers = append(subscribers, viewmodels.EventBusSubscriber{
+			Name:      stats.Name,
+			Queued:    stats.Queued,
+			Delivered: stats.Delivered,
+			Dropped:   stats.Dropped,
+		})
+	}
+
+	Response(ctx, w, viewmodels.GetEventBusStatsResponse{
+		Message:     "Found event bus subscribers",
+		Subscribers: subscribers,
+	}, http.StatusOK)
+}
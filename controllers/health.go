@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/viewmodels"
+	"go.uber.org/zap"
+)
+
+// readinessCheck reports why a dependency is unusable, or nil if it is usable
+type readinessCheck func(ctx context.Context) error
+
+// GetHealth responds with 200 while the process is alive.
+// Dependencies, including the Discord gateways, are checked by GetReadiness so an outage there does not get the process restarted.
+func (c *Controller) GetHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	Response(ctx, w, viewmodels.GetHealthResponse{
+		Message: "Service is alive",
+	}, http.StatusOK)
+}
+
+// GetReadiness responds with the result of each dependency check, and 503 if any of them failed
+func (c *Controller) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	names := []string{"discord_gateway", "redis", "guild_config_service", "nitrado_service"}
+	checks := map[string]readinessCheck{
+		"discord_gateway":      c.checkDiscordGateway,
+		"redis":                c.checkRedis,
+		"guild_config_service": c.checkHTTP(fmt.Sprintf("http://%s%s/status", c.Config.GuildConfigService.Host, c.Config.GuildConfigService.BasePath)),
+		"nitrado_service":      c.checkHTTP(c.Config.NitradoService.URL + "/status"),
+	}
+
+	results := make([]viewmodels.ReadinessCheck, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = runReadinessCheck(ctx, name, checks[name], c.Config.Health.CheckTimeout*time.Second)
+		}(i, name)
+	}
+	wg.Wait()
+
+	response := viewmodels.GetReadinessResponse{
+		Message: "Service is ready",
+		Ready:   true,
+		Checks:  results,
+	}
+
+	for _, result := range results {
+		if !result.OK {
+			response.Message = "Service is not ready"
+			response.Ready = false
+		}
+	}
+
+	if !response.Ready {
+		Response(ctx, w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	Response(ctx, w, response, http.StatusOK)
+}
+
+// runReadinessCheck runs a check within the timeout
+func runReadinessCheck(ctx context.Context, name string, check readinessCheck, timeout time.Duration) viewmodels.ReadinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		defer recovery.Recover(ctx, "readiness:"+name, "")
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.New("check timed out")
+	}
+
+	result := viewmodels.ReadinessCheck{
+		Name:      name,
+		OK:        err == nil,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// checkDiscordGateway fails while any shard's gateway is disconnected or missing heartbeat acks.
+// discordgo reconnects on its own, so a shard only counts as wedged once it has had no ack for longer than the configured limit.
+func (c *Controller) checkDiscordGateway(ctx context.Context) error {
+	staleAfter := c.Config.Health.GatewayStaleAfter * time.Second
+	for _, session := range c.Shards.Sessions {
+		session.RLock()
+		ready := session.DataReady
+		lastAck := session.LastHeartbeatAck
+		lastSent := session.LastHeartbeatSent
+		session.RUnlock()
+
+		if !ready {
+			return fmt.Errorf("shard %d gateway is not connected", session.ShardID)
+		}
+
+		if lastAck.Before(lastSent) && time.Since(lastSent) > c.Config.Health.CheckTimeout*time.Second {
+			return fmt.Errorf("shard %d last heartbeat was not acked after %s", session.ShardID, time.Since(lastSent).Round(time.Second))
+		}
+
+		if since := time.Since(lastAck); since > staleAfter {
+			return fmt.Errorf("shard %d has had no heartbeat ack for %s", session.ShardID, since.Round(time.Second))
+		}
+	}
+
+	return nil
+}
+
+// checkRedis sends a PING
+func (c *Controller) checkRedis(ctx context.Context) error {
+	var pong string
+	if err := c.Cache.Client.Do(radix.Cmd(&pong, "PING")); err != nil {
+		return err
+	}
+
+	if pong != "PONG" {
+		return fmt.Errorf("unexpected PING reply: %s", pong)
+	}
+
+	return nil
+}
+
+// checkHTTP returns a check that passes when a service answers a GET request without a server error
+func (c *Controller) checkHTTP(url string) readinessCheck {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("responded with status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/viewmodels"
+	"go.uber.org/zap"
+)
+
+// liveFeedTokenProtocol prefixes a live feed token sent as a WebSocket subprotocol
+const liveFeedTokenProtocol = "token."
+
+// liveFeedUpgrader only accepts browsers on the configured origins.
+// Clients that send no Origin header aren't browsers and are authorized by their Service-Token header or guild token alone.
+func (c *Controller) liveFeedUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+
+			for _, allowed := range c.Config.LiveFeed.AllowedOrigins {
+				if strings.EqualFold(origin, allowed) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// CreateLiveFeedToken creates a token a browser can open the guild's live feed with in place of the Service-Token header
+func (c *Controller) CreateLiveFeedToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if c.LiveFeed == nil || !c.Config.CacheSettings.LiveFeedTokens.Enabled {
+		Error(ctx, w, "Live feed tokens are not enabled", errors.New("live feed tokens disabled"), http.StatusNotFound)
+		return
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		Error(ctx, w, "Failed to generate live feed token", err, http.StatusInternalServerError)
+		return
+	}
+
+	ttl, _ := strconv.ParseInt(c.Config.CacheSettings.LiveFeedTokens.TTL, 10, 64)
+	liveFeedToken := models.LiveFeedToken{
+		Token:     hex.EncodeToString(b),
+		GuildID:   mux.Vars(r)["guild_id"],
+		ExpiresAt: time.Now().Unix() + ttl,
+	}
+
+	cacheKey := liveFeedToken.CacheKey(c.Config.CacheSettings.LiveFeedTokens.Base, liveFeedToken.Token)
+	if setCacheErr := c.Cache.SetStruct(ctx, cacheKey, &liveFeedToken, c.Config.CacheSettings.LiveFeedTokens.TTL); setCacheErr != nil {
+		Error(ctx, w, setCacheErr.Message, setCacheErr.Err, http.StatusInternalServerError)
+		return
+	}
+
+	Response(ctx, w, viewmodels.CreateLiveFeedTokenResponse{
+		Message:       "Created record",
+		LiveFeedToken: liveFeedToken,
+	}, http.StatusCreated)
+}
+
+// VerifyLiveFeedToken reports whether a request carries a live feed token for the guild,
+// either in the token query parameter or as a "token.<token>" WebSocket subprotocol
+func (c *Controller) VerifyLiveFeedToken(r *http.Request, guildID string) bool {
+	ctx := logging.AddValues(r.Context(), zap.String("scope", logging.GetFuncName()))
+
+	token, _ := liveFeedToken(r)
+	if token == "" || guildID == "" || !c.Config.CacheSettings.LiveFeedTokens.Enabled {
+		return false
+	}
+
+	var stored *models.LiveFeedToken
+	cacheKey := stored.CacheKey(c.Config.CacheSettings.LiveFeedTokens.Base, token)
+	if gsErr := c.Cache.GetStruct(ctx, cacheKey, &stored); gsErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", gsErr.Err), zap.String("error_message", gsErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+		return false
+	}
+
+	return stored != nil && stored.GuildID == guildID
+}
+
+// liveFeedToken returns the request's live feed token and, if it was sent as a subprotocol, the subprotocol to accept
+func liveFeedToken(r *http.Request) (string, string) {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token, ""
+	}
+
+	for _, protocol := range websocket.Subprotocols(r) {
+		if strings.HasPrefix(protocol, liveFeedTokenProtocol) {
+			return strings.TrimPrefix(protocol, liveFeedTokenProtocol), protocol
+		}
+	}
+
+	return "", ""
+}
+
+// StreamGuildEvents upgrades to a WebSocket and streams a guild's game events as JSON text messages.
+// The optional servers and events query parameters are comma separated filters.
+func (c *Controller) StreamGuildEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if c.LiveFeed == nil {
+		Error(ctx, w, "Live feed is not enabled", errors.New("live feed disabled"), http.StatusNotFound)
+		return
+	}
+
+	guildID := mux.Vars(r)["guild_id"]
+
+	var servers []int64
+	for _, val := range splitQuery(r.URL.Query().Get("servers")) {
+		serverID, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			Error(ctx, w, fmt.Sprintf("Invalid server ID: %s", val), err, http.StatusBadRequest)
+			return
+		}
+		servers = append(servers, serverID)
+	}
+
+	events := splitQuery(strings.ToLower(r.URL.Query().Get("events")))
+	for _, event := range events {
+		if !models.IsGameEventType(event) {
+			Error(ctx, w, fmt.Sprintf("Unknown event type: %s. Use %s.", event, strings.Join(models.GameEventTypes, ", ")), errors.New("invalid event type"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	sub, sErr := c.LiveFeed.Subscribe(guildID, servers, events)
+	if sErr != nil {
+		Error(ctx, w, sErr.Message, sErr.Err, http.StatusTooManyRequests)
+		return
+	}
+	defer c.LiveFeed.Unsubscribe(sub)
+
+	// A browser that sent its token as a subprotocol only accepts the connection if that subprotocol is echoed back
+	var responseHeader http.Header
+	if _, protocol := liveFeedToken(r); protocol != "" && r.Header.Get("Service-Token") == "" {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": []string{protocol}}
+	}
+
+	conn, uErr := c.liveFeedUpgrader().Upgrade(w, r, responseHeader)
+	if uErr != nil {
+		// Upgrade has already responded to the client
+		ctx = logging.AddValues(ctx, zap.NamedError("error", uErr), zap.String("error_message", "Failed to upgrade live feed connection"))
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+		return
+	}
+	defer conn.Close()
+
+	pingInterval := c.Config.LiveFeed.PingInterval * time.Second
+	writeTimeout := c.Config.LiveFeed.WriteTimeout * time.Second
+
+	// Clients only send control frames; reading is what processes pongs and notices a closed connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		defer recovery.Recover(ctx, "live_feed", "")
+
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+		})
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message, ok := <-sub.Messages:
+			if !ok {
+				reason := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+				if sub.Overflowed() {
+					reason = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "live feed fell behind")
+				} else if sub.GoingAway() {
+					reason = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+				}
+				conn.WriteControl(websocket.CloseMessage, reason, time.Now().Add(writeTimeout))
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// splitQuery splits a comma separated query parameter, ignoring empty values
+func splitQuery(val string) []string {
+	var parts []string
+	for _, part := range strings.Split(val, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
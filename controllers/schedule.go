@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pollschedule"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/viewmodels"
+	"go.uber.org/zap"
+)
+
+// scheduledRunners are the runners that poll servers on a schedule
+var scheduledRunners = map[string]bool{
+	"logs": true,
+}
+
+// GetRunnerSchedule responds with when each server of this replica's shards is next due to be polled by a runner and how its last poll went
+func (c *Controller) GetRunnerSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	runner := mux.Vars(r)["runner"]
+	if !scheduledRunners[runner] {
+		Error(ctx, w, "Runner has no schedule", errors.New("unknown runner"), http.StatusNotFound)
+		return
+	}
+
+	if !c.Config.CacheSettings.PollSchedule.Enabled {
+		Error(ctx, w, "Poll schedule is not enabled", errors.New("poll schedule disabled"), http.StatusNotFound)
+		return
+	}
+
+	states, sErr := pollschedule.GetStates(ctx, c.Cache, c.Config.CacheSettings.PollSchedule, c.Shards.Scope(runner))
+	if sErr != nil {
+		Error(ctx, w, sErr.Message, sErr.Err, http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	servers := []viewmodels.ScheduledServer{}
+	for _, state := range states {
+		server := viewmodels.ScheduledServer{
+			ServerID:       state.ServerID,
+			NextDue:        state.NextDue.Unix(),
+			Backoff:        state.Backoff,
+			InFlight:       state.InFlight,
+			LastLagMS:      state.LastLag.Milliseconds(),
+			LastDurationMS: state.LastDuration.Milliseconds(),
+			LastFailed:     state.LastFailed,
+		}
+		if now.After(state.NextDue) {
+			server.OverdueMS = now.Sub(state.NextDue).Milliseconds()
+		}
+		if !state.LastStarted.IsZero() {
+			server.LastStarted = state.LastStarted.Unix()
+		}
+		if !state.LastFinished.IsZero() {
+			server.LastFinished = state.LastFinished.Unix()
+		}
+		servers = append(servers, server)
+	}
+
+	Response(ctx, w, viewmodels.GetRunnerScheduleResponse{
+		Message: "Found runner schedule",
+		Runner:  runner,
+		Servers: servers,
+	}, http.StatusOK)
+}
@@ -10,9 +10,12 @@ require (
 	github.com/go-openapi/strfmt v0.20.1
 	github.com/google/uuid v1.2.0
 	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/websocket v1.4.0
 	github.com/mediocregopher/radix/v3 v3.7.0
+	github.com/prometheus/client_golang v1.11.0
 	gitlab.com/BIC_Dev/guild-config-service-client v0.7.1
 	gitlab.com/BIC_Dev/nitrado-service-v2-client v1.1.0
 	go.uber.org/zap v1.16.0
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -3,27 +3,41 @@ package interactions
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/google/uuid"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/interactions/commands"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/interactions/reactions"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/httpsink"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/logarchive"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/nitradoservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/shards"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
 // Interactions struct
 type Interactions struct {
 	Session                  *discordgo.Session
+	Shards                   *shards.Manager
 	Config                   *configs.Config
 	Cache                    *cache.Cache
 	GuildConfigService       *guildconfigservice.GuildConfigService
 	NitradoService           *nitradoservice.NitradoService
+	LogArchive               *logarchive.LogArchive
+	HTTPSink                 *httpsink.Sink
+	EventBus                 *eventbus.Bus
 	MessagesAwaitingReaction *reactions.MessagesAwaitingReaction
+
+	mu       sync.RWMutex
+	stopping bool
+	inFlight sync.WaitGroup
 }
 
 // Error struct
@@ -44,20 +58,65 @@ func (i *Interactions) SetupHandlers() {
 		Messages: make(map[string]reactions.MessageAwaitingReaction),
 	}
 
-	go reactions.ExpireMessagesAwaitingReaction(i.MessagesAwaitingReaction)
+	recovery.Go(context.Background(), "expire_messages_awaiting_reaction", "", func() {
+		reactions.ExpireMessagesAwaitingReaction(i.MessagesAwaitingReaction)
+	})
+
+	i.Shards.AddHandler(i.MessageCreate)
+	i.Shards.AddHandler(i.MessageReaction)
+	i.Shards.AddHandler(i.GuildCreate)
+	i.Shards.AddHandler(i.GuildDelete)
+	i.Shards.AddHandler(i.ChannelDelete)
+}
 
-	i.Session.AddHandler(i.MessageCreate)
-	i.Session.AddHandler(i.MessageReaction)
+// Stop ignores new commands and reactions and waits for the ones already running to finish
+func (i *Interactions) Stop(ctx context.Context) error {
+	i.mu.Lock()
+	i.stopping = true
+	i.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		i.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin reports whether a new command or reaction may start, counting it as in flight if so
+func (i *Interactions) begin() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.stopping {
+		return false
+	}
+
+	i.inFlight.Add(1)
+	return true
 }
 
 // MessageCreate func
 func (i *Interactions) MessageCreate(s *discordgo.Session, mc *discordgo.MessageCreate) {
+	if !i.begin() {
+		return
+	}
+	defer i.inFlight.Done()
+
 	requestID := uuid.New()
 
 	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", requestID.String()))
+	defer recovery.Recover(ctx, "message_create", mc.ChannelID)
+
 	ctx = logging.AddValues(
 		ctx,
-		zap.String("request_id", requestID.String()),
 		zap.String("scope", logging.GetFuncName()),
 		zap.String("guild_id", mc.GuildID),
 		zap.String("channel_id", mc.ChannelID),
@@ -78,12 +137,18 @@ func (i *Interactions) MessageCreate(s *discordgo.Session, mc *discordgo.Message
 
 	// Check if the message is a command
 	if strings.HasPrefix(strings.ToLower(mc.Content), i.Config.Bot.Prefix) {
+		if !i.claim(ctx, i.Config.CacheSettings.CommandDedupe, mc.Message.ID) {
+			return
+		}
+
 		commands := commands.Commands{
 			Session:                  i.Session,
 			Config:                   i.Config,
 			Cache:                    i.Cache,
 			GuildConfigService:       i.GuildConfigService,
 			NitradoService:           i.NitradoService,
+			LogArchive:               i.LogArchive,
+			HTTPSink:                 i.HTTPSink,
 			MessagesAwaitingReaction: i.MessagesAwaitingReaction,
 		}
 		commands.Factory(ctx, s, mc)
@@ -91,14 +156,40 @@ func (i *Interactions) MessageCreate(s *discordgo.Session, mc *discordgo.Message
 	}
 }
 
+// claim reports whether this replica should handle a command or guild event, claiming its ID under the given cache settings.
+// Every replica receives the same event, so only the first to claim its ID handles it; if Redis fails the event is handled anyway.
+// Reactions need no claim because only the replica that posted a prompt is waiting for reactions to it.
+func (i *Interactions) claim(ctx context.Context, settings configs.CacheSetting, id string) bool {
+	if !settings.Enabled {
+		return true
+	}
+
+	claimed, err := i.Cache.SetNX(ctx, cache.GenerateKey(settings.Base, id), "1", settings.TTL)
+	if err != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+		return true
+	}
+
+	return claimed
+}
+
 // MessageReaction func
 func (i *Interactions) MessageReaction(s *discordgo.Session, mra *discordgo.MessageReactionAdd) {
+	if !i.begin() {
+		return
+	}
+	defer i.inFlight.Done()
+
 	requestID := uuid.New()
 
 	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", requestID.String()))
+	defer recovery.Recover(ctx, "message_reaction", mra.ChannelID)
+
 	ctx = logging.AddValues(
 		ctx,
-		zap.String("request_id", requestID.String()),
 		zap.String("scope", logging.GetFuncName()),
 		zap.String("guild_id", mra.GuildID),
 		zap.String("message_id", mra.MessageID),
@@ -139,6 +230,7 @@ func (i *Interactions) MessageReaction(s *discordgo.Session, mra *discordgo.Mess
 		Cache:                    i.Cache,
 		GuildConfigService:       i.GuildConfigService,
 		NitradoService:           i.NitradoService,
+		EventBus:                 i.EventBus,
 		MessagesAwaitingReaction: i.MessagesAwaitingReaction,
 	}
 
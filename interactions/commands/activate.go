@@ -14,6 +14,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
 	"go.uber.org/zap"
 )
@@ -175,6 +176,8 @@ func (c *Commands) Activate(ctx context.Context, s *discordgo.Session, mc *disco
 			})
 			return
 		}
+
+		guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
 	}
 
 	if guildNeedsGuildService {
@@ -195,6 +198,8 @@ func (c *Commands) Activate(ctx context.Context, s *discordgo.Session, mc *disco
 			})
 			return
 		}
+
+		guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
 	} else if guildNeedsGuildServiceActivation {
 		guildServiceBody := gcscmodels.UpdateGuildServiceRequest{
 			Enabled: true,
@@ -215,6 +220,8 @@ func (c *Commands) Activate(ctx context.Context, s *discordgo.Session, mc *disco
 			})
 			return
 		}
+
+		guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
 	}
 
 	atExpErr := c.Cache.Expire(ctx, cacheKey)
@@ -113,13 +113,13 @@ func (c *Commands) AddNitradoToken(ctx context.Context, s *discordgo.Session, mc
 		}
 	}
 
-	createResponse, createErr := c.NitradoService.Client.UpdateNitradoToken(nsv2.UpdateNitradoTokenRequest{
+	createResponse, createErr := c.NitradoService.UpdateNitradoToken(ctx, nsv2.UpdateNitradoTokenRequest{
 		NitradoToken: addNitradoTokenCommand.Params.Token,
 		Accounts:     accounts,
 	})
 	if createErr != nil {
 		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
-			Message: createErr.Message(),
+			Message: createErr.Message,
 			Err:     createErr,
 		})
 		return
@@ -155,6 +155,8 @@ func (c *Commands) AddNitradoToken(ctx context.Context, s *discordgo.Session, mc
 			})
 			return
 		}
+
+		guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, nitradoTokenGuild.Guild.ID)
 	}
 
 	anto := AddNitradoTokenOutput{
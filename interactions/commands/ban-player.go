@@ -247,7 +247,7 @@ func (bpc *BanPlayerCommandConfirmationOutput) ConvertToEmbedField() (*discordgo
 	fieldVal := ""
 
 	if len(bpc.Servers) == 1 {
-		name = fmt.Sprintf("%s will be banned on %s", bpc.PlayerName, bpc.Servers[0].Name)
+		name = fmt.Sprintf("%s will be banned on %s", discordapi.EscapeText(bpc.PlayerName), bpc.Servers[0].Name)
 	} else {
 		name = fmt.Sprintf("%s will be banned on %d servers", bpc.PlayerName, len(bpc.Servers))
 	}
@@ -12,9 +12,13 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/interactions/reactions"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/httpsink"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/logarchive"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/nitradoservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +29,8 @@ type Commands struct {
 	Cache                    *cache.Cache
 	GuildConfigService       *guildconfigservice.GuildConfigService
 	NitradoService           *nitradoservice.NitradoService
+	LogArchive               *logarchive.LogArchive
+	HTTPSink                 *httpsink.Sink
 	MessagesAwaitingReaction *reactions.MessagesAwaitingReaction
 }
 
@@ -86,6 +92,12 @@ func (c *Commands) Factory(ctx context.Context, s *discordgo.Session, mc *discor
 		return
 	}
 
+	ctx = metrics.TrackOutcome(ctx)
+	defer func() {
+		metrics.Commands.WithLabelValues(command.Name, metrics.Outcome(metrics.Failed(ctx))).Inc()
+	}()
+	defer recovery.Recover(ctx, "command:"+command.Name, mc.ChannelID)
+
 	if !command.Enabled {
 		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
 			Message: "This command has not been enabled for use",
@@ -161,6 +173,20 @@ func (c *Commands) Factory(ctx context.Context, s *discordgo.Session, mc *discor
 		c.SearchPlayers(ctx, s, mc, command)
 	case "Refresh Bans":
 		c.RefreshBans(ctx, s, mc, command)
+	case "Leaderboard":
+		c.Leaderboard(ctx, s, mc, command)
+	case "Player Stats":
+		c.PlayerStats(ctx, s, mc, command)
+	case "Daily Digest":
+		c.DailyDigest(ctx, s, mc, command)
+	case "Search Logs":
+		c.SearchLogs(ctx, s, mc, command)
+	case "Outbox":
+		c.Outbox(ctx, s, mc, command)
+	case "Output Webhook":
+		c.OutputWebhook(ctx, s, mc, command)
+	case "HTTP Sink":
+		c.HTTPSinkEndpoints(ctx, s, mc, command)
 	default:
 		// TODO: Output error
 	}
@@ -182,6 +208,8 @@ func getCommandConfig(commands []configs.Command, command string) (configs.Comma
 
 // ErrorOutput func
 func (c *Commands) ErrorOutput(ctx context.Context, command configs.Command, content string, channelID string, err Error) ([]*discordgo.Message, *Error) {
+	metrics.MarkFailed(ctx)
+
 	newCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
 	logger := logging.Logger(newCtx)
 	logger.Error("error_log")
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// DailyDigestCommand struct
+type DailyDigestCommand struct {
+	Params DailyDigestCommandParams
+}
+
+// DailyDigestCommandParams struct
+type DailyDigestCommandParams struct {
+	Disable  bool
+	Time     string
+	Timezone string
+}
+
+// DailyDigestSuccessOutput struct
+type DailyDigestSuccessOutput struct {
+	Digest models.DailyDigest
+}
+
+// DailyDigest func
+func (c *Commands) DailyDigest(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, ddErr := parseDailyDigestCommand(command, mc)
+	if ddErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *ddErr)
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "Servers"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	digest := models.DailyDigest{
+		GuildID: mc.GuildID,
+		Channel: models.Channel{
+			ID: mc.ChannelID,
+		},
+		Time:     parsedCommand.Params.Time,
+		Timezone: parsedCommand.Params.Timezone,
+		Enabled:  !parsedCommand.Params.Disable,
+		CreatedBy: &models.User{
+			ID:   mc.Author.ID,
+			Name: mc.Author.Username,
+		},
+	}
+
+	var previous *models.DailyDigest
+	cacheKey := digest.CacheKey(c.Config.CacheSettings.DailyDigest.Base, mc.GuildID)
+	if gsErr := c.Cache.GetStruct(ctx, cacheKey, &previous); gsErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gsErr.Message,
+			Err:     gsErr.Err,
+		})
+		return
+	}
+
+	if previous != nil {
+		digest.LastSent = previous.LastSent
+
+		if parsedCommand.Params.Disable {
+			digest.Channel = previous.Channel
+			digest.Time = previous.Time
+			digest.Timezone = previous.Timezone
+		}
+	}
+
+	if ssErr := c.Cache.SetStruct(ctx, cacheKey, &digest, c.Config.CacheSettings.DailyDigest.TTL); ssErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: ssErr.Message,
+			Err:     ssErr.Err,
+		})
+		return
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	embeddableFields = append(embeddableFields, &DailyDigestSuccessOutput{
+		Digest: digest,
+	})
+
+	embedParams := discordapi.EmbeddableParams{
+		Title:        command.Name,
+		Description:  "Updated the daily digest settings for this Discord server.",
+		TitleURL:     c.Config.Bot.DocumentationURL,
+		Footer:       fmt.Sprintf("Executed by %s", mc.Author.Username),
+		ThumbnailURL: c.Config.Bot.OkThumbnail,
+	}
+
+	c.Output(ctx, mc.ChannelID, embedParams, embeddableFields, embeddableErrors)
+	return
+}
+
+// parseDailyDigestCommand func
+func parseDailyDigestCommand(command configs.Command, mc *discordgo.MessageCreate) (*DailyDigestCommand, *Error) {
+	splitContent := strings.Split(mc.Content, " ")
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	if strings.ToLower(splitContent[1]) == "off" {
+		return &DailyDigestCommand{
+			Params: DailyDigestCommandParams{
+				Disable: true,
+			},
+		}, nil
+	}
+
+	if _, tErr := time.Parse("15:04", splitContent[1]); tErr != nil {
+		return nil, &Error{
+			Message: "Digest time must be in 24 hour HH:MM format",
+			Err:     errors.New("invalid digest time"),
+		}
+	}
+
+	timezone := "UTC"
+	if len(splitContent) == 3 {
+		timezone = splitContent[2]
+	}
+
+	if _, lErr := time.LoadLocation(timezone); lErr != nil {
+		return nil, &Error{
+			Message: fmt.Sprintf("Unknown timezone: %s", timezone),
+			Err:     errors.New("invalid timezone"),
+		}
+	}
+
+	return &DailyDigestCommand{
+		Params: DailyDigestCommandParams{
+			Time:     splitContent[1],
+			Timezone: timezone,
+		},
+	}, nil
+}
+
+// ConvertToEmbedField for DailyDigestSuccessOutput struct
+func (out *DailyDigestSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	if !out.Digest.Enabled {
+		return &discordgo.MessageEmbedField{
+			Name:   "Daily Digest Disabled",
+			Value:  "The daily digest will no longer be posted.",
+			Inline: false,
+		}, nil
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "Daily Digest Enabled",
+		Value:  fmt.Sprintf("Posting to <#%s> every day at %s (%s).", out.Digest.Channel.ID, out.Digest.Time, out.Digest.Timezone),
+		Inline: false,
+	}, nil
+}
@@ -15,6 +15,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nitrado_service_v2_client "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
@@ -128,13 +129,15 @@ func (c *Commands) GetBanlist(ctx context.Context, s *discordgo.Session, mc *dis
 	successChannel := make(chan GetBanlistSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan GetBanlistError, len(guildFeed.Payload.Guild.Servers))
 
-	go c.HandleGetBanlistResponses(ctx, s, mc, command, len(servers), successChannel, errorChannel)
+	recovery.Go(ctx, "command:"+command.Name, mc.ChannelID, func() {
+		c.HandleGetBanlistResponses(ctx, s, mc, command, len(servers), successChannel, errorChannel)
+	})
 
 	for _, stb := range servers {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "command:"+command.Name, func() {
 			c.GetBanlistRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	return
@@ -176,11 +179,11 @@ func parseGetBanlistCommand(command configs.Command, mc *discordgo.MessageCreate
 func (c *Commands) GetBanlistRequest(ctx context.Context, server gcscmodels.Server, getBanlistSuccess chan GetBanlistSuccess, getBanlistError chan GetBanlistError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	resp, err := c.NitradoService.Client.GetBanlist(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
+	resp, err := c.NitradoService.GetBanlist(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
 	if err != nil {
 		getBanlistError <- GetBanlistError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
@@ -319,7 +322,7 @@ func (bps *GetBanlistSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmb
 	fieldVal := "```"
 
 	for _, player := range bps.Players {
-		fieldVal += "\n" + player.Name
+		fieldVal += "\n" + discordapi.EscapeCode(player.Name)
 	}
 
 	if fieldVal == "```" {
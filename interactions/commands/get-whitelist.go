@@ -15,6 +15,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nitrado_service_v2_client "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
@@ -132,13 +133,15 @@ func (c *Commands) GetWhitelist(ctx context.Context, s *discordgo.Session, mc *d
 	successChannel := make(chan GetWhitelistSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan GetWhitelistError, len(guildFeed.Payload.Guild.Servers))
 
-	go c.HandleGetWhitelistResponses(ctx, s, mc, command, len(servers), successChannel, errorChannel)
+	recovery.Go(ctx, "command:"+command.Name, mc.ChannelID, func() {
+		c.HandleGetWhitelistResponses(ctx, s, mc, command, len(servers), successChannel, errorChannel)
+	})
 
 	for _, stb := range servers {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "command:"+command.Name, func() {
 			c.GetWhitelistRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	return
@@ -180,11 +183,11 @@ func parseGetWhitelistCommand(command configs.Command, mc *discordgo.MessageCrea
 func (c *Commands) GetWhitelistRequest(ctx context.Context, server gcscmodels.Server, getWhitelistSuccess chan GetWhitelistSuccess, getWhitelistError chan GetWhitelistError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	resp, err := c.NitradoService.Client.GetWhitelist(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
+	resp, err := c.NitradoService.GetWhitelist(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
 	if err != nil {
 		getWhitelistError <- GetWhitelistError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
@@ -325,7 +328,7 @@ func (bps *GetWhitelistSuccessOutput) ConvertToEmbedField() (*discordgo.MessageE
 			continue
 		}
 
-		fieldVal += "\n" + player.Name
+		fieldVal += "\n" + discordapi.EscapeCode(player.Name)
 	}
 
 	if fieldVal == "```" {
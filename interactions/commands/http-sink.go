@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/httpsink"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// HTTP Sink actions
+const (
+	HTTPSinkAdd    = "add"
+	HTTPSinkRemove = "remove"
+	HTTPSinkList   = "list"
+)
+
+// recentDeliveries is how many deliveries the list action shows
+const recentDeliveries = 10
+
+// HTTPSinkCommand struct
+type HTTPSinkCommand struct {
+	Params HTTPSinkCommandParams
+}
+
+// HTTPSinkCommandParams struct
+type HTTPSinkCommandParams struct {
+	Action     string
+	URL        string
+	Events     []string
+	EndpointID string
+}
+
+// HTTPSinkEndpointOutput struct
+type HTTPSinkEndpointOutput struct {
+	Endpoint httpsink.Endpoint
+}
+
+// HTTPSinkRemovedOutput struct
+type HTTPSinkRemovedOutput struct {
+	EndpointID string
+}
+
+// HTTPSinkDeliveriesOutput struct
+type HTTPSinkDeliveriesOutput struct {
+	Deliveries []httpsink.Delivery
+}
+
+// HTTPSinkEndpoints func
+func (c *Commands) HTTPSinkEndpoints(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, hsErr := parseHTTPSinkCommand(command, mc)
+	if hsErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *hsErr)
+		return
+	}
+
+	if c.HTTPSink == nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: "HTTP event delivery is not available",
+			Err:     errors.New("http sinks disabled"),
+		})
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "GuildServices"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+	var description string
+
+	switch parsedCommand.Params.Action {
+	case HTTPSinkAdd:
+		endpoint, aeErr := c.HTTPSink.AddEndpoint(ctx, mc.GuildID, parsedCommand.Params.URL, parsedCommand.Params.Events)
+		if aeErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: aeErr.Message,
+				Err:     aeErr,
+			})
+			return
+		}
+
+		// The signing secret is only ever shown in a DM so it can't leak into a guild channel
+		if sErr := c.sendHTTPSinkSecret(ctx, s, mc.Author.ID, endpoint); sErr != nil {
+			c.HTTPSink.RemoveEndpoint(ctx, mc.GuildID, endpoint.ID)
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *sErr)
+			return
+		}
+
+		description = "Endpoint added. The signing secret was sent to you in a DM."
+		embeddableFields = append(embeddableFields, &HTTPSinkEndpointOutput{
+			Endpoint: *endpoint,
+		})
+	case HTTPSinkRemove:
+		removed, reErr := c.HTTPSink.RemoveEndpoint(ctx, mc.GuildID, parsedCommand.Params.EndpointID)
+		if reErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: reErr.Message,
+				Err:     reErr,
+			})
+			return
+		}
+
+		if !removed {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: fmt.Sprintf("No HTTP endpoint found with ID: %s", parsedCommand.Params.EndpointID),
+				Err:     errors.New("endpoint not found"),
+			})
+			return
+		}
+
+		description = "Endpoint removed. It will not receive any more events."
+		embeddableFields = append(embeddableFields, &HTTPSinkRemovedOutput{
+			EndpointID: parsedCommand.Params.EndpointID,
+		})
+	case HTTPSinkList:
+		endpoints, geErr := c.HTTPSink.GetEndpoints(ctx, mc.GuildID)
+		if geErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: geErr.Message,
+				Err:     geErr,
+			})
+			return
+		}
+
+		deliveries, gdErr := c.HTTPSink.GetDeliveries(ctx, mc.GuildID, recentDeliveries)
+		if gdErr != nil {
+			embeddableErrors = append(embeddableErrors, &Error{
+				Message: gdErr.Message,
+				Err:     gdErr,
+			})
+		}
+
+		description = fmt.Sprintf("%d of %d HTTP endpoints registered.", len(endpoints), c.HTTPSink.MaxEndpoints)
+		for _, endpoint := range endpoints {
+			embeddableFields = append(embeddableFields, &HTTPSinkEndpointOutput{
+				Endpoint: endpoint,
+			})
+		}
+
+		if len(deliveries) > 0 {
+			embeddableFields = append(embeddableFields, &HTTPSinkDeliveriesOutput{
+				Deliveries: deliveries,
+			})
+		}
+	}
+
+	c.Output(ctx, mc.ChannelID, discordapi.EmbeddableParams{
+		Title:        command.Name,
+		Description:  description,
+		TitleURL:     c.Config.Bot.DocumentationURL,
+		Footer:       fmt.Sprintf("Executed by %s", mc.Author.Username),
+		ThumbnailURL: c.Config.Bot.OkThumbnail,
+	}, embeddableFields, embeddableErrors)
+}
+
+// sendHTTPSinkSecret DMs the signing secret for a new endpoint to the user who added it
+func (c *Commands) sendHTTPSinkSecret(ctx context.Context, s *discordgo.Session, userID string, endpoint *httpsink.Endpoint) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	dmChannel, dmErr := discordapi.CreateDMChannel(s, userID)
+	if dmErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", dmErr.Err), zap.String("error_message", dmErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+
+		return &Error{
+			Message: "Failed to DM you the signing secret. Allow DMs from server members and try again.",
+			Err:     dmErr.Err,
+		}
+	}
+
+	content := fmt.Sprintf("Signing secret for HTTP endpoint `%s` (%s):\n||`%s`||\n"+
+		"Each request has a `%s` header of `sha256=` followed by the hex HMAC-SHA256 of `<%s>.<body>` using this secret.",
+		endpoint.ID, endpoint.URL, endpoint.Secret, httpsink.HeaderSignature, httpsink.HeaderTimestamp)
+
+	if _, smErr := discordapi.SendMessage(s, dmChannel.ID, &content, nil); smErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", smErr.Err), zap.String("error_message", smErr.Message), zap.Int("status_code", smErr.Code))
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+
+		return &Error{
+			Message: "Failed to DM you the signing secret. Allow DMs from server members and try again.",
+			Err:     smErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// parseHTTPSinkCommand func
+func parseHTTPSinkCommand(command configs.Command, mc *discordgo.MessageCreate) (*HTTPSinkCommand, *Error) {
+	splitContent := strings.Fields(mc.Content)
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	params := HTTPSinkCommandParams{
+		Action: strings.ToLower(splitContent[1]),
+	}
+
+	switch params.Action {
+	case HTTPSinkAdd:
+		if len(splitContent) < 3 {
+			return nil, &Error{
+				Message: "An endpoint URL is required",
+				Err:     errors.New("missing url"),
+			}
+		}
+
+		params.URL = strings.Trim(splitContent[2], "<>")
+
+		if len(splitContent) > 3 {
+			for _, event := range strings.Split(splitContent[3], ",") {
+				if event = strings.ToLower(strings.TrimSpace(event)); event != "" {
+					params.Events = append(params.Events, event)
+				}
+			}
+		}
+	case HTTPSinkRemove:
+		if len(splitContent) != 3 {
+			return nil, &Error{
+				Message: "An endpoint ID is required",
+				Err:     errors.New("missing endpoint id"),
+			}
+		}
+
+		params.EndpointID = splitContent[2]
+	case HTTPSinkList:
+		if len(splitContent) != 2 {
+			return nil, &Error{
+				Message: "List does not take any arguments",
+				Err:     errors.New("invalid number of arguments"),
+			}
+		}
+	default:
+		return nil, &Error{
+			Message: fmt.Sprintf("Unknown action: %s. Use add, remove or list.", splitContent[1]),
+			Err:     errors.New("invalid action"),
+		}
+	}
+
+	return &HTTPSinkCommand{
+		Params: params,
+	}, nil
+}
+
+// ConvertToEmbedField for HTTPSinkEndpointOutput struct
+func (out *HTTPSinkEndpointOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := fmt.Sprintf("%s\nEvents: %s\nAdded: <t:%d:R>", discordapi.EscapeText(out.Endpoint.URL), strings.Join(out.Endpoint.Events, ", "), out.Endpoint.CreatedAt)
+
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Endpoint %s", out.Endpoint.ID),
+		Value:  fieldVal,
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for HTTPSinkRemovedOutput struct
+func (out *HTTPSinkRemovedOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Endpoint %s", out.EndpointID),
+		Value:  "Removed",
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for HTTPSinkDeliveriesOutput struct
+func (out *HTTPSinkDeliveriesOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := ""
+	for _, delivery := range out.Deliveries {
+		status := "✅"
+		detail := fmt.Sprintf("%d", delivery.StatusCode)
+		if !delivery.Success {
+			status = "❌"
+			if delivery.Error != "" {
+				detail = discordapi.TruncateText(discordapi.EscapeText(delivery.Error), 80)
+			}
+		}
+
+		fieldVal += fmt.Sprintf("%s `%s` %s %s after %d attempt(s) <t:%d:R>\n", status, delivery.EndpointID, delivery.EventType, detail, delivery.Attempts, delivery.Timestamp)
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Last %d deliveries", len(out.Deliveries)),
+		Value:  discordapi.TruncateText(fieldVal, discordapi.MaxEmbedFieldValueCharCount),
+		Inline: false,
+	}, nil
+}
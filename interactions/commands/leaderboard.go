@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pvpstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// LeaderboardSize const
+const LeaderboardSize int = 15
+
+// LeaderboardCommand struct
+type LeaderboardCommand struct {
+	Params LeaderboardCommandParams
+}
+
+// LeaderboardCommandParams struct
+type LeaderboardCommandParams struct {
+	Entity   string
+	ServerID int64
+	Period   string
+}
+
+// LeaderboardSuccessOutput struct
+type LeaderboardSuccessOutput struct {
+	Entity string
+	Stats  []pvpstats.Stats
+}
+
+// Leaderboard func
+func (c *Commands) Leaderboard(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, lcErr := parseLeaderboardCommand(command, mc)
+	if lcErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *lcErr)
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "Servers"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	scopeName := "All Servers"
+	if parsedCommand.Params.ServerID != pvpstats.ScopeAllServers {
+		scopeName = ""
+		for _, aServer := range guildFeed.Payload.Guild.Servers {
+			if aServer.NitradoID == parsedCommand.Params.ServerID {
+				scopeName = aServer.Name
+				break
+			}
+		}
+
+		if scopeName == "" {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unable to find server for leaderboard",
+				Err:     errors.New("invalid server id"),
+			})
+			return
+		}
+	}
+
+	periodKey := pvpstats.PeriodKey(parsedCommand.Params.Period, time.Now())
+	stats, lbErr := pvpstats.GetLeaderboard(ctx, c.Cache, c.Config.CacheSettings.PvPStats, mc.GuildID, parsedCommand.Params.ServerID, parsedCommand.Params.Entity, periodKey, LeaderboardSize)
+	if lbErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: lbErr.Message,
+			Err:     lbErr,
+		})
+		return
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	embeddableFields = append(embeddableFields, &LeaderboardSuccessOutput{
+		Entity: parsedCommand.Params.Entity,
+		Stats:  stats,
+	})
+
+	embedParams := discordapi.EmbeddableParams{
+		Title:       fmt.Sprintf("PvP Leaderboard: %s", strings.Title(parsedCommand.Params.Entity)),
+		Description: fmt.Sprintf("%s - %s", scopeName, PeriodDescription(parsedCommand.Params.Period)),
+		TitleURL:    c.Config.Bot.DocumentationURL,
+		Footer:      fmt.Sprintf("Executed by %s", mc.Author.Username),
+	}
+
+	c.Output(ctx, mc.ChannelID, embedParams, embeddableFields, embeddableErrors)
+	return
+}
+
+// parseLeaderboardCommand func
+func parseLeaderboardCommand(command configs.Command, mc *discordgo.MessageCreate) (*LeaderboardCommand, *Error) {
+	splitContent := strings.Split(mc.Content, " ")
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	params := LeaderboardCommandParams{
+		Entity:   pvpstats.EntityPlayers,
+		ServerID: pvpstats.ScopeAllServers,
+		Period:   pvpstats.PeriodWeek,
+	}
+
+	for _, arg := range splitContent[1:] {
+		arg = strings.ToLower(arg)
+
+		if arg == pvpstats.EntityPlayers || arg == pvpstats.EntityTribes {
+			params.Entity = arg
+			continue
+		}
+
+		if pvpstats.IsPeriod(arg) {
+			params.Period = arg
+			continue
+		}
+
+		serverIDInt, sidErr := strconv.ParseInt(arg, 10, 64)
+		if sidErr != nil {
+			return nil, &Error{
+				Message: fmt.Sprintf("Unknown leaderboard option: %s", arg),
+				Err:     errors.New("invalid leaderboard option"),
+			}
+		}
+
+		params.ServerID = serverIDInt
+	}
+
+	return &LeaderboardCommand{
+		Params: params,
+	}, nil
+}
+
+// PeriodDescription func
+func PeriodDescription(period string) string {
+	switch period {
+	case pvpstats.PeriodDay:
+		return "Today (UTC)"
+	case pvpstats.PeriodWeek:
+		return "This Week (UTC)"
+	case pvpstats.PeriodMonth:
+		return "This Month (UTC)"
+	}
+
+	return "All Time"
+}
+
+// ConvertToEmbedField for LeaderboardSuccessOutput struct
+func (out *LeaderboardSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := ""
+
+	for key, stats := range out.Stats {
+		name := discordapi.EscapeText(stats.Name)
+
+		fieldVal += fmt.Sprintf("**%d. %s**\n%d kills / %d deaths (K/D %.2f)", key+1, name, stats.Kills, stats.Deaths, stats.KD())
+		if out.Entity == pvpstats.EntityTribes && stats.TamedKills > 0 {
+			fieldVal += fmt.Sprintf(" - %d tamed dino kills", stats.TamedKills)
+		}
+		fieldVal += "\n"
+	}
+
+	if fieldVal == "" {
+		fieldVal = "No PvP data..."
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "\u200b",
+		Value:  fieldVal + "\u200b",
+		Inline: false,
+	}, nil
+}
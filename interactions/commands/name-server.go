@@ -116,6 +116,8 @@ func (c *Commands) NameServer(ctx context.Context, s *discordgo.Session, mc *dis
 		return
 	}
 
+	guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+
 	if serverResponse.Payload == nil {
 		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
 			Message: "Failed to update server name",
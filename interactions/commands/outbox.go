@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outbox"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// OutboxDeadLettersShown const
+const OutboxDeadLettersShown int = 10
+
+// OutboxCommand struct
+type OutboxCommand struct {
+	Params OutboxCommandParams
+}
+
+// OutboxCommandParams struct
+type OutboxCommandParams struct {
+	Clear bool
+}
+
+// OutboxQueueOutput struct
+type OutboxQueueOutput struct {
+	Depths []outbox.ChannelDepth
+}
+
+// OutboxDeadLetterOutput struct
+type OutboxDeadLetterOutput struct {
+	Message outbox.Message
+}
+
+// OutboxClearedOutput struct
+type OutboxClearedOutput struct {
+	Count int
+}
+
+// Outbox func
+func (c *Commands) Outbox(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, obErr := parseOutboxCommand(command, mc)
+	if obErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *obErr)
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "GuildServices"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	settings := c.Config.CacheSettings.Outbox
+
+	deadCount, cdErr := outbox.CountDeadLetters(ctx, c.Cache, settings, mc.GuildID)
+	if cdErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: cdErr.Message,
+			Err:     cdErr,
+		})
+		return
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	if parsedCommand.Params.Clear {
+		if clErr := outbox.ClearDeadLetters(ctx, c.Cache, settings, mc.GuildID); clErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: clErr.Message,
+				Err:     clErr,
+			})
+			return
+		}
+
+		embeddableFields = append(embeddableFields, &OutboxClearedOutput{
+			Count: deadCount,
+		})
+
+		c.Output(ctx, mc.ChannelID, discordapi.EmbeddableParams{
+			Title:        command.Name,
+			Description:  "Cleared failed deliveries for this Discord server.",
+			TitleURL:     c.Config.Bot.DocumentationURL,
+			Footer:       fmt.Sprintf("Executed by %s", mc.Author.Username),
+			ThumbnailURL: c.Config.Bot.OkThumbnail,
+		}, embeddableFields, embeddableErrors)
+		return
+	}
+
+	depths, gdErr := outbox.GetDepths(ctx, c.Cache, settings, mc.GuildID)
+	if gdErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gdErr.Message,
+			Err:     gdErr,
+		})
+		return
+	}
+
+	deadLetters, dlErr := outbox.GetDeadLetters(ctx, c.Cache, settings, mc.GuildID, OutboxDeadLettersShown)
+	if dlErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: dlErr.Message,
+			Err:     dlErr,
+		})
+		return
+	}
+
+	embeddableFields = append(embeddableFields, &OutboxQueueOutput{
+		Depths: depths,
+	})
+
+	for _, msg := range deadLetters {
+		embeddableErrors = append(embeddableErrors, &OutboxDeadLetterOutput{
+			Message: msg,
+		})
+	}
+
+	c.Output(ctx, mc.ChannelID, discordapi.EmbeddableParams{
+		Title:       command.Name,
+		Description: fmt.Sprintf("Messages waiting to be retried and deliveries that failed permanently. %d failed deliveries stored. All time is in UTC.", deadCount),
+		TitleURL:    c.Config.Bot.DocumentationURL,
+		Footer:      fmt.Sprintf("Executed by %s", mc.Author.Username),
+	}, embeddableFields, embeddableErrors)
+}
+
+// parseOutboxCommand func
+func parseOutboxCommand(command configs.Command, mc *discordgo.MessageCreate) (*OutboxCommand, *Error) {
+	splitContent := strings.Split(mc.Content, " ")
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	if len(splitContent) == 2 && strings.ToLower(splitContent[1]) != "clear" {
+		return nil, &Error{
+			Message: fmt.Sprintf("Unknown option: %s", splitContent[1]),
+			Err:     errors.New("invalid option"),
+		}
+	}
+
+	return &OutboxCommand{
+		Params: OutboxCommandParams{
+			Clear: len(splitContent) == 2,
+		},
+	}, nil
+}
+
+// ConvertToEmbedField for OutboxQueueOutput struct
+func (out *OutboxQueueOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := ""
+	for _, depth := range out.Depths {
+		fieldVal += fmt.Sprintf("<#%s>: %d queued", depth.ChannelID, depth.Depth)
+		if depth.NextAttempt.After(time.Now()) {
+			fieldVal += fmt.Sprintf(" - next attempt %s", depth.NextAttempt.UTC().Format("15:04:05"))
+		}
+		fieldVal += "\n"
+	}
+
+	if fieldVal == "" {
+		fieldVal = "No messages waiting to be retried."
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "Queued Deliveries",
+		Value:  fieldVal + "\u200b",
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for OutboxDeadLetterOutput struct
+func (out *OutboxDeadLetterOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	title := out.Message.Embed.Title
+	if title == "" {
+		title = "Untitled message"
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("Failed: %s", time.Unix(out.Message.FailedAt, 0).UTC().Format("2006-01-02 15:04:05")),
+		Value:  fmt.Sprintf("<#%s> - %s\n%d attempts: %s", out.Message.ChannelID, title, out.Message.Attempts, out.Message.LastError),
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for OutboxClearedOutput struct
+func (out *OutboxClearedOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	return &discordgo.MessageEmbedField{
+		Name:   "Failed Deliveries Cleared",
+		Value:  fmt.Sprintf("Removed %d failed deliveries.", out.Count),
+		Inline: false,
+	}, nil
+}
@@ -0,0 +1,305 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outputwebhook"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// OutputWebhookCommand struct
+type OutputWebhookCommand struct {
+	Params OutputWebhookCommandParams
+}
+
+// OutputWebhookCommandParams struct
+// ServerID is set when the command changes a server's webhook avatar instead of a channel's webhook.
+type OutputWebhookCommandParams struct {
+	ChannelID string
+	Enable    bool
+	ServerID  uint64
+	AvatarURL string
+}
+
+// OutputWebhookSuccessOutput struct
+type OutputWebhookSuccessOutput struct {
+	ChannelID string
+	Enabled   bool
+}
+
+// OutputWebhookAvatarOutput struct
+type OutputWebhookAvatarOutput struct {
+	ServerName string
+	AvatarURL  string
+}
+
+// OutputWebhook func
+func (c *Commands) OutputWebhook(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, owErr := parseOutputWebhookCommand(command, mc)
+	if owErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *owErr)
+		return
+	}
+
+	if !c.Config.CacheSettings.OutputWebhooks.Enabled {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: "Webhook delivery is not available",
+			Err:     errors.New("output webhooks disabled"),
+		})
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "Servers"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	settings := c.Config.CacheSettings.OutputWebhooks
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	if parsedCommand.Params.ServerID != 0 {
+		var serverName string
+		for _, server := range guildFeed.Payload.Guild.Servers {
+			if server.ID == parsedCommand.Params.ServerID {
+				serverName = server.Name
+			}
+		}
+
+		if serverName == "" {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: fmt.Sprintf("Server ID %d does not belong to this guild", parsedCommand.Params.ServerID),
+				Err:     errors.New("invalid server id"),
+			})
+			return
+		}
+
+		if saErr := outputwebhook.SetAvatar(ctx, c.Cache, settings, parsedCommand.Params.ServerID, parsedCommand.Params.AvatarURL); saErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: saErr.Message,
+				Err:     saErr,
+			})
+			return
+		}
+
+		embeddableFields = append(embeddableFields, &OutputWebhookAvatarOutput{
+			ServerName: serverName,
+			AvatarURL:  parsedCommand.Params.AvatarURL,
+		})
+
+		c.Output(ctx, mc.ChannelID, discordapi.EmbeddableParams{
+			Title:        command.Name,
+			Description:  "Webhook avatar updated for the server.",
+			TitleURL:     c.Config.Bot.DocumentationURL,
+			Footer:       fmt.Sprintf("Executed by %s", mc.Author.Username),
+			ThumbnailURL: c.Config.Bot.OkThumbnail,
+		}, embeddableFields, embeddableErrors)
+		return
+	}
+
+	isLogChannel := false
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		for _, oc := range server.ServerOutputChannels {
+			if oc.ChannelID != parsedCommand.Params.ChannelID || oc.OutputChannelTypeID == "players" {
+				continue
+			}
+
+			isLogChannel = true
+		}
+	}
+
+	if !isLogChannel {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: "Channel is not an admin, chat or kill log output channel",
+			Err:     errors.New("invalid output channel"),
+		})
+		return
+	}
+
+	if parsedCommand.Params.Enable {
+		if _, cErr := outputwebhook.Create(ctx, s, c.Cache, settings, mc.GuildID, parsedCommand.Params.ChannelID, c.Config.OutputWebhooks.Name); cErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Failed to create webhook. The bot needs the Manage Webhooks permission in the channel.",
+				Err:     cErr,
+			})
+			return
+		}
+	} else {
+		if dErr := outputwebhook.Disable(ctx, s, c.Cache, settings, mc.GuildID, parsedCommand.Params.ChannelID); dErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: dErr.Message,
+				Err:     dErr,
+			})
+			return
+		}
+	}
+
+	embeddableFields = append(embeddableFields, &OutputWebhookSuccessOutput{
+		ChannelID: parsedCommand.Params.ChannelID,
+		Enabled:   parsedCommand.Params.Enable,
+	})
+
+	c.Output(ctx, mc.ChannelID, discordapi.EmbeddableParams{
+		Title:        command.Name,
+		Description:  "Webhook delivery updated for the output channel.",
+		TitleURL:     c.Config.Bot.DocumentationURL,
+		Footer:       fmt.Sprintf("Executed by %s", mc.Author.Username),
+		ThumbnailURL: c.Config.Bot.OkThumbnail,
+	}, embeddableFields, embeddableErrors)
+}
+
+// parseOutputWebhookCommand parses "<#channel> <on|off>" or "avatar <server_id> <image_url|reset>"
+func parseOutputWebhookCommand(command configs.Command, mc *discordgo.MessageCreate) (*OutputWebhookCommand, *Error) {
+	splitContent := strings.Split(mc.Content, " ")
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	if strings.ToLower(splitContent[1]) == "avatar" {
+		return parseOutputWebhookAvatar(splitContent)
+	}
+
+	if len(splitContent) != 3 {
+		return nil, &Error{
+			Message: "Command expects a channel and on or off",
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	start := strings.Index(splitContent[1], "<#")
+	end := strings.Index(splitContent[1], ">")
+
+	if start == -1 || end == -1 || end <= start+2 {
+		return nil, &Error{
+			Message: "Invalid channel format",
+			Err:     errors.New("invalid channel"),
+		}
+	}
+
+	var enable bool
+	switch strings.ToLower(splitContent[2]) {
+	case "on":
+		enable = true
+	case "off":
+		enable = false
+	default:
+		return nil, &Error{
+			Message: fmt.Sprintf("Unknown option: %s. Use on or off.", splitContent[2]),
+			Err:     errors.New("invalid option"),
+		}
+	}
+
+	return &OutputWebhookCommand{
+		Params: OutputWebhookCommandParams{
+			ChannelID: splitContent[1][start+2 : end],
+			Enable:    enable,
+		},
+	}, nil
+}
+
+// parseOutputWebhookAvatar func
+func parseOutputWebhookAvatar(splitContent []string) (*OutputWebhookCommand, *Error) {
+	if len(splitContent) != 4 {
+		return nil, &Error{
+			Message: "Command expects a server ID and an image URL or reset",
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	serverID, sidErr := strconv.ParseUint(splitContent[2], 10, 64)
+	if sidErr != nil || serverID == 0 {
+		return nil, &Error{
+			Message: "Invalid Server ID",
+			Err:     errors.New("invalid server id"),
+		}
+	}
+
+	avatarURL := strings.Trim(splitContent[3], "<>")
+	if strings.ToLower(avatarURL) == "reset" {
+		avatarURL = ""
+	} else if parsed, pErr := url.Parse(avatarURL); pErr != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, &Error{
+			Message: "Avatar must be an https image URL or reset",
+			Err:     errors.New("invalid avatar url"),
+		}
+	}
+
+	return &OutputWebhookCommand{
+		Params: OutputWebhookCommandParams{
+			ServerID:  serverID,
+			AvatarURL: avatarURL,
+		},
+	}, nil
+}
+
+// ConvertToEmbedField for OutputWebhookSuccessOutput struct
+func (out *OutputWebhookSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := fmt.Sprintf("<#%s> now posts through a webhook named after each server.", out.ChannelID)
+	if !out.Enabled {
+		fieldVal = fmt.Sprintf("<#%s> now posts as the bot and its webhook was removed.", out.ChannelID)
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "Output Webhook",
+		Value:  fieldVal,
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for OutputWebhookAvatarOutput struct
+func (out *OutputWebhookAvatarOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := fmt.Sprintf("%s now posts through webhooks with the avatar %s", discordapi.EscapeText(out.ServerName), out.AvatarURL)
+	if out.AvatarURL == "" {
+		fieldVal = fmt.Sprintf("%s now posts through webhooks with the default avatar.", discordapi.EscapeText(out.ServerName))
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "Webhook Avatar",
+		Value:  fieldVal,
+		Inline: false,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pvpstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// PlayerStatsCommand struct
+type PlayerStatsCommand struct {
+	Params PlayerStatsCommandParams
+}
+
+// PlayerStatsCommandParams struct
+type PlayerStatsCommandParams struct {
+	PlayerName string
+}
+
+// PlayerStatsSuccessOutput struct
+type PlayerStatsSuccessOutput struct {
+	Period string
+	Stats  *pvpstats.Stats
+}
+
+// PlayerStats func
+func (c *Commands) PlayerStats(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	parsedCommand, psErr := parsePlayerStatsCommand(command, mc)
+	if psErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *psErr)
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "GuildServices"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	now := time.Now()
+	found := false
+	for _, period := range []string{pvpstats.PeriodDay, pvpstats.PeriodWeek, pvpstats.PeriodMonth, pvpstats.PeriodAll} {
+		stats, gsErr := pvpstats.GetStats(ctx, c.Cache, c.Config.CacheSettings.PvPStats, mc.GuildID, pvpstats.ScopeAllServers, pvpstats.EntityPlayers, pvpstats.PeriodKey(period, now), parsedCommand.Params.PlayerName)
+		if gsErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: gsErr.Message,
+				Err:     gsErr,
+			})
+			return
+		}
+
+		if stats != nil {
+			found = true
+		}
+
+		embeddableFields = append(embeddableFields, &PlayerStatsSuccessOutput{
+			Period: period,
+			Stats:  stats,
+		})
+	}
+
+	if !found {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: fmt.Sprintf("No PvP stats found for player: %s", parsedCommand.Params.PlayerName),
+			Err:     errors.New("no player stats"),
+		})
+		return
+	}
+
+	embedParams := discordapi.EmbeddableParams{
+		Title:       fmt.Sprintf("PvP Stats: %s", discordapi.EscapeText(parsedCommand.Params.PlayerName)),
+		Description: "Stats across all servers. All time is in UTC.",
+		TitleURL:    c.Config.Bot.DocumentationURL,
+		Footer:      fmt.Sprintf("Executed by %s", mc.Author.Username),
+	}
+
+	c.Output(ctx, mc.ChannelID, embedParams, embeddableFields, embeddableErrors)
+	return
+}
+
+// parsePlayerStatsCommand func
+func parsePlayerStatsCommand(command configs.Command, mc *discordgo.MessageCreate) (*PlayerStatsCommand, *Error) {
+	splitContent := strings.Split(mc.Content, " ")
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	return &PlayerStatsCommand{
+		Params: PlayerStatsCommandParams{
+			PlayerName: strings.Join(splitContent[1:], " "),
+		},
+	}, nil
+}
+
+// ConvertToEmbedField for PlayerStatsSuccessOutput struct
+func (out *PlayerStatsSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := "No PvP data..."
+
+	if out.Stats != nil {
+		fieldVal = fmt.Sprintf("**Kills:** %d\n**Deaths:** %d\n**K/D:** %.2f\n**Wild Dino Deaths:** %d", out.Stats.Kills, out.Stats.Deaths, out.Stats.KD(), out.Stats.WildDeaths)
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   PeriodDescription(out.Period),
+		Value:  fieldVal + "\n\u200b",
+		Inline: true,
+	}, nil
+}
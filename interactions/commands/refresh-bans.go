@@ -17,6 +17,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -149,13 +150,15 @@ func (c *Commands) RefreshBans(ctx context.Context, s *discordgo.Session, mc *di
 	successChannel := make(chan GetBanlistSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan GetBanlistError, len(guildFeed.Payload.Guild.Servers))
 
-	go c.HandleRefreshBansResponses(ctx, s, mc, command, len(servers), syncServers, successChannel, errorChannel)
+	recovery.Go(ctx, "command:"+command.Name, mc.ChannelID, func() {
+		c.HandleRefreshBansResponses(ctx, s, mc, command, len(servers), syncServers, successChannel, errorChannel)
+	})
 
 	for _, stb := range servers {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "command:"+command.Name, func() {
 			c.GetBanlistRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	return
@@ -104,6 +104,8 @@ func (c *Commands) RemoveServer(ctx context.Context, s *discordgo.Session, mc *d
 		return
 	}
 
+	guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+
 	var embeddableFields []discordapi.EmbeddableField
 	var embeddableErrors []discordapi.EmbeddableField
 	embeddableFields = append(embeddableFields, &RemoveServerOutput{
@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/logarchive"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// SearchLogsCommand struct
+type SearchLogsCommand struct {
+	Params SearchLogsCommandParams
+}
+
+// SearchLogsCommandParams struct
+type SearchLogsCommandParams struct {
+	Type   string
+	Player string
+	Text   string
+	Since  time.Time
+	Page   int
+	Export bool
+}
+
+// SearchLogsSuccessOutput struct
+type SearchLogsSuccessOutput struct {
+	Entry      logarchive.Entry
+	ServerName string
+}
+
+// SearchLogs func
+func (c *Commands) SearchLogs(ctx context.Context, s *discordgo.Session, mc *discordgo.MessageCreate, command configs.Command) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if c.LogArchive == nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: "The log archive is not enabled",
+			Err:     errors.New("log archive disabled"),
+		})
+		return
+	}
+
+	parsedCommand, slErr := parseSearchLogsCommand(command, mc, time.Now())
+	if slErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *slErr)
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+	if gfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		})
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, c.Config.Bot.GuildService, "Servers"); vErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		})
+		return
+	}
+
+	if !c.IsApproved(ctx, guildFeed.Payload.Guild, command.Name, mc.Member.Roles) {
+		isAdmin, iaErr := c.IsAdmin(ctx, mc.GuildID, mc.Member.Roles)
+		if iaErr != nil {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, *iaErr)
+			return
+		}
+		if !isAdmin {
+			c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+				Message: "Unauthorized to use this command",
+				Err:     errors.New("user is not authorized"),
+			})
+			return
+		}
+	}
+
+	serverNames := make(map[int64]string)
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		serverNames[server.NitradoID] = server.Name
+	}
+
+	pageSize := c.Config.LogArchive.PageSize
+	query := logarchive.Query{
+		GuildID: mc.GuildID,
+		Type:    parsedCommand.Params.Type,
+		Player:  parsedCommand.Params.Player,
+		Text:    parsedCommand.Params.Text,
+		Since:   parsedCommand.Params.Since,
+		Limit:   pageSize,
+		Offset:  (parsedCommand.Params.Page - 1) * pageSize,
+	}
+
+	if parsedCommand.Params.Export {
+		query.Limit = c.Config.LogArchive.ExportLimit
+		query.Offset = 0
+	}
+
+	entries, total, sErr := c.LogArchive.Search(ctx, query)
+	if sErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: sErr.Message,
+			Err:     sErr,
+		})
+		return
+	}
+
+	if total == 0 {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: "No archived logs matched the search",
+			Err:     errors.New("no search results"),
+		})
+		return
+	}
+
+	if parsedCommand.Params.Export {
+		c.exportSearchLogs(ctx, command, mc, parsedCommand.Params, entries, total, serverNames)
+		return
+	}
+
+	pages := (total + pageSize - 1) / pageSize
+	if parsedCommand.Params.Page > pages {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: fmt.Sprintf("Page %d does not exist. The search has %d pages.", parsedCommand.Params.Page, pages),
+			Err:     errors.New("page out of range"),
+		})
+		return
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	for _, entry := range entries {
+		embeddableFields = append(embeddableFields, &SearchLogsSuccessOutput{
+			Entry:      entry,
+			ServerName: serverNames[entry.ServerID],
+		})
+	}
+
+	embedParams := discordapi.EmbeddableParams{
+		Title:       fmt.Sprintf("Log Search: %s", parsedCommand.Params.Type),
+		Description: fmt.Sprintf("Page %d of %d (%d results). Newest first. All time is in UTC.", parsedCommand.Params.Page, pages, total),
+		TitleURL:    c.Config.Bot.DocumentationURL,
+		Footer:      fmt.Sprintf("Executed by %s", mc.Author.Username),
+	}
+
+	c.Output(ctx, mc.ChannelID, embedParams, embeddableFields, embeddableErrors)
+	return
+}
+
+// exportSearchLogs sends the search results as a text file attachment
+func (c *Commands) exportSearchLogs(ctx context.Context, command configs.Command, mc *discordgo.MessageCreate, params SearchLogsCommandParams, entries []logarchive.Entry, total int, serverNames map[int64]string) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "[%s] [%s] %s\n", time.Unix(entry.Timestamp, 0).UTC().Format("2006-01-02 15:04:05"), serverNames[entry.ServerID], entry.Text)
+	}
+
+	content := fmt.Sprintf("Exported %d of %d matching %s log entries.", len(entries), total, params.Type)
+	fileName := fmt.Sprintf("%s-logs-%s.txt", params.Type, time.Now().UTC().Format("20060102-150405"))
+
+	_, sfErr := discordapi.SendFile(c.Session, mc.ChannelID, &content, fileName, "text/plain", &buf)
+	if sfErr != nil {
+		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
+			Message: sfErr.Message,
+			Err:     sfErr.Err,
+		})
+	}
+}
+
+// parseSearchLogsCommand parses "{type} [player:name] [text:words] [since:24h|7d|YYYY-MM-DD] [page:n] [export]"
+func parseSearchLogsCommand(command configs.Command, mc *discordgo.MessageCreate, now time.Time) (*SearchLogsCommand, *Error) {
+	splitContent := strings.Fields(mc.Content)
+
+	if len(splitContent)-1 < command.MinArgs || len(splitContent)-1 > command.MaxArgs {
+		return nil, &Error{
+			Message: fmt.Sprintf("Command given %d arguments, expects %d to %d arguments.", len(splitContent)-1, command.MinArgs, command.MaxArgs),
+			Err:     errors.New("invalid number of arguments"),
+		}
+	}
+
+	params := SearchLogsCommandParams{
+		Type: strings.ToLower(splitContent[1]),
+		Page: 1,
+	}
+
+	switch params.Type {
+	case logarchive.TypeChat, logarchive.TypeAdmin, logarchive.TypeKills:
+	default:
+		return nil, &Error{
+			Message: fmt.Sprintf("Invalid log type: %s. Must be chat, admin or kills.", splitContent[1]),
+			Err:     errors.New("invalid log type"),
+		}
+	}
+
+	values := make(map[string][]string)
+	currentKey := ""
+	for _, arg := range splitContent[2:] {
+		if strings.ToLower(arg) == "export" {
+			params.Export = true
+			currentKey = ""
+			continue
+		}
+
+		if sep := strings.Index(arg, ":"); sep > 0 {
+			key := strings.ToLower(arg[:sep])
+			switch key {
+			case "player", "text", "since", "page":
+				currentKey = key
+				if arg[sep+1:] != "" {
+					values[key] = append(values[key], arg[sep+1:])
+				}
+				continue
+			}
+		}
+
+		if currentKey == "" {
+			return nil, &Error{
+				Message: fmt.Sprintf("Unexpected argument: %s. Use player:, text:, since:, page: or export.", arg),
+				Err:     errors.New("invalid argument"),
+			}
+		}
+
+		values[currentKey] = append(values[currentKey], arg)
+	}
+
+	params.Player = strings.Join(values["player"], " ")
+	params.Text = strings.Join(values["text"], " ")
+
+	if since, ok := values["since"]; ok {
+		parsedSince, psErr := parseSince(strings.Join(since, " "), now)
+		if psErr != nil {
+			return nil, psErr
+		}
+		params.Since = parsedSince
+	}
+
+	if page, ok := values["page"]; ok {
+		pageNum, aErr := strconv.Atoi(strings.Join(page, ""))
+		if aErr != nil || pageNum < 1 {
+			return nil, &Error{
+				Message: "Page must be a positive number",
+				Err:     errors.New("invalid page"),
+			}
+		}
+		params.Page = pageNum
+	}
+
+	return &SearchLogsCommand{
+		Params: params,
+	}, nil
+}
+
+// parseSince accepts a relative duration in hours or days (24h, 7d) or a UTC date (YYYY-MM-DD)
+func parseSince(since string, now time.Time) (time.Time, *Error) {
+	invalid := &Error{
+		Message: fmt.Sprintf("Invalid since value: %s. Use hours (24h), days (7d) or a date (YYYY-MM-DD).", since),
+		Err:     errors.New("invalid since"),
+	}
+
+	if date, dErr := time.Parse("2006-01-02", since); dErr == nil {
+		return date, nil
+	}
+
+	if len(since) < 2 {
+		return time.Time{}, invalid
+	}
+
+	amount, aErr := strconv.Atoi(since[:len(since)-1])
+	if aErr != nil || amount < 1 {
+		return time.Time{}, invalid
+	}
+
+	switch strings.ToLower(since[len(since)-1:]) {
+	case "h":
+		return now.Add(-time.Duration(amount) * time.Hour), nil
+	case "d":
+		return now.AddDate(0, 0, -amount), nil
+	}
+
+	return time.Time{}, invalid
+}
+
+// ConvertToEmbedField for SearchLogsSuccessOutput struct
+func (out *SearchLogsSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	text := discordapi.EscapeText(out.Entry.Text)
+
+	if runes := []rune(text); len(runes) > 900 {
+		text = string(runes[:900]) + "..."
+	}
+
+	serverName := out.ServerName
+	if serverName == "" {
+		serverName = fmt.Sprint(out.Entry.ServerID)
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("%s - %s", time.Unix(out.Entry.Timestamp, 0).UTC().Format("2006-01-02 15:04:05"), serverName),
+		Value:  text,
+		Inline: false,
+	}, nil
+}
@@ -15,6 +15,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
@@ -113,13 +114,15 @@ func (c *Commands) SearchPlayers(ctx context.Context, s *discordgo.Session, mc *
 	successChannel := make(chan GetPlayersSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan GetPlayersError, len(guildFeed.Payload.Guild.Servers))
 
-	go c.HandleGetPlayersResponses(ctx, s, mc, command, parsedCommand.Params.PartialName, len(servers), successChannel, errorChannel)
+	recovery.Go(ctx, "command:"+command.Name, mc.ChannelID, func() {
+		c.HandleGetPlayersResponses(ctx, s, mc, command, parsedCommand.Params.PartialName, len(servers), successChannel, errorChannel)
+	})
 
 	for _, server := range servers {
 		var aServer gcscmodels.Server = server
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "command:"+command.Name, func() {
 			c.GetPlayers(ctx, aServer, parsedCommand.Params.PartialName, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	return
@@ -238,12 +241,12 @@ Loop:
 func (c *Commands) GetPlayers(ctx context.Context, server gcscmodels.Server, playerName string, getPlayersSuccess chan GetPlayersSuccess, getPlayersError chan GetPlayersError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	players, err := c.NitradoService.Client.SearchPlayers(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName, false, false, true)
+	players, err := c.NitradoService.SearchPlayers(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName, false, false, true)
 	if err != nil {
 		getPlayersError <- GetPlayersError{
-			Message: err.Message(),
+			Message: err.Message,
 			Err: Error{
-				Message: err.Message(),
+				Message: err.Message,
 				Err:     err,
 			},
 		}
@@ -259,10 +262,10 @@ func (c *Commands) GetPlayers(ctx context.Context, server gcscmodels.Server, pla
 
 // ConvertToEmbedField for NameServerOutput struct
 func (out *SearchPlayersSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
-	name := "🔴 " + out.Player.Name
+	name := "🔴 " + discordapi.EscapeText(out.Player.Name)
 
 	if out.Player.Online {
-		name = "🟢 " + out.Player.Name
+		name = "🟢 " + discordapi.EscapeText(out.Player.Name)
 	}
 
 	if name == "" {
@@ -134,6 +134,9 @@ func (c *Commands) Setup(ctx context.Context, s *discordgo.Session, mc *discordg
 		logger := logging.Logger(ctx)
 		logger.Error("error_log")
 
+		// A failed setup may still have linked some servers
+		guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+
 		c.ErrorOutput(ctx, command, mc.Content, mc.ChannelID, Error{
 			Message: "Failed to setup Nitrado Server Manager V2",
 			Err:     errors.New("failed bot setup"),
@@ -141,6 +144,9 @@ func (c *Commands) Setup(ctx context.Context, s *discordgo.Session, mc *discordg
 		return
 	}
 
+	// Dropped once the setup has linked every server, so feeds read while it was running aren't kept
+	guildconfigservice.InvalidateGuildFeed(ctx, c.GuildConfigService, mc.GuildID)
+
 	setupOutput := SetupOutput{
 		NitradoSetup: *nitradoSetupResponse.Payload,
 	}
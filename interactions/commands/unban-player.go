@@ -246,7 +246,7 @@ func (bpc *UnbanPlayerCommandConfirmationOutput) ConvertToEmbedField() (*discord
 	fieldVal := ""
 
 	if len(bpc.Servers) == 1 {
-		name = fmt.Sprintf("%s will be unbanned on %s", bpc.PlayerName, bpc.Servers[0].Name)
+		name = fmt.Sprintf("%s will be unbanned on %s", discordapi.EscapeText(bpc.PlayerName), bpc.Servers[0].Name)
 	} else {
 		name = fmt.Sprintf("%s will be unbanned on %d servers", bpc.PlayerName, len(bpc.Servers))
 	}
@@ -250,7 +250,7 @@ func (bpc *UnwhitelistPlayerCommandConfirmationOutput) ConvertToEmbedField() (*d
 	fieldVal := ""
 
 	if len(bpc.Servers) == 1 {
-		name = fmt.Sprintf("%s will be unwhitelisted on %s", bpc.PlayerName, bpc.Servers[0].Name)
+		name = fmt.Sprintf("%s will be unwhitelisted on %s", discordapi.EscapeText(bpc.PlayerName), bpc.Servers[0].Name)
 	} else {
 		name = fmt.Sprintf("%s will be unwhitelisted on %d servers", bpc.PlayerName, len(bpc.Servers))
 	}
@@ -250,7 +250,7 @@ func (bpc *WhitelistPlayerCommandConfirmationOutput) ConvertToEmbedField() (*dis
 	fieldVal := ""
 
 	if len(bpc.Servers) == 1 {
-		name = fmt.Sprintf("%s will be whitelisted on %s", bpc.PlayerName, bpc.Servers[0].Name)
+		name = fmt.Sprintf("%s will be whitelisted on %s", discordapi.EscapeText(bpc.PlayerName), bpc.Servers[0].Name)
 	} else {
 		name = fmt.Sprintf("%s will be whitelisted on %d servers", bpc.PlayerName, len(bpc.Servers))
 	}
@@ -0,0 +1,303 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcsc/guild_services"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outbox"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outputwebhook"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// welcomePermissions are needed in a channel to post the welcome message there
+const welcomePermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks
+
+// GuildCreate welcomes a guild that has just added the bot with the steps to set it up.
+// Discord also sends a guild create for every guild when a shard connects, so only guilds joined within the welcome window are welcomed.
+func (i *Interactions) GuildCreate(s *discordgo.Session, gc *discordgo.GuildCreate) {
+	if !i.Config.GuildLifecycle.WelcomeEnabled || gc.Guild == nil || gc.Unavailable {
+		return
+	}
+
+	if !i.begin() {
+		return
+	}
+	defer i.inFlight.Done()
+
+	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", uuid.New().String()))
+	defer recovery.Recover(ctx, "guild_create", "")
+
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("guild_id", gc.ID),
+	)
+
+	joinedAt, tErr := gc.JoinedAt.Parse()
+	if tErr != nil || time.Since(joinedAt) > i.Config.GuildLifecycle.WelcomeWindow*time.Second {
+		return
+	}
+
+	if !i.claim(ctx, i.Config.CacheSettings.GuildWelcomes, gc.ID) {
+		return
+	}
+
+	channelID := welcomeChannel(s, gc.Guild)
+	if channelID == "" {
+		newCtx := logging.AddValues(ctx, zap.String("info_message", "No channel to post the welcome message in"))
+		logger := logging.Logger(newCtx)
+		logger.Info("info_log")
+		return
+	}
+
+	if _, smErr := discordapi.SendMessage(s, channelID, nil, i.welcomeEmbed()); smErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", smErr.Err),
+			zap.String("error_message", smErr.Message),
+			zap.Int("status_code", smErr.Code),
+			zap.String("channel_id", channelID),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+}
+
+// GuildDelete disables the bot's service for a guild that removed the bot, which stops the runners posting for it.
+// Running n!activate after adding the bot back enables it again. A guild that is only unavailable during an outage is left alone.
+func (i *Interactions) GuildDelete(s *discordgo.Session, gd *discordgo.GuildDelete) {
+	if gd.Guild == nil || gd.Unavailable {
+		return
+	}
+
+	if !i.begin() {
+		return
+	}
+	defer i.inFlight.Done()
+
+	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", uuid.New().String()))
+	defer recovery.Recover(ctx, "guild_delete", "")
+
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("guild_id", gd.ID),
+	)
+
+	if !i.claim(ctx, i.Config.CacheSettings.CommandDedupe, "guild_delete:"+gd.ID) {
+		return
+	}
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, i.GuildConfigService, gd.ID)
+	if gfErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", gfErr.Err), zap.String("error_message", gfErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, i.Config.Bot.GuildService, "GuildServices"); vErr != nil {
+		// Never activated or already disabled
+		return
+	}
+
+	for _, guildService := range guildFeed.Payload.Guild.GuildServices {
+		if guildService.Name != i.Config.Bot.GuildService || !guildService.Enabled {
+			continue
+		}
+
+		if dgsErr := disableGuildService(ctx, i.GuildConfigService, guildService); dgsErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", dgsErr.Err), zap.String("error_message", dgsErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+			return
+		}
+	}
+
+	newCtx := logging.AddValues(ctx, zap.String("info_message", "Disabled guild service after the bot was removed"))
+	logger := logging.Logger(newCtx)
+	logger.Info("info_log")
+}
+
+// ChannelDelete removes any server output channels using a deleted channel, along with its webhook record and queued output,
+// instead of waiting for a runner to fail to post there
+func (i *Interactions) ChannelDelete(s *discordgo.Session, cd *discordgo.ChannelDelete) {
+	if cd.Channel == nil || cd.GuildID == "" {
+		return
+	}
+
+	if !i.begin() {
+		return
+	}
+	defer i.inFlight.Done()
+
+	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", uuid.New().String()))
+	defer recovery.Recover(ctx, "channel_delete", "")
+
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("guild_id", cd.GuildID),
+		zap.String("channel_id", cd.ID),
+	)
+
+	i.forgetChannel(ctx, cd.GuildID, cd.ID)
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, i.GuildConfigService, cd.GuildID)
+	if gfErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", gfErr.Err), zap.String("error_message", gfErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, i.Config.Bot.GuildService, "Servers"); vErr != nil {
+		return
+	}
+
+	var outputChannels []*gcscmodels.ServerOutputChannel
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		for _, outputChannel := range server.ServerOutputChannels {
+			if outputChannel.ChannelID == cd.ID {
+				outputChannels = append(outputChannels, outputChannel)
+			}
+		}
+	}
+
+	if len(outputChannels) == 0 {
+		return
+	}
+
+	if !i.claim(ctx, i.Config.CacheSettings.CommandDedupe, "channel_delete:"+cd.ID) {
+		return
+	}
+
+	for _, outputChannel := range outputChannels {
+		_, dsocErr := guildconfigservice.DeleteServerOutputChannel(ctx, i.GuildConfigService, cd.GuildID, int64(outputChannel.ID))
+		if dsocErr != nil {
+			newCtx := logging.AddValues(ctx,
+				zap.NamedError("error", dsocErr.Err),
+				zap.String("error_message", dsocErr.Message),
+				zap.Uint64("server_id", outputChannel.ServerID),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+		}
+	}
+}
+
+// forgetChannel drops the webhook record and outbox queue kept for a deleted channel
+func (i *Interactions) forgetChannel(ctx context.Context, guildID string, channelID string) {
+	if i.Config.CacheSettings.OutputWebhooks.Enabled {
+		if fErr := outputwebhook.Forget(ctx, i.Cache, i.Config.CacheSettings.OutputWebhooks, channelID); fErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", fErr.Err), zap.String("error_message", fErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+		}
+	}
+
+	if i.Config.CacheSettings.Outbox.Enabled {
+		if cErr := outbox.Clear(ctx, i.Cache, i.Config.CacheSettings.Outbox, guildID, channelID); cErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", cErr.Err), zap.String("error_message", cErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+		}
+	}
+}
+
+// disableGuildService turns off the bot's service for a guild
+func disableGuildService(ctx context.Context, gcs *guildconfigservice.GuildConfigService, guildService *gcscmodels.GuildService) *Error {
+	guildServiceBody := gcscmodels.UpdateGuildServiceRequest{
+		Enabled: false,
+		GuildID: guildService.GuildID,
+		Name:    guildService.Name,
+	}
+	updateGuildServiceParams := guild_services.NewUpdateGuildServiceParamsWithTimeout(10)
+	updateGuildServiceParams.SetGuild(guildService.GuildID)
+	updateGuildServiceParams.SetGuildServiceID(int64(guildService.ID))
+	updateGuildServiceParams.SetContext(context.Background())
+	updateGuildServiceParams.SetBody(&guildServiceBody)
+
+	if _, ugsErr := gcs.Client.GuildServices.UpdateGuildService(updateGuildServiceParams, gcs.Auth); ugsErr != nil {
+		return &Error{
+			Message: "Failed to disable guild service",
+			Err:     ugsErr,
+		}
+	}
+
+	guildconfigservice.InvalidateGuildFeed(ctx, gcs, guildService.GuildID)
+
+	return nil
+}
+
+// welcomeChannel returns the guild's system channel, or its highest text channel, that the bot can post in
+func welcomeChannel(s *discordgo.Session, guild *discordgo.Guild) string {
+	canPost := func(channelID string) bool {
+		permissions, err := s.State.UserChannelPermissions(s.State.User.ID, channelID)
+		return err == nil && permissions&welcomePermissions == welcomePermissions
+	}
+
+	if guild.SystemChannelID != "" && canPost(guild.SystemChannelID) {
+		return guild.SystemChannelID
+	}
+
+	channels := make([]*discordgo.Channel, 0, len(guild.Channels))
+	for _, channel := range guild.Channels {
+		if channel.Type == discordgo.ChannelTypeGuildText {
+			channels = append(channels, channel)
+		}
+	}
+	sort.Slice(channels, func(a, b int) bool {
+		return channels[a].Position < channels[b].Position
+	})
+
+	for _, channel := range channels {
+		if canPost(channel.ID) {
+			return channel.ID
+		}
+	}
+
+	return ""
+}
+
+// welcomeEmbed lists the configured setup commands in order
+func (i *Interactions) welcomeEmbed() *discordgo.MessageEmbed {
+	var fields []*discordgo.MessageEmbedField
+	for _, name := range i.Config.GuildLifecycle.WelcomeSteps {
+		for _, command := range i.Config.Commands {
+			if command.Name != name || !command.Enabled || len(command.Usage) == 0 {
+				continue
+			}
+
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("%d. %s", len(fields)+1, command.Name),
+				Value: fmt.Sprintf("```\n%s%s\n```\n%s", i.Config.Bot.Prefix, command.Usage[0], command.Description),
+			})
+			break
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Thanks for adding Nitrado Server Manager",
+		Description: "Follow these steps to link your Nitrado servers to this Discord server.",
+		Color:       i.Config.Bot.OkColor,
+		URL:         i.Config.Bot.DocumentationURL,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: i.Config.Bot.OkThumbnail,
+		},
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Setup",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -122,13 +124,15 @@ func (r *Reactions) BanPlayer(ctx context.Context, s *discordgo.Session, mra *di
 	successChannel := make(chan BanSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan BanError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleBanPlayerResponses(ctx, s, mra, command, len(serversToBanOn), successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleBanPlayerResponses(ctx, s, mra, command, len(serversToBanOn), successChannel, errorChannel)
+	})
 
 	for _, stb := range serversToBanOn {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 			r.BanPlayerRequest(ctx, aServer, cbr.PlayerName, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	delete(r.MessagesAwaitingReaction.Messages, mra.MessageID)
@@ -141,17 +145,19 @@ func (r *Reactions) BanPlayer(ctx context.Context, s *discordgo.Session, mra *di
 func (r *Reactions) BanPlayerRequest(ctx context.Context, server gcscmodels.Server, playerName string, banSuccess chan BanSuccess, banError chan BanError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.BanPlayer(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
+	_, err := r.NitradoService.BanPlayer(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
 	if err != nil {
 		banError <- BanError{
 			Server:     server,
-			Message:    err.Message(),
+			Message:    err.Message,
 			Error:      err.Error(),
 			PlayerName: playerName,
 		}
 		return
 	}
 
+	r.RecordModeration(ctx, server, serverstats.ActionBan, playerName)
+
 	banSuccess <- BanSuccess{
 		Server:     server,
 		PlayerName: playerName,
@@ -279,7 +285,7 @@ func (bps *BanPlayerSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbe
 	fieldVal := "This may take up to 5 minutes to take effect."
 
 	return &discordgo.MessageEmbedField{
-		Name:   fmt.Sprintf("%s banned on %d server(s)", bps.PlayerName, len(bps.Servers)),
+		Name:   fmt.Sprintf("%s banned on %d server(s)", discordapi.EscapeText(bps.PlayerName), len(bps.Servers)),
 		Value:  fieldVal,
 		Inline: false,
 	}, nil
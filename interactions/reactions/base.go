@@ -6,12 +6,17 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/nitradoservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outputwebhook"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +27,7 @@ type Reactions struct {
 	Cache                    *cache.Cache
 	GuildConfigService       *guildconfigservice.GuildConfigService
 	NitradoService           *nitradoservice.NitradoService
+	EventBus                 *eventbus.Bus
 	MessagesAwaitingReaction *MessagesAwaitingReaction
 }
 
@@ -53,6 +59,14 @@ func (r *Reactions) IsCommandReaction(ctx context.Context, messageID string, emo
 
 }
 
+// RecordModeration publishes a moderation action issued through the bot for the daily digest and event consumers
+func (r *Reactions) RecordModeration(ctx context.Context, server gcscmodels.Server, action string, playerName string) {
+	r.EventBus.Publish(ctx, server, eventbus.ModerationAction{
+		Action: action,
+		Player: playerName,
+	})
+}
+
 // ExpireMessagesAwaitingReaction func
 func ExpireMessagesAwaitingReaction(messagesAwaitingReaction *MessagesAwaitingReaction) {
 	ticker := time.NewTicker(60 * time.Second)
@@ -77,6 +91,12 @@ func (r *Reactions) Factory(ctx context.Context, s *discordgo.Session, mra *disc
 	logger := logging.Logger(ctx)
 	logger.Info("reaction_log")
 
+	ctx = metrics.TrackOutcome(ctx)
+	defer func() {
+		metrics.Reactions.WithLabelValues(mar.CommandName, metrics.Outcome(metrics.Failed(ctx))).Inc()
+	}()
+	defer recovery.Recover(ctx, "reaction:"+mar.CommandName, mra.ChannelID)
+
 	// TODO: Needs additional data. For example, if it's a BAN command reaction, who are we trying to ban? Maybe a data cache key to get the info stored separately?
 	// var cmr *models.CommandMessageReaction
 	// cacheKey := cmr.CacheKey(r.Config.CacheSettings.CommandMessageReaction.Base, mra.MessageID)
@@ -149,6 +169,8 @@ func (r *Reactions) Factory(ctx context.Context, s *discordgo.Session, mra *disc
 
 // ErrorOutput func
 func (r *Reactions) ErrorOutput(ctx context.Context, content string, channelID string, err Error) ([]*discordgo.Message, *Error) {
+	metrics.MarkFailed(ctx)
+
 	newCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
 	logger := logging.Logger(newCtx)
 	logger.Error("error_log")
@@ -277,3 +299,18 @@ func (e *Error) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi
 		Inline: false,
 	}, nil
 }
+
+// CreateOutputWebhook creates the webhook a log output channel posts through.
+// Failing to create one is logged rather than shown, since output falls back to the bot user.
+func (r *Reactions) CreateOutputWebhook(ctx context.Context, s *discordgo.Session, guildID string, channelID string, outputType string) {
+	if !r.Config.CacheSettings.OutputWebhooks.Enabled || outputType == "players" {
+		return
+	}
+
+	_, cErr := outputwebhook.Create(ctx, s, r.Cache, r.Config.CacheSettings.OutputWebhooks, guildID, channelID, r.Config.OutputWebhooks.Name)
+	if cErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", cErr.Err), zap.String("error_message", cErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nitrado_service_v2_client "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
@@ -137,13 +139,15 @@ func (r *Reactions) ClearWhitelist(ctx context.Context, s *discordgo.Session, mr
 	successChannel := make(chan GetWhitelistSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan GetWhitelistError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleGetWhitelistResponse(ctx, s, mra, command, len(servers), successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleGetWhitelistResponse(ctx, s, mra, command, len(servers), successChannel, errorChannel)
+	})
 
 	for _, stb := range servers {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 			r.GetWhitelistRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	delete(r.MessagesAwaitingReaction.Messages, mra.MessageID)
@@ -156,11 +160,11 @@ func (r *Reactions) ClearWhitelist(ctx context.Context, s *discordgo.Session, mr
 func (r *Reactions) GetWhitelistRequest(ctx context.Context, server gcscmodels.Server, getWhitelistSuccess chan GetWhitelistSuccess, getWhitelistError chan GetWhitelistError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	whitelist, err := r.NitradoService.Client.GetWhitelist(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
+	whitelist, err := r.NitradoService.GetWhitelist(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), false)
 	if err != nil {
 		getWhitelistError <- GetWhitelistError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
@@ -239,15 +243,17 @@ func (r *Reactions) ClearWhitelistOnServers(ctx context.Context, s *discordgo.Se
 	successChannel := make(chan ClearWhitelistSuccess, totalPlayers)
 	errorChannel := make(chan ClearWhitelistError, totalPlayers)
 
-	go r.HandleClearWhitelistResponse(ctx, s, mra, command, totalPlayers, serverErrors, successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleClearWhitelistResponse(ctx, s, mra, command, totalPlayers, serverErrors, successChannel, errorChannel)
+	})
 
 	for _, sw := range serverWhitelists {
 		var server gcscmodels.Server = sw.Server
 		for _, aPlayer := range sw.Players {
 			var player nitrado_service_v2_client.Player = aPlayer
-			wp.Submit(func() {
+			wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 				r.ClearWhitelistRequest(ctx, server, player, successChannel, errorChannel)
-			})
+			}))
 		}
 	}
 
@@ -258,16 +264,18 @@ func (r *Reactions) ClearWhitelistOnServers(ctx context.Context, s *discordgo.Se
 func (r *Reactions) ClearWhitelistRequest(ctx context.Context, server gcscmodels.Server, player nitrado_service_v2_client.Player, clearWhitelistSuccess chan ClearWhitelistSuccess, clearWhitelistError chan ClearWhitelistError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.UnwhitelistPlayer(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), player.Name)
+	_, err := r.NitradoService.UnwhitelistPlayer(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), player.Name)
 	if err != nil {
 		clearWhitelistError <- ClearWhitelistError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
 	}
 
+	r.RecordModeration(ctx, server, serverstats.ActionUnwhitelist, player.Name)
+
 	clearWhitelistSuccess <- ClearWhitelistSuccess{
 		Server: server,
 		Player: player,
@@ -130,6 +130,7 @@ func (r *Reactions) CreateChannels(ctx context.Context, s *discordgo.Session, mr
 				errorOutput.ChannelNames = append(errorOutput.ChannelNames, ccr.AdminChannelName)
 			} else {
 				successOutput.Channels = append(successOutput.Channels, newChannel)
+				r.CreateOutputWebhook(ctx, s, mra.GuildID, newChannel.ID, "admin")
 			}
 		}
 	}
@@ -162,6 +163,7 @@ func (r *Reactions) CreateChannels(ctx context.Context, s *discordgo.Session, mr
 				errorOutput.ChannelNames = append(errorOutput.ChannelNames, ccr.ChatChannelName)
 			} else {
 				successOutput.Channels = append(successOutput.Channels, newChannel)
+				r.CreateOutputWebhook(ctx, s, mra.GuildID, newChannel.ID, "chat")
 			}
 		}
 	}
@@ -194,6 +196,7 @@ func (r *Reactions) CreateChannels(ctx context.Context, s *discordgo.Session, mr
 				errorOutput.ChannelNames = append(errorOutput.ChannelNames, ccr.KillsChannelName)
 			} else {
 				successOutput.Channels = append(successOutput.Channels, newChannel)
+				r.CreateOutputWebhook(ctx, s, mra.GuildID, newChannel.ID, "kills")
 			}
 		}
 	}
@@ -14,6 +14,7 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -162,15 +163,17 @@ func (r *Reactions) RefreshBans(ctx context.Context, s *discordgo.Session, mra *
 	successChannel := make(chan RefreshBansSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan RefreshBanError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleRefreshBansResponses(ctx, s, mra, command, totalBans, messageID, startTime, successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleRefreshBansResponses(ctx, s, mra, command, totalBans, messageID, startTime, successChannel, errorChannel)
+	})
 
 	for _, sb := range serversBans {
 		for _, aPlayer := range sb.Bans {
 			var player string = aPlayer
 			var aServer gcscmodels.Server = sb.Server
-			wp.Submit(func() {
+			wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 				r.RefreshBansRequest(ctx, aServer, player, successChannel, errorChannel)
-			})
+			}))
 		}
 	}
 
@@ -182,11 +185,11 @@ func (r *Reactions) RefreshBans(ctx context.Context, s *discordgo.Session, mra *
 func (r *Reactions) RefreshBansRequest(ctx context.Context, server gcscmodels.Server, playerName string, banSuccess chan RefreshBansSuccess, banError chan RefreshBanError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.BanPlayer(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
+	_, err := r.NitradoService.BanPlayer(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
 	if err != nil {
 		banError <- RefreshBanError{
 			Server:     server,
-			Message:    err.Message(),
+			Message:    err.Message,
 			Error:      err.Error(),
 			PlayerName: playerName,
 		}
@@ -12,8 +12,10 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -118,13 +120,15 @@ func (r *Reactions) RestartServer(ctx context.Context, s *discordgo.Session, mra
 	successChannel := make(chan RestartSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan RestartError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleRestartServerResponses(ctx, s, mra, command, len(serversToRestartOn), successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleRestartServerResponses(ctx, s, mra, command, len(serversToRestartOn), successChannel, errorChannel)
+	})
 
 	for _, stb := range serversToRestartOn {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 			r.RestartServerRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	delete(r.MessagesAwaitingReaction.Messages, mra.MessageID)
@@ -137,16 +141,20 @@ func (r *Reactions) RestartServer(ctx context.Context, s *discordgo.Session, mra
 func (r *Reactions) RestartServerRequest(ctx context.Context, server gcscmodels.Server, restartSuccess chan RestartSuccess, restartError chan RestartError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.RestartGameserver(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), "Restart executed by Nitrado Server Manager V2", "")
+	_, err := r.NitradoService.RestartGameserver(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), "Restart executed by Nitrado Server Manager V2", "")
 	if err != nil {
 		restartError <- RestartError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
 	}
 
+	r.EventBus.Publish(ctx, server, eventbus.ServerControl{
+		Action: eventbus.ActionRestart,
+	})
+
 	restartSuccess <- RestartSuccess{
 		Server: server,
 	}
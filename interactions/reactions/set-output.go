@@ -46,9 +46,11 @@ func (r *Reactions) SetOutput(ctx context.Context, s *discordgo.Session, mra *di
 	var newOutputChannel *gcscmodels.ServerOutputChannel
 	var oldOutputChannel *gcscmodels.ServerOutputChannel
 	var channelType string
+	var outputType string
 	switch mra.Emoji.ID {
 	case r.Config.Reactions["set_output_admin"].ID:
 		channelType = "Admin Log"
+		outputType = "admin"
 		if reactionModel.ServerOutputChannelIDAdmin != 0 {
 			soc, socErr := guildconfigservice.GetServerOutputChannel(ctx, r.GuildConfigService, mra.GuildID, reactionModel.ServerOutputChannelIDAdmin)
 			if socErr != nil {
@@ -82,6 +84,7 @@ func (r *Reactions) SetOutput(ctx context.Context, s *discordgo.Session, mra *di
 		}
 	case r.Config.Reactions["set_output_chat"].ID:
 		channelType = "Chat Log"
+		outputType = "chat"
 		if reactionModel.ServerOutputChannelIDChat != 0 {
 			soc, socErr := guildconfigservice.GetServerOutputChannel(ctx, r.GuildConfigService, mra.GuildID, reactionModel.ServerOutputChannelIDChat)
 			if socErr != nil {
@@ -115,6 +118,7 @@ func (r *Reactions) SetOutput(ctx context.Context, s *discordgo.Session, mra *di
 		}
 	case r.Config.Reactions["set_output_players"].ID:
 		channelType = "Online Players"
+		outputType = "players"
 		if reactionModel.ServerOutputChannelIDPlayers != 0 {
 			soc, socErr := guildconfigservice.GetServerOutputChannel(ctx, r.GuildConfigService, mra.GuildID, reactionModel.ServerOutputChannelIDPlayers)
 			if socErr != nil {
@@ -148,6 +152,7 @@ func (r *Reactions) SetOutput(ctx context.Context, s *discordgo.Session, mra *di
 		}
 	case r.Config.Reactions["set_output_kill"].ID:
 		channelType = "Kill Log"
+		outputType = "kills"
 		if reactionModel.ServerOutputChannelIDKills != 0 {
 			soc, socErr := guildconfigservice.GetServerOutputChannel(ctx, r.GuildConfigService, mra.GuildID, reactionModel.ServerOutputChannelIDKills)
 			if socErr != nil {
@@ -195,6 +200,8 @@ func (r *Reactions) SetOutput(ctx context.Context, s *discordgo.Session, mra *di
 		return
 	}
 
+	r.CreateOutputWebhook(ctx, s, mra.GuildID, newOutputChannel.ChannelID, outputType)
+
 	successOutput := SetOutputSuccessOutput{
 		NewChannelID: newOutputChannel.ChannelID,
 	}
@@ -12,8 +12,10 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -118,13 +120,15 @@ func (r *Reactions) StopServer(ctx context.Context, s *discordgo.Session, mra *d
 	successChannel := make(chan StopSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan StopError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleStopServerResponses(ctx, s, mra, command, len(serversToStopOn), successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleStopServerResponses(ctx, s, mra, command, len(serversToStopOn), successChannel, errorChannel)
+	})
 
 	for _, stb := range serversToStopOn {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 			r.StopServerRequest(ctx, aServer, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	delete(r.MessagesAwaitingReaction.Messages, mra.MessageID)
@@ -137,16 +141,20 @@ func (r *Reactions) StopServer(ctx context.Context, s *discordgo.Session, mra *d
 func (r *Reactions) StopServerRequest(ctx context.Context, server gcscmodels.Server, stopSuccess chan StopSuccess, stopError chan StopError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.StopGameserver(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), "Stop executed by Nitrado Server Manager V2", "")
+	_, err := r.NitradoService.StopGameserver(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), "Stop executed by Nitrado Server Manager V2", "")
 	if err != nil {
 		stopError <- StopError{
 			Server:  server,
-			Message: err.Message(),
+			Message: err.Message,
 			Error:   err.Error(),
 		}
 		return
 	}
 
+	r.EventBus.Publish(ctx, server, eventbus.ServerControl{
+		Action: eventbus.ActionStop,
+	})
+
 	stopSuccess <- StopSuccess{
 		Server: server,
 	}
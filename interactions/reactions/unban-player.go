@@ -13,7 +13,9 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -122,13 +124,15 @@ func (r *Reactions) UnbanPlayer(ctx context.Context, s *discordgo.Session, mra *
 	successChannel := make(chan UnbanSuccess, len(guildFeed.Payload.Guild.Servers))
 	errorChannel := make(chan UnbanError, len(guildFeed.Payload.Guild.Servers))
 
-	go r.HandleUnbanPlayerResponses(ctx, s, mra, command, len(serversToUnbanOn), successChannel, errorChannel)
+	recovery.Go(ctx, "reaction:"+command.Name, mra.ChannelID, func() {
+		r.HandleUnbanPlayerResponses(ctx, s, mra, command, len(serversToUnbanOn), successChannel, errorChannel)
+	})
 
 	for _, stb := range serversToUnbanOn {
 		var aServer gcscmodels.Server = stb
-		wp.Submit(func() {
+		wp.Submit(recovery.Wrap(ctx, "reaction:"+command.Name, func() {
 			r.UnbanPlayerRequest(ctx, aServer, cbr.PlayerName, successChannel, errorChannel)
-		})
+		}))
 	}
 
 	delete(r.MessagesAwaitingReaction.Messages, mra.MessageID)
@@ -141,17 +145,19 @@ func (r *Reactions) UnbanPlayer(ctx context.Context, s *discordgo.Session, mra *
 func (r *Reactions) UnbanPlayerRequest(ctx context.Context, server gcscmodels.Server, playerName string, banSuccess chan UnbanSuccess, banError chan UnbanError) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	_, err := r.NitradoService.Client.UnbanPlayer(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
+	_, err := r.NitradoService.UnbanPlayer(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), playerName)
 	if err != nil {
 		banError <- UnbanError{
 			Server:     server,
-			Message:    err.Message(),
+			Message:    err.Message,
 			Error:      err.Error(),
 			PlayerName: playerName,
 		}
 		return
 	}
 
+	r.RecordModeration(ctx, server, serverstats.ActionUnban, playerName)
+
 	banSuccess <- UnbanSuccess{
 		Server:     server,
 		PlayerName: playerName,
@@ -278,7 +284,7 @@ func (bps *UnbanPlayerSuccess) ConvertToEmbedField() (*discordgo.MessageEmbedFie
 	fieldVal := "This may take up to 5 minutes to take effect."
 
 	return &discordgo.MessageEmbedField{
-		Name:   fmt.Sprintf("%s unbanned on %d server(s)", bps.PlayerName, len(bps.Servers)),
+		Name:   fmt.Sprintf("%s unbanned on %d server(s)", discordapi.EscapeText(bps.PlayerName), len(bps.Servers)),
 		Value:  fieldVal,
 		Inline: false,
 	}, nil
@@ -2,19 +2,33 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"time"
+	_ "time/tzdata"
 
-	"github.com/bwmarrin/discordgo"
 	"github.com/caarlos0/env"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/controllers"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/interactions"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/routes"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/runners"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventstream"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/httpsink"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/jobqueue"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/leader"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/livefeed"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/logarchive"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/nitradoservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pollschedule"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/shards"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/lifecycle"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +41,8 @@ type Environment struct {
 	NitradoServiceToken     string `env:"NITRADO_SERVICE_TOKEN,required"`
 	GuildConfigServiceToken string `env:"GUILD_CONFIG_SERVICE_TOKEN,required"`
 	BasePath                string `env:"BASE_PATH"`
+	ShardID                 int    `env:"SHARD_ID" envDefault:"-1"`
+	ShardCount              int    `env:"SHARD_COUNT" envDefault:"-1"`
 	Migrate                 bool   `env:"MIGRATE"`
 }
 
@@ -46,53 +62,94 @@ func main() {
 
 	config := configs.GetConfig(ctx, environment.Environment)
 	cache := InitCache(ctx, config)
-	guildConfigService := guildconfigservice.InitService(ctx, config, environment.GuildConfigServiceToken)
+	guildConfigService := guildconfigservice.InitService(ctx, config, cache, environment.GuildConfigServiceToken)
 	nitradoService := nitradoservice.InitService(ctx, config, environment.NitradoServiceToken)
+	logArchive := logarchive.InitArchive(ctx, config, cache)
 
-	// Instantiate Discord client
-	dg, discErr := discordgo.New("Bot " + environment.DiscordToken)
-	if discErr != nil {
-		ctx = logging.AddValues(ctx, zap.NamedError("error", discErr), zap.String("error_message", "Failed to create Discord client"))
+	// Instantiate a Discord client for each shard this process runs
+	shardManager, smErr := shards.InitManager(config, environment.DiscordToken, environment.ShardID, environment.ShardCount)
+	if smErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", smErr.Err), zap.String("error_message", smErr.Message))
 		logger := logging.Logger(ctx)
 		logger.Fatal("error_log")
 	}
+	dg := shardManager.Primary()
 
-	defer dg.Close()
+	recovery.Init(config, &discordapi.PanicReporter{
+		Session: dg,
+		Config:  config,
+	})
 
-	// Open a websocket connection to Discord and begin listening.
-	openErr := dg.Open()
-	if openErr != nil {
-		ctx = logging.AddValues(ctx, zap.NamedError("error", openErr), zap.String("error_message", "Failed to open Discord web socket"))
+	ctx = logging.AddValues(ctx, zap.String("shards", shardManager.Scope("discord")))
+
+	// Open a websocket connection to Discord for each shard and begin listening.
+	if openErr := shardManager.Open(); openErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", openErr.Err), zap.String("error_message", openErr.Message))
 		logger := logging.Logger(ctx)
 		logger.Fatal("error_log")
 	}
 
+	shardManager.StartPublishing(ctx, cache, config.CacheSettings.ShardGuilds, config.Sharding.PublishInterval*time.Second)
+
+	httpSink := httpsink.InitSink(config, cache)
+	liveFeed := livefeed.InitHub(config, cache)
+	if lfErr := liveFeed.Start(ctx); lfErr != nil {
+		// Connections still get the events polled by this replica
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", lfErr.Err), zap.String("error_message", lfErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+	eventStream := eventstream.InitStream(config, cache)
+	eventBus := eventbus.NewBus(config)
+	elector := leader.InitElector(config, cache, shardManager.Scope(config.LeaderElection.Key))
+
 	comm := interactions.Interactions{
 		Session:            dg,
+		Shards:             shardManager,
 		Config:             config,
 		Cache:              cache,
 		GuildConfigService: guildConfigService,
 		NitradoService:     nitradoService,
+		LogArchive:         logArchive,
+		HTTPSink:           httpSink,
+		EventBus:           eventBus,
 	}
 
 	comm.SetupHandlers()
 
+	scheduler := discordapi.NewScheduler(dg, config.DiscordScheduler.Workers)
+	scheduler.Start()
+
 	run := runners.Runners{
 		Session:            dg,
+		Shards:             shardManager,
+		Scheduler:          scheduler,
 		Config:             config,
 		Cache:              cache,
 		GuildConfigService: guildConfigService,
 		NitradoService:     nitradoService,
+		LogArchive:         logArchive,
+		HTTPSink:           httpSink,
+		LiveFeed:           liveFeed,
+		EventStream:        eventStream,
+		EventBus:           eventBus,
+		Leader:             elector,
+		LogsJobs:           jobqueue.InitQueue(config, cache, shardManager.Scope("logs")),
+		PlayersJobs:        jobqueue.InitQueue(config, cache, shardManager.Scope("players")),
+		LogsSchedule:       pollschedule.InitSchedule(config, cache, shardManager.Scope("logs"), config.Runners.Logs.Frequency*time.Second),
 	}
 
+	elector.Start(ctx)
 	run.StartRunners()
 
 	router := routes.GetRouter(ctx)
 	controller := controllers.Controller{
 		Config:             config,
 		Cache:              cache,
-		DiscordSession:     dg,
+		Shards:             shardManager,
 		GuildConfigService: guildConfigService,
+		LiveFeed:           liveFeed,
+		EventBus:           eventBus,
 	}
 
 	r := routes.Router{
@@ -102,8 +159,22 @@ func main() {
 		Controller:   &controller,
 	}
 
-	routes.AddRoutes(ctx, router, r)
-
+	server := routes.AddRoutes(ctx, router, r)
+
+	// Stop taking new work first, then drain what is queued behind it before closing the connections
+	lm := lifecycle.NewManager(config)
+	lm.OnShutdown("interactions", comm.Stop)
+	lm.OnShutdown("runners", run.Stop)
+	lm.OnShutdown("leader_election", elector.Resign)
+	lm.OnShutdown("event_bus", eventBus.Close)
+	lm.OnShutdown("http_sink", httpSink.Close)
+	lm.OnShutdown("discord_scheduler", scheduler.Drain)
+	lm.OnShutdown("http_server", server.Shutdown)
+	lm.OnShutdown("live_feed", liveFeed.Close)
+	lm.OnShutdown("guild_feed_cache", guildConfigService.Feeds.Close)
+	lm.OnShutdown("discord_sessions", shardManager.Close)
+
+	lm.Wait(ctx)
 }
 
 // InitCache initializes the Redis cache
@@ -119,5 +190,6 @@ func InitCache(ctx context.Context, config *configs.Config) *cache.Cache {
 
 	return &cache.Cache{
 		Client: pool,
+		Addr:   fmt.Sprintf("%s:%d", config.Redis.Host, config.Redis.Port),
 	}
 }
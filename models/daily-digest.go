@@ -0,0 +1,19 @@
+package models
+
+import "fmt"
+
+// DailyDigest struct
+type DailyDigest struct {
+	GuildID   string  `json:"guild_id"`
+	Channel   Channel `json:"channel"`
+	Time      string  `json:"time"`
+	Timezone  string  `json:"timezone"`
+	Enabled   bool    `json:"enabled"`
+	LastSent  string  `json:"last_sent"`
+	CreatedBy *User   `json:"created_by"`
+}
+
+// CacheKey func
+func (dd *DailyDigest) CacheKey(base, guildID string) string {
+	return fmt.Sprintf("%s:%s", base, guildID)
+}
@@ -0,0 +1,82 @@
+package models
+
+import "gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+
+// Game event types
+const (
+	GameEventChat       = "chat"
+	GameEventAdmin      = "admin"
+	GameEventKill       = "kill"
+	GameEventJoin       = "join"
+	GameEventLeave      = "leave"
+	GameEventModeration = "moderation"
+	GameEventRestart    = "restart"
+	GameEventStop       = "stop"
+)
+
+// GameEventTypes lists every game event type
+var GameEventTypes = []string{GameEventChat, GameEventAdmin, GameEventKill, GameEventJoin, GameEventLeave, GameEventModeration, GameEventRestart, GameEventStop}
+
+// GameEventVersions is the current schema version of each event type.
+// Bump a type's version whenever its data changes shape and add a new file under schemas/events.
+var GameEventVersions = map[string]int{
+	GameEventChat:       1,
+	GameEventAdmin:      1,
+	GameEventKill:       1,
+	GameEventJoin:       1,
+	GameEventLeave:      1,
+	GameEventModeration: 1,
+	GameEventRestart:    1,
+	GameEventStop:       1,
+}
+
+// GameEvent struct
+// Something that happened on a server, as published to HTTP endpoints and live feeds.
+type GameEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Version    int         `json:"version"`
+	GuildID    string      `json:"guild_id"`
+	ServerID   int64       `json:"server_id"`
+	ServerName string      `json:"server_name"`
+	Timestamp  int64       `json:"timestamp"`
+	Data       interface{} `json:"data"`
+}
+
+// PlayerEventData struct
+// Data for join and leave events.
+type PlayerEventData struct {
+	Name string `json:"name"`
+}
+
+// ModerationEventData struct
+// Data for moderation events.
+type ModerationEventData struct {
+	Action string `json:"action"`
+	Player string `json:"player"`
+}
+
+// NewGameEvent creates an event for a server at the current schema version of its type
+func NewGameEvent(id string, eventType string, server gcscmodels.Server, timestamp int64, data interface{}) GameEvent {
+	return GameEvent{
+		ID:         id,
+		Type:       eventType,
+		Version:    GameEventVersions[eventType],
+		GuildID:    server.GuildID,
+		ServerID:   server.NitradoID,
+		ServerName: server.Name,
+		Timestamp:  timestamp,
+		Data:       data,
+	}
+}
+
+// IsGameEventType reports whether a string is a known game event type
+func IsGameEventType(eventType string) bool {
+	for _, val := range GameEventTypes {
+		if val == eventType {
+			return true
+		}
+	}
+
+	return false
+}
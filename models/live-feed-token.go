@@ -0,0 +1,21 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// LiveFeedToken struct
+// A token lets a browser open one guild's live feed without the Service-Token header.
+type LiveFeedToken struct {
+	Token     string `json:"token"`
+	GuildID   string `json:"guild_id"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// CacheKey is keyed by a hash of the token so tokens can't be read back out of Redis
+func (lft *LiveFeedToken) CacheKey(base, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%s:%s", base, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+)
+
+// LogCheckpoint struct
+// Timestamp is the newest delivered log time and Hashes identify the entries delivered at exactly that time.
+// Pending holds entries Discord did not accept so they can be retried on the next run.
+// Queued lists, in order, the embeds waiting in the outbox; their entries stay pending until the outbox delivers them.
+type LogCheckpoint struct {
+	Timestamp    int64            `json:"timestamp"`
+	Hashes       []string         `json:"hashes"`
+	PendingAdmin []nsv2.AdminLog  `json:"pending_admin,omitempty"`
+	PendingChat  []nsv2.PlayerLog `json:"pending_chat,omitempty"`
+	PendingKills []nsv2.KillLog   `json:"pending_kills,omitempty"`
+	Queued       []QueuedLogs     `json:"queued,omitempty"`
+}
+
+// QueuedLogs struct
+// The log entries completed by an embed in the outbox, identified as they are in the checkpoint
+type QueuedLogs struct {
+	OutboxID   string   `json:"outbox_id"`
+	Timestamps []int64  `json:"timestamps"`
+	Hashes     []string `json:"hashes"`
+}
+
+// CacheKey func
+func (lc *LogCheckpoint) CacheKey(base string, guildID string, serverID int64, logType string) string {
+	return fmt.Sprintf("%s:%s:%d:%s", base, guildID, serverID, logType)
+}
+
+// Delivered reports whether a log entry is at or behind the checkpoint
+func (lc *LogCheckpoint) Delivered(timestamp int64, hash string) bool {
+	if timestamp == 0 {
+		return false
+	}
+
+	if timestamp < lc.Timestamp {
+		return true
+	}
+
+	if timestamp > lc.Timestamp {
+		return false
+	}
+
+	for _, val := range lc.Hashes {
+		if val == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Advance moves the checkpoint past a delivered log entry
+func (lc *LogCheckpoint) Advance(timestamp int64, hash string) {
+	if timestamp > lc.Timestamp {
+		lc.Timestamp = timestamp
+		lc.Hashes = []string{hash}
+		return
+	}
+
+	if timestamp == lc.Timestamp {
+		lc.Hashes = append(lc.Hashes, hash)
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OnlinePlayers struct
+// The players seen online on a server by the last poll.
+type OnlinePlayers struct {
+	Players []string `json:"players"`
+}
+
+// CacheKey func
+func (op *OnlinePlayers) CacheKey(base string, guildID string, serverID int64) string {
+	return fmt.Sprintf("%s:%s:%d", base, guildID, serverID)
+}
+
+// Changes returns the players that joined and left between the stored poll and the current one
+func (op *OnlinePlayers) Changes(current []string) ([]string, []string) {
+	wasOnline := nameSet(op.Players)
+	isOnline := nameSet(current)
+
+	var joined []string
+	for _, name := range current {
+		if !wasOnline[strings.ToLower(name)] {
+			joined = append(joined, name)
+		}
+	}
+
+	var left []string
+	for _, name := range op.Players {
+		if !isOnline[strings.ToLower(name)] {
+			left = append(left, name)
+		}
+	}
+
+	return joined, left
+}
+
+// nameSet func
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+
+	return set
+}
@@ -0,0 +1,18 @@
+package models
+
+import "fmt"
+
+// OutputWebhook struct
+// Enabled output channels post through the webhook instead of as the bot user.
+type OutputWebhook struct {
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	WebhookID string `json:"webhook_id"`
+	Token     string `json:"token"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// CacheKey func
+func (ow *OutputWebhook) CacheKey(base string, channelID string) string {
+	return fmt.Sprintf("%s:%s", base, channelID)
+}
@@ -3,16 +3,34 @@ package routes
 import (
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/gorilla/mux"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/controllers"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
 	"go.uber.org/zap"
 )
 
 // Authentication struct
+// VerifyGuildToken checks a request's guild token for the guild in its path.
 type Authentication struct {
-	ServiceToken string
-	BasePath     string
+	ServiceToken     string
+	BasePath         string
+	VerifyGuildToken func(r *http.Request, guildID string) bool
+}
+
+// publicPaths can be requested without a Service-Token header.
+// /metrics is left out since it reveals bot usage and errors; scrapers send the token like any other client.
+var publicPaths = map[string]bool{
+	"/status":  true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// guildTokenRoutes accept a token for the guild in their path in place of the Service-Token header,
+// since browsers can't set headers on a WebSocket handshake
+var guildTokenRoutes = map[string]bool{
+	"live_feed": true,
 }
 
 // AuthenticationMiddleware verifies the Service-Token header is set and authorized for access to the API
@@ -22,8 +40,14 @@ func (m Authentication) AuthenticationMiddleware(next http.Handler) http.Handler
 		ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
 		serviceTokenHeader := r.Header.Get("Service-Token")
-		if r.URL.Path == m.BasePath+"/status" {
+		if publicPaths[strings.TrimPrefix(r.URL.Path, m.BasePath)] {
 			next.ServeHTTP(w, r)
+		} else if serviceTokenHeader == "" && m.isGuildTokenRoute(r) {
+			if m.VerifyGuildToken(r, mux.Vars(r)["guild_id"]) {
+				next.ServeHTTP(w, r)
+			} else {
+				controllers.Error(ctx, w, "A Service-Token header or a valid token for the guild must be sent with request", errors.New("Invalid guild token"), http.StatusUnauthorized)
+			}
 		} else if serviceTokenHeader == "" {
 			controllers.Error(ctx, w, "A Service-Token header must be set for all routes", errors.New("Missing Service-Token header"), http.StatusUnauthorized)
 		} else if serviceTokenHeader == m.ServiceToken {
@@ -33,3 +57,9 @@ func (m Authentication) AuthenticationMiddleware(next http.Handler) http.Handler
 		}
 	})
 }
+
+// isGuildTokenRoute func
+func (m Authentication) isGuildTokenRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	return route != nil && guildTokenRoutes[route.GetName()] && m.VerifyGuildToken != nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"runtime/debug"
 	"time"
 
@@ -56,7 +57,7 @@ func LoggingMiddleware() func(http.Handler) http.Handler {
 				zap.String("proto", r.Proto),
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.EscapedPath()),
-				zap.Any("query_params", r.URL.Query()),
+				zap.Any("query_params", redactQuery(r.URL.Query())),
 				zap.String("remote_address", r.RemoteAddr),
 				zap.String("request_body", string(body)),
 			)
@@ -111,3 +112,18 @@ func LoggingMiddleware() func(http.Handler) http.Handler {
 		return http.HandlerFunc(fn)
 	}
 }
+
+// redactQuery hides query parameters that carry credentials, like a live feed token
+func redactQuery(query url.Values) url.Values {
+	if query.Get("token") == "" {
+		return query
+	}
+
+	redacted := make(url.Values, len(query))
+	for key, val := range query {
+		redacted[key] = val
+	}
+	redacted.Set("token", "[redacted]")
+
+	return redacted
+}
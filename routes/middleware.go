@@ -1,6 +1,11 @@
 package routes
 
-import "net/http"
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
 
 // ResponseWriter struct
 type ResponseWriter struct {
@@ -43,3 +48,16 @@ func (rw *ResponseWriter) Write(message []byte) (int, error) {
 
 	return rw.ResponseWriter.Write(message)
 }
+
+// Hijack lets WebSocket upgrades take over the connection
+func (rw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	rw.status = http.StatusSwitchingProtocols
+	rw.wroteHeader = true
+
+	return hijacker.Hijack()
+}
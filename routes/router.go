@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/controllers"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
 	"go.uber.org/zap"
 )
 
@@ -24,28 +25,46 @@ func GetRouter(ctx context.Context) *mux.Router {
 	return mux.NewRouter().StrictSlash(true)
 }
 
-// AddRoutes adds all necessary routes to the router
-func AddRoutes(ctx context.Context, router *mux.Router, r Router) {
+// AddRoutes adds all necessary routes to the router and starts the listener.
+// The returned server is shut down by the lifecycle manager.
+func AddRoutes(ctx context.Context, router *mux.Router, r Router) *http.Server {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 	auth := Authentication{
-		ServiceToken: r.ServiceToken,
-		BasePath:     r.BasePath,
+		ServiceToken:     r.ServiceToken,
+		BasePath:         r.BasePath,
+		VerifyGuildToken: r.Controller.VerifyLiveFeedToken,
 	}
 
 	// STATUS
 	router.HandleFunc(r.BasePath+"/status", r.Controller.GetStatus).Methods("GET")
+	router.HandleFunc(r.BasePath+"/healthz", r.Controller.GetHealth).Methods("GET")
+	router.HandleFunc(r.BasePath+"/readyz", r.Controller.GetReadiness).Methods("GET")
 	router.HandleFunc(r.BasePath+"/activation-tokens", r.Controller.CreateActivationToken).Methods("POST")
 	router.HandleFunc(r.BasePath+"/discord/all-guilds", r.Controller.GetAllGuilds).Methods("GET")
 	router.HandleFunc(r.BasePath+"/discord/verify-subscriber-guilds", r.Controller.VerifySubscriberGuilds).Methods("GET")
+	router.HandleFunc(r.BasePath+"/guilds/{guild_id}/live", r.Controller.StreamGuildEvents).Methods("GET").Name("live_feed")
+	router.HandleFunc(r.BasePath+"/guilds/{guild_id}/live/tokens", r.Controller.CreateLiveFeedToken).Methods("POST")
+	router.HandleFunc(r.BasePath+"/event-bus", r.Controller.GetEventBusStats).Methods("GET")
+	router.HandleFunc(r.BasePath+"/runners/{runner}/schedule", r.Controller.GetRunnerSchedule).Methods("GET")
+	router.Handle(r.BasePath+"/metrics", metrics.Handler()).Methods("GET")
 
 	router.Use(auth.AuthenticationMiddleware)
 
 	loggingMiddleware := LoggingMiddleware()
 	loggedRouter := loggingMiddleware(router)
 
+	server := &http.Server{
+		Addr:    ":" + r.Port,
+		Handler: loggedRouter,
+	}
+
 	logger := logging.Logger(ctx)
 	logger.Info("Starting Listener", zap.String("port", r.Port))
-	if err := http.ListenAndServe(":"+r.Port, loggedRouter); err != nil {
-		logger.Fatal("error_log", zap.NamedError("err", err))
-	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("error_log", zap.NamedError("err", err))
+		}
+	}()
+
+	return server
 }
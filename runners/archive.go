@@ -0,0 +1,61 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// ArchiveLogs func
+func (r *Runners) ArchiveLogs(ctx context.Context, server gcscmodels.Server, logs nsv2.GetLogsResponse) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	sErr := r.LogArchive.Store(ctx, server.GuildID, server.NitradoID, logs)
+	if sErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", sErr.Err), zap.String("error_message", sErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+
+		return &Error{
+			Message: sErr.Message,
+			Err:     sErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// ArchivePrune deletes archived logs older than the configured retention
+func (r *Runners) ArchivePrune(ctx context.Context, delay time.Duration) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("runner", "archive_prune"),
+	)
+
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
+	}
+
+	ticker := time.NewTicker(r.Config.Runners.ArchivePrune.Frequency * time.Second)
+
+	for r.nextTick(ticker) {
+		deleted, pErr := r.LogArchive.Prune(ctx)
+		if pErr != nil {
+			newCtx := logging.AddValues(ctx,
+				zap.NamedError("error", pErr.Err),
+				zap.String("error_message", pErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		newCtx := logging.AddValues(ctx, zap.Int("deleted", deleted), zap.String("runner_message", "Pruned log archive"))
+		logger := logging.Logger(newCtx)
+		logger.Info("runner_log")
+	}
+}
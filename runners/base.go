@@ -3,6 +3,7 @@ package runners
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -10,29 +11,50 @@ import (
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventstream"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/httpsink"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/jobqueue"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/leader"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/livefeed"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/logarchive"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/nitradoservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pollschedule"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/shards"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	"go.uber.org/zap"
 )
 
-// MaxEmbedSize
-const MaxEmbedSize int = 5500
-
-// MaxEmbedFields
-const MaxEmbedFields int = 22
-
-// MaxEmbedFieldSize
-const MaxEmbedFieldSize int = 900
+// MaxEmbedFieldSize leaves room for the markdown added when log entries are rendered into a field
+const MaxEmbedFieldSize int = discordapi.MaxEmbedFieldValueCharCount - 124
 
 // Runners struct
 type Runners struct {
 	Session            *discordgo.Session
+	Shards             *shards.Manager
+	Scheduler          *discordapi.Scheduler
 	Config             *configs.Config
 	Cache              *cache.Cache
 	GuildConfigService *guildconfigservice.GuildConfigService
 	NitradoService     *nitradoservice.NitradoService
+	LogArchive         *logarchive.LogArchive
+	HTTPSink           *httpsink.Sink
+	LiveFeed           *livefeed.Hub
+	EventStream        *eventstream.Stream
+	EventBus           *eventbus.Bus
+	Leader             *leader.Elector
+	LogsJobs           *jobqueue.Queue
+	PlayersJobs        *jobqueue.Queue
+	LogsSchedule       *pollschedule.Schedule
+
+	stop     chan struct{}
+	running  sync.WaitGroup
+	mu       sync.Mutex
+	stopping bool
+	reposts  map[string]time.Time
 }
 
 // Error struct
@@ -42,9 +64,22 @@ type Error struct {
 }
 
 // RunnerOutputParams struct
+// Receipt is passed on to the embeds queued in the outbox, see outbox.Message.
 type RunnerOutputParams struct {
 	Title       string
 	Description string
+	Receipt     string
+}
+
+// OutputResult struct
+// Embeds are all the embeds built for an output, of which Discord accepted the first Sent.
+// Queued holds the outbox message IDs of the embeds after those, in order, and Sources maps each embed field
+// to the embeddable field it came from, as given by discordapi.CreateEmbedsWithSources.
+type OutputResult struct {
+	Embeds  []discordgo.MessageEmbed
+	Sent    int
+	Queued  []string
+	Sources []int
 }
 
 // Error func
@@ -62,17 +97,90 @@ func (e *Error) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi
 }
 
 // StartRunners func
+// Every replica runs the loops, but only the elected leader does any work on a tick,
+// so a follower picks up on its next tick once it takes over. Queued jobs are run by every replica.
+// A sharded bot elects a leader per shard and each leader only works on the guilds its shards own.
 func (r *Runners) StartRunners() {
 	ctx := context.Background()
 
-	go r.Logs(ctx, r.Config.Runners.Logs.Delay)
-	go r.OnlinePlayers(ctx, r.Config.Runners.Players.Delay)
+	r.stop = make(chan struct{})
+	r.reposts = make(map[string]time.Time)
+	r.Subscribe()
+	r.Shards.AddHandler(r.OnlinePlayersMessageDelete)
+
+	r.start("logs", func() { r.Logs(ctx, r.Config.Runners.Logs.Delay) })
+	r.start("players", func() { r.OnlinePlayers(ctx, r.Config.Runners.Players.Delay) })
+
+	if r.Config.Runners.Leaderboard.Enabled {
+		r.start("leaderboard", func() { r.Leaderboard(ctx, r.Config.Runners.Leaderboard.Delay) })
+	}
+
+	if r.Config.Runners.Digest.Enabled {
+		r.start("digest", func() { r.Digest(ctx, r.Config.Runners.Digest.Delay) })
+	}
+
+	if r.LogArchive != nil && r.Config.Runners.ArchivePrune.Enabled {
+		r.start("archive_prune", func() { r.ArchivePrune(ctx, r.Config.Runners.ArchivePrune.Delay) })
+	}
+
+	if r.Config.CacheSettings.Outbox.Enabled && r.Config.Runners.Outbox.Enabled {
+		r.start("outbox", func() { r.Outbox(ctx, r.Config.Runners.Outbox.Delay) })
+	}
+
+	r.startJobWorkers(ctx)
 	// go r.StatusRunner(r.Config.Runners.Status.Delay)
 	// go r.ServicesRunner(r.Config.Runners.Services.Delay)
 }
 
+// Stop ends the runner loops so no new ticks start, then waits for the work already queued to finish.
+// The work itself keeps its own context so in-flight requests are not cut short.
+func (r *Runners) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	r.stopping = true
+	r.mu.Unlock()
+
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start runs a runner loop on its own goroutine and tracks it until it returns
+func (r *Runners) start(name string, runner func()) {
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+		defer recovery.Recover(context.Background(), "runner:"+name, "")
+		runner()
+	}()
+}
+
+// begin reports whether work started by a Discord event may run, counting it as running if so
+func (r *Runners) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopping {
+		return false
+	}
+
+	r.running.Add(1)
+	return true
+}
+
 // LogsOutput func
-func (r *Runners) LogsOutput(ctx context.Context, runnerParams RunnerOutputParams, channel gcscmodels.ServerOutputChannel, server gcscmodels.Server, embeddableFields []discordapi.EmbeddableField, embeddableErrors []discordapi.EmbeddableField) ([]*discordgo.Message, *Error) {
+// Embeds that fail to send are queued in the outbox. On error the result still covers what was sent or queued before the failure.
+func (r *Runners) LogsOutput(ctx context.Context, runnerParams RunnerOutputParams, channel gcscmodels.ServerOutputChannel, server gcscmodels.Server, embeddableFields []discordapi.EmbeddableField, embeddableErrors []discordapi.EmbeddableField) (OutputResult, *Error) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
 	params := discordapi.EmbeddableParams{
@@ -88,37 +196,48 @@ func (r *Runners) LogsOutput(ctx context.Context, runnerParams RunnerOutputParam
 	}
 
 	combinedFields := append(embeddableFields, embeddableErrors...)
-	embeds := discordapi.CreateEmbeds(params, combinedFields)
+	embeds, sources := discordapi.CreateEmbedsWithSources(params, combinedFields)
 
-	var messages []*discordgo.Message
-	for _, embed := range embeds {
-		message, err := discordapi.SendMessage(r.Session, channel.ChannelID, nil, &embed)
-		if err != nil {
-			tempCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message), zap.Int("status_code", err.Code))
-			logger := logging.Logger(tempCtx)
-			logger.Error("runner_log")
-
-			if err.Code == 10003 {
-				_, dsocErr := guildconfigservice.DeleteServerOutputChannel(ctx, r.GuildConfigService, server.GuildID, int64(channel.ID))
-				if dsocErr != nil {
-					gcCtx := logging.AddValues(ctx, zap.NamedError("error", dsocErr.Err), zap.String("error_message", dsocErr.Message))
-					logger := logging.Logger(gcCtx)
-					logger.Error("error_log")
-				}
+	messages, err := r.SendOutputEmbeds(ctx, server, channel, embeds)
+	result := OutputResult{
+		Embeds:  embeds,
+		Sent:    len(messages),
+		Sources: sources,
+	}
+
+	if err != nil {
+		tempCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message), zap.Int("status_code", err.Code))
+		logger := logging.Logger(tempCtx)
+		logger.Error("runner_log")
+
+		if !permanentDiscordErrors[err.Code] {
+			queued, qeErr := r.QueueEmbeds(ctx, server, channel.ChannelID, runnerParams.Receipt, embeds[len(messages):])
+			result.Queued = queued
+			if qeErr == nil {
+				return result, nil
 			}
+		}
 
-			return nil, &Error{
-				Message: err.Message,
-				Err:     err.Err,
+		if err.Code == 10003 {
+			_, dsocErr := guildconfigservice.DeleteServerOutputChannel(ctx, r.GuildConfigService, server.GuildID, int64(channel.ID))
+			if dsocErr != nil {
+				gcCtx := logging.AddValues(ctx, zap.NamedError("error", dsocErr.Err), zap.String("error_message", dsocErr.Message))
+				logger := logging.Logger(gcCtx)
+				logger.Error("error_log")
 			}
 		}
-		messages = append(messages, message)
+
+		return result, &Error{
+			Message: err.Message,
+			Err:     err.Err,
+		}
 	}
 
-	return messages, nil
+	return result, nil
 }
 
 // OnlinePlayersOutput func
+// Sends and edits go through the scheduler so they share each channel's queue with the other runner output.
 func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.ServerOutputChannel, server gcscmodels.Server, totalPlayers int, embeddableFields []discordapi.EmbeddableField, embeddableErrors []discordapi.EmbeddableField) ([]*discordgo.Message, *Error) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
@@ -157,7 +276,7 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 	for key, embed := range embeds {
 		if onlinePlayersOutputChannelMessages != nil {
 			if key >= len(onlinePlayersOutputChannelMessages.Messages) {
-				message, err := discordapi.SendMessage(r.Session, channel.ChannelID, nil, &embed)
+				message, err := r.Scheduler.SendStandaloneEmbed(server.GuildID, channel.ChannelID, embed)
 				if err != nil {
 					if err.Code == 10003 {
 						_, dsocErr := guildconfigservice.DeleteServerOutputChannel(ctx, r.GuildConfigService, server.GuildID, int64(channel.ID))
@@ -170,6 +289,10 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 						tempCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message), zap.Int("status_code", err.Code))
 						logger := logging.Logger(tempCtx)
 						logger.Error("runner_log")
+
+						if !permanentDiscordErrors[err.Code] {
+							r.QueueSupersedingEmbed(ctx, server.GuildID, channel.ChannelID, "", onlinePlayersSupersedeKey(server, key), embed)
+						}
 					}
 
 					return nil, &Error{
@@ -177,6 +300,7 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 						Err:     err.Err,
 					}
 				}
+				r.DiscardSupersededEmbed(ctx, channel.ChannelID, onlinePlayersSupersedeKey(server, key))
 				messages = append(messages, message)
 				modelMessages = append(modelMessages, models.Message{
 					ID: message.ID,
@@ -185,7 +309,7 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 					},
 				})
 			} else {
-				message, err := discordapi.EditMessage(r.Session, channel.ChannelID, onlinePlayersOutputChannelMessages.Messages[key].ID, nil, &embed)
+				message, err := r.Scheduler.EditEmbed(server.GuildID, channel.ChannelID, onlinePlayersOutputChannelMessages.Messages[key].ID, embed)
 				if err != nil {
 					if err.Code == 10003 {
 						_, dsocErr := guildconfigservice.DeleteServerOutputChannel(ctx, r.GuildConfigService, server.GuildID, int64(channel.ID))
@@ -199,7 +323,7 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 							Err:     err,
 						}
 					} else if err.Code == 10008 {
-						newmessage, nerr := discordapi.SendMessage(r.Session, channel.ChannelID, nil, &embed)
+						newmessage, nerr := r.Scheduler.SendStandaloneEmbed(server.GuildID, channel.ChannelID, embed)
 						if nerr != nil {
 							tempCtx := logging.AddValues(ctx, zap.NamedError("error", nerr.Err), zap.String("error_message", nerr.Message), zap.Int("status_code", nerr.Code))
 							logger := logging.Logger(tempCtx)
@@ -230,8 +354,15 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 						tempCtx := logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message), zap.Int("status_code", err.Code))
 						logger := logging.Logger(tempCtx)
 						logger.Error("runner_log")
+
+						// Keep tracking the message so it is not deleted while its edit waits in the outbox
+						prevMessage := onlinePlayersOutputChannelMessages.Messages[key]
+						if !permanentDiscordErrors[err.Code] && r.QueueSupersedingEmbed(ctx, server.GuildID, channel.ChannelID, prevMessage.ID, onlinePlayersSupersedeKey(server, key), embed) {
+							modelMessages = append(modelMessages, prevMessage)
+						}
 					}
 				} else {
+					r.DiscardSupersededEmbed(ctx, channel.ChannelID, onlinePlayersSupersedeKey(server, key))
 					messages = append(messages, message)
 					modelMessages = append(modelMessages, models.Message{
 						ID: message.ID,
@@ -284,3 +415,8 @@ func (r *Runners) OnlinePlayersOutput(ctx context.Context, channel gcscmodels.Se
 
 	return messages, nil
 }
+
+// onlinePlayersSupersedeKey identifies one embed of a server's online players output in the outbox
+func onlinePlayersSupersedeKey(server gcscmodels.Server, key int) string {
+	return fmt.Sprintf("players:%d:%d", server.ID, key)
+}
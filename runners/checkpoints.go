@@ -0,0 +1,388 @@
+package runners
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outbox"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// MaxPendingLogs is the most undelivered entries kept per server and log type
+const MaxPendingLogs int = 1000
+
+// logEntry is a log entry of any type along with what its checkpoint needs to know about it
+type logEntry struct {
+	Timestamp int64
+	Hash      string
+	Log       interface{}
+}
+
+// logKind describes how deliverLogs stores and posts one type of log
+type logKind struct {
+	Name       string
+	Pending    func(checkpoint *models.LogCheckpoint) []logEntry
+	SetPending func(checkpoint *models.LogCheckpoint, entries []logEntry)
+	Write      func(ctx context.Context, server gcscmodels.Server, channel *gcscmodels.ServerOutputChannel, entries []logEntry, receipt string) LogsDelivery
+}
+
+// LogsDelivery struct
+// Delivered counts the oldest entries Discord accepted, and Queued the entries after them in each embed left in the outbox, in order.
+type LogsDelivery struct {
+	Delivered int
+	Queued    []QueuedEmbed
+}
+
+// QueuedEmbed struct
+type QueuedEmbed struct {
+	OutboxID string
+	Entries  int
+}
+
+// DeliverAdminLogs posts admin logs that are newer than the server's checkpoint and advances it past the ones Discord accepted
+func (r *Runners) DeliverAdminLogs(ctx context.Context, server gcscmodels.Server, adminLogOutput *gcscmodels.ServerOutputChannel, adminLogs []nsv2.AdminLog) {
+	r.deliverLogs(ctx, server, adminLogOutput, logKind{
+		Name: "admin",
+		Pending: func(checkpoint *models.LogCheckpoint) []logEntry {
+			return adminLogEntries(checkpoint.PendingAdmin)
+		},
+		SetPending: func(checkpoint *models.LogCheckpoint, entries []logEntry) {
+			checkpoint.PendingAdmin = nil
+			for _, entry := range entries {
+				checkpoint.PendingAdmin = append(checkpoint.PendingAdmin, entry.Log.(nsv2.AdminLog))
+			}
+		},
+		Write: func(ctx context.Context, server gcscmodels.Server, channel *gcscmodels.ServerOutputChannel, entries []logEntry, receipt string) LogsDelivery {
+			var logs []nsv2.AdminLog
+			for _, entry := range entries {
+				logs = append(logs, entry.Log.(nsv2.AdminLog))
+			}
+			return r.WriteAdminLogs(ctx, server, channel, logs, receipt)
+		},
+	}, adminLogEntries(adminLogs))
+}
+
+// DeliverChatLogs posts chat logs that are newer than the server's checkpoint and advances it past the ones Discord accepted
+func (r *Runners) DeliverChatLogs(ctx context.Context, server gcscmodels.Server, chatLogOutput *gcscmodels.ServerOutputChannel, chatLogs []nsv2.PlayerLog) {
+	r.deliverLogs(ctx, server, chatLogOutput, logKind{
+		Name: "chat",
+		Pending: func(checkpoint *models.LogCheckpoint) []logEntry {
+			return chatLogEntries(checkpoint.PendingChat)
+		},
+		SetPending: func(checkpoint *models.LogCheckpoint, entries []logEntry) {
+			checkpoint.PendingChat = nil
+			for _, entry := range entries {
+				checkpoint.PendingChat = append(checkpoint.PendingChat, entry.Log.(nsv2.PlayerLog))
+			}
+		},
+		Write: func(ctx context.Context, server gcscmodels.Server, channel *gcscmodels.ServerOutputChannel, entries []logEntry, receipt string) LogsDelivery {
+			var logs []nsv2.PlayerLog
+			for _, entry := range entries {
+				logs = append(logs, entry.Log.(nsv2.PlayerLog))
+			}
+			return r.WriteChatLogs(ctx, server, channel, logs, receipt)
+		},
+	}, chatLogEntries(chatLogs))
+}
+
+// DeliverKillLogs posts kill logs that are newer than the server's checkpoint and advances it past the ones Discord accepted
+func (r *Runners) DeliverKillLogs(ctx context.Context, server gcscmodels.Server, killLogOutput *gcscmodels.ServerOutputChannel, killLogs []nsv2.KillLog) {
+	r.deliverLogs(ctx, server, killLogOutput, logKind{
+		Name: "kills",
+		Pending: func(checkpoint *models.LogCheckpoint) []logEntry {
+			return killLogEntries(checkpoint.PendingKills)
+		},
+		SetPending: func(checkpoint *models.LogCheckpoint, entries []logEntry) {
+			checkpoint.PendingKills = nil
+			for _, entry := range entries {
+				checkpoint.PendingKills = append(checkpoint.PendingKills, entry.Log.(nsv2.KillLog))
+			}
+		},
+		Write: func(ctx context.Context, server gcscmodels.Server, channel *gcscmodels.ServerOutputChannel, entries []logEntry, receipt string) LogsDelivery {
+			var logs []nsv2.KillLog
+			for _, entry := range entries {
+				logs = append(logs, entry.Log.(nsv2.KillLog))
+			}
+			return r.WriteKillLogs(ctx, server, channel, logs, receipt)
+		},
+	}, killLogEntries(killLogs))
+}
+
+// deliverLogs posts the entries of one log type that are newer than the server's checkpoint, oldest first,
+// and advances the checkpoint past the ones Discord accepted. The rest are kept as pending for the next run.
+// Entries whose embed was queued in the outbox stay pending until the outbox delivers it, and newer entries wait behind them.
+// Without a checkpoint the fetched entries are posted as they are.
+func (r *Runners) deliverLogs(ctx context.Context, server gcscmodels.Server, channel *gcscmodels.ServerOutputChannel, kind logKind, fetched []logEntry) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()), zap.String("log_type", kind.Name))
+
+	checkpoint, cacheKey, gcErr := r.getLogCheckpoint(ctx, server, kind.Name)
+	if gcErr != nil {
+		if len(fetched) > 0 {
+			kind.Write(ctx, server, channel, fetched, "")
+		}
+		return
+	}
+
+	waiting, settled := r.settleQueuedLogs(ctx, server.GuildID, channel.ChannelID, cacheKey, checkpoint)
+
+	pending := kind.Pending(checkpoint)
+
+	seen := make(map[string]bool)
+	var undelivered []logEntry
+	for _, entry := range append(pending, fetched...) {
+		if seen[entry.Hash] || checkpoint.Delivered(entry.Timestamp, entry.Hash) {
+			continue
+		}
+		seen[entry.Hash] = true
+		undelivered = append(undelivered, entry)
+	}
+
+	if len(undelivered) == 0 && len(pending) == 0 && !settled {
+		return
+	}
+
+	sort.SliceStable(undelivered, func(i, j int) bool {
+		return undelivered[i].Timestamp < undelivered[j].Timestamp
+	})
+
+	if !waiting && len(undelivered) > 0 {
+		delivery := kind.Write(ctx, server, channel, undelivered, cacheKey)
+
+		next := 0
+		for ; next < delivery.Delivered; next++ {
+			checkpoint.Advance(undelivered[next].Timestamp, undelivered[next].Hash)
+		}
+
+		for _, embed := range delivery.Queued {
+			queued := models.QueuedLogs{
+				OutboxID: embed.OutboxID,
+			}
+			for i := 0; i < embed.Entries && next < len(undelivered); i++ {
+				queued.Timestamps = append(queued.Timestamps, undelivered[next].Timestamp)
+				queued.Hashes = append(queued.Hashes, undelivered[next].Hash)
+				next++
+			}
+			checkpoint.Queued = append(checkpoint.Queued, queued)
+		}
+
+		undelivered = undelivered[delivery.Delivered:]
+	}
+
+	if len(undelivered) > MaxPendingLogs {
+		undelivered = undelivered[len(undelivered)-MaxPendingLogs:]
+	}
+	kind.SetPending(checkpoint, undelivered)
+
+	r.setLogCheckpoint(ctx, cacheKey, checkpoint)
+}
+
+// settleQueuedLogs advances the checkpoint past the queued embeds the outbox has delivered since the last run.
+// It reports whether any are still waiting in the outbox, and whether the checkpoint changed.
+// An embed that left the outbox undelivered was dead-lettered or discarded. Its entries are still pending, so they
+// are posted again along with everything queued after it, which is taken back out of the outbox to keep the order.
+func (r *Runners) settleQueuedLogs(ctx context.Context, guildID string, channelID string, cacheKey string, checkpoint *models.LogCheckpoint) (bool, bool) {
+	if len(checkpoint.Queued) == 0 {
+		return false, false
+	}
+
+	settings := r.Config.CacheSettings.Outbox
+
+	ids, rErr := outbox.Receipts(ctx, r.Cache, settings, cacheKey)
+	if rErr != nil {
+		r.logOutboxError(ctx, rErr)
+		return true, false
+	}
+
+	delivered := make(map[string]bool)
+	for _, id := range ids {
+		delivered[id] = true
+	}
+
+	var waiting []models.QueuedLogs
+	for i, queued := range checkpoint.Queued {
+		if delivered[queued.OutboxID] {
+			for j := range queued.Hashes {
+				checkpoint.Advance(queued.Timestamps[j], queued.Hashes[j])
+			}
+			continue
+		}
+
+		stillQueued, qErr := outbox.Queued(ctx, r.Cache, settings, queued.OutboxID)
+		if qErr != nil {
+			r.logOutboxError(ctx, qErr)
+			waiting = checkpoint.Queued[i:]
+			break
+		}
+
+		if stillQueued {
+			waiting = checkpoint.Queued[i:]
+			break
+		}
+
+		for _, later := range checkpoint.Queued[i+1:] {
+			if delivered[later.OutboxID] {
+				continue
+			}
+
+			if rmErr := outbox.Remove(ctx, r.Cache, settings, guildID, channelID, later.OutboxID); rmErr != nil {
+				r.logOutboxError(ctx, rmErr)
+			}
+		}
+		break
+	}
+
+	checkpoint.Queued = waiting
+
+	if cErr := outbox.ClearReceipts(ctx, r.Cache, settings, cacheKey, ids); cErr != nil {
+		r.logOutboxError(ctx, cErr)
+	}
+
+	return len(waiting) > 0, true
+}
+
+// adminLogEntries func
+func adminLogEntries(logs []nsv2.AdminLog) []logEntry {
+	var entries []logEntry
+	for _, entry := range logs {
+		entries = append(entries, logEntry{
+			Timestamp: entry.Timestamp,
+			Hash:      logHash(entry.Name, entry.Command, entry.Timestamp),
+			Log:       entry,
+		})
+	}
+
+	return entries
+}
+
+// chatLogEntries func
+func chatLogEntries(logs []nsv2.PlayerLog) []logEntry {
+	var entries []logEntry
+	for _, entry := range logs {
+		entries = append(entries, logEntry{
+			Timestamp: entry.Timestamp,
+			Hash:      logHash(entry.Gamertag, entry.Name, entry.Message, entry.Timestamp),
+			Log:       entry,
+		})
+	}
+
+	return entries
+}
+
+// killLogEntries func
+func killLogEntries(logs []nsv2.KillLog) []logEntry {
+	var entries []logEntry
+	for _, entry := range logs {
+		entries = append(entries, logEntry{
+			Timestamp: entry.Timestamp,
+			Hash:      logHash(entry.PvEKill, entry.KilledName, entry.KilledLevel, entry.KilledDinoType, entry.KilledTribe, entry.KillerName, entry.KillerLevel, entry.KillerDinoType, entry.KillerTribe, entry.Timestamp),
+			Log:       entry,
+		})
+	}
+
+	return entries
+}
+
+// getLogCheckpoint loads the checkpoint for a server and log type, returning an empty checkpoint if none exists
+func (r *Runners) getLogCheckpoint(ctx context.Context, server gcscmodels.Server, logType string) (*models.LogCheckpoint, string, *Error) {
+	var checkpoint *models.LogCheckpoint
+	cacheKey := checkpoint.CacheKey(r.Config.CacheSettings.LogCheckpoints.Base, server.GuildID, server.NitradoID, logType)
+	if gsErr := r.Cache.GetStruct(ctx, cacheKey, &checkpoint); gsErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", gsErr.Err), zap.String("error_message", gsErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+
+		return nil, cacheKey, &Error{
+			Message: gsErr.Message,
+			Err:     gsErr.Err,
+		}
+	}
+
+	if checkpoint == nil {
+		checkpoint = &models.LogCheckpoint{}
+	}
+
+	return checkpoint, cacheKey, nil
+}
+
+// setLogCheckpoint func
+func (r *Runners) setLogCheckpoint(ctx context.Context, cacheKey string, checkpoint *models.LogCheckpoint) {
+	if ssErr := r.Cache.SetStruct(ctx, cacheKey, checkpoint, r.Config.CacheSettings.LogCheckpoints.TTL); ssErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", ssErr.Err), zap.String("error_message", ssErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+	}
+}
+
+// logHash identifies a log entry by its contents.
+// Each part is prefixed with its length so text can't move between adjacent parts and give the same hash.
+func logHash(parts ...interface{}) string {
+	h := sha1.New()
+	for _, part := range parts {
+		text := fmt.Sprint(part)
+		fmt.Fprintf(h, "%d:%s", len(text), text)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// outputDelivery splits an output's entries between the embeds Discord accepted and those queued in the outbox.
+// fieldEntries counts the log entries in each embeddable field of the output.
+func outputDelivery(result OutputResult, fieldEntries []int) LogsDelivery {
+	counts := embedEntries(result.Embeds, result.Sources, fieldEntries)
+
+	var delivery LogsDelivery
+	for i := 0; i < result.Sent && i < len(counts); i++ {
+		delivery.Delivered += counts[i]
+	}
+
+	for i, id := range result.Queued {
+		embed := result.Sent + i
+		if embed >= len(counts) {
+			break
+		}
+
+		delivery.Queued = append(delivery.Queued, QueuedEmbed{
+			OutboxID: id,
+			Entries:  counts[embed],
+		})
+	}
+
+	return delivery
+}
+
+// embedEntries counts the log entries completed by each embed.
+// sources maps each embed field to the output it came from, since a long output can be split over several fields,
+// and an output belongs to the embed holding its last field. Counting stops at the first output without fields,
+// so the entries counted are always the oldest ones.
+func embedEntries(embeds []discordgo.MessageEmbed, sources []int, fieldEntries []int) []int {
+	var fieldEmbeds []int
+	for i := range embeds {
+		for range embeds[i].Fields {
+			fieldEmbeds = append(fieldEmbeds, i)
+		}
+	}
+
+	lastField := make(map[int]int)
+	for field, source := range sources {
+		lastField[source] = field
+	}
+
+	counts := make([]int, len(embeds))
+	for source := 0; source < len(fieldEntries); source++ {
+		field, ok := lastField[source]
+		if !ok || field >= len(fieldEmbeds) {
+			break
+		}
+		counts[fieldEmbeds[field]] += fieldEntries[source]
+	}
+
+	return counts
+}
@@ -0,0 +1,128 @@
+package runners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+)
+
+// testField is an embeddable field with a fixed value
+type testField struct {
+	value string
+}
+
+// ConvertToEmbedField func
+func (f testField) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	return &discordgo.MessageEmbedField{
+		Name:  "Logs",
+		Value: f.value,
+	}, nil
+}
+
+func TestOutputDelivery(t *testing.T) {
+	// 24 short outputs fill the first embed up to its last field, where the long output starts
+	// before being split into the second embed
+	var fields []discordapi.EmbeddableField
+	var fieldEntries []int
+	for i := 0; i < 24; i++ {
+		fields = append(fields, testField{value: "entry"})
+		fieldEntries = append(fieldEntries, 1)
+	}
+	fields = append(fields, testField{value: strings.Repeat("line of a long log output\n", 100)})
+	fieldEntries = append(fieldEntries, 10)
+	fields = append(fields, testField{value: "entry"})
+	fieldEntries = append(fieldEntries, 1)
+
+	embeds, sources := discordapi.CreateEmbedsWithSources(discordapi.EmbeddableParams{Title: "Logs"}, fields)
+	if len(embeds) != 2 {
+		t.Fatalf("expected the long output to be split across 2 embeds, got %d embeds", len(embeds))
+	}
+	if len(embeds[0].Fields) != discordapi.MaxEmbedFields || sources[discordapi.MaxEmbedFields-1] != 24 {
+		t.Fatalf("expected the first embed to end with the start of the long output, got sources %v", sources)
+	}
+
+	tests := []struct {
+		name      string
+		sent      int
+		queued    []string
+		delivered int
+		want      []QueuedEmbed
+	}{
+		{
+			name:      "nothing sent or queued",
+			sent:      0,
+			delivered: 0,
+		},
+		{
+			name:      "failed after the first piece of the split output",
+			sent:      1,
+			delivered: 24,
+		},
+		{
+			name:      "everything sent",
+			sent:      2,
+			delivered: 35,
+		},
+		{
+			name:      "rest of the split output queued",
+			sent:      1,
+			queued:    []string{"b"},
+			delivered: 24,
+			want:      []QueuedEmbed{{OutboxID: "b", Entries: 11}},
+		},
+		{
+			name:      "everything queued",
+			sent:      0,
+			queued:    []string{"a", "b"},
+			delivered: 0,
+			want:      []QueuedEmbed{{OutboxID: "a", Entries: 24}, {OutboxID: "b", Entries: 11}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := outputDelivery(OutputResult{
+				Embeds:  embeds,
+				Sent:    tt.sent,
+				Queued:  tt.queued,
+				Sources: sources,
+			}, fieldEntries)
+
+			if got.Delivered != tt.delivered {
+				t.Errorf("Delivered = %d, want %d", got.Delivered, tt.delivered)
+			}
+			if len(got.Queued) != len(tt.want) {
+				t.Fatalf("Queued = %v, want %v", got.Queued, tt.want)
+			}
+			for i := range tt.want {
+				if got.Queued[i] != tt.want[i] {
+					t.Errorf("Queued = %v, want %v", got.Queued, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbedEntriesSkippedField(t *testing.T) {
+	// The second output failed to convert, so nothing after it is counted
+	embeds := []discordgo.MessageEmbed{
+		{Fields: []*discordgo.MessageEmbedField{{}, {}}},
+	}
+
+	got := embedEntries(embeds, []int{0, 2}, []int{3, 4, 5})
+	if len(got) != 1 || got[0] != 3 {
+		t.Errorf("embedEntries() = %v, want [3]", got)
+	}
+}
+
+func TestLogHash(t *testing.T) {
+	if logHash("Bo", "bhi", int64(1)) == logHash("Bob", "hi", int64(1)) {
+		t.Error("logHash() gives the same hash when text moves between parts")
+	}
+
+	if logHash("Bob", "hi", int64(1)) != logHash("Bob", "hi", int64(1)) {
+		t.Error("logHash() is not stable")
+	}
+}
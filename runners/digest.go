@@ -0,0 +1,325 @@
+package runners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gammazero/workerpool"
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// DailyDigestHours const
+const DailyDigestHours int = 24
+
+// DailyDigestOutput struct
+type DailyDigestOutput struct {
+	Server  gcscmodels.Server
+	Summary serverstats.Summary
+}
+
+// DailyDigestErrorOutput struct
+type DailyDigestErrorOutput struct {
+	Server  gcscmodels.Server
+	Message string
+}
+
+// Digest posts the opt-in daily digest for each guild once the configured local time has passed
+func (r *Runners) Digest(ctx context.Context, delay time.Duration) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("runner", "digest"),
+	)
+
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
+	}
+
+	ticker := time.NewTicker(r.Config.Runners.Digest.Frequency * time.Second)
+
+	wp := workerpool.New(r.Config.Runners.Digest.Workers)
+	defer wp.StopWait()
+
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			continue
+		}
+
+		requestID := uuid.New()
+		gCtx := logging.AddValues(ctx, zap.String("request_id", requestID.String()))
+
+		if wp.WaitingQueueSize() > 0 {
+			newCtx := logging.AddValues(gCtx,
+				zap.Int("queue_size", wp.WaitingQueueSize()),
+				zap.NamedError("error", errors.New("queue not empty")),
+				zap.String("error_message", "cannot start new digest run with non-empty queue"),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			skipTick("digest")
+			continue
+		}
+
+		r.digestTick(gCtx, wp)
+	}
+}
+
+// digestTick queues the daily digest for every guild whose digest is due
+func (r *Runners) digestTick(ctx context.Context, wp *workerpool.WorkerPool) {
+	defer recovery.Recover(ctx, "runner:digest", "")
+
+	tick := startTick(ctx, "digest", wp)
+	defer tick.finish()
+
+	allGuilds, agErr := guildconfigservice.GetAllGuilds(ctx, r.GuildConfigService)
+	if agErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", agErr),
+			zap.String("error_message", agErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if allGuilds.Payload == nil || allGuilds.Payload.Guilds == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil guilds")),
+			zap.String("error_message", "nil guilds in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	for _, aGuild := range allGuilds.Payload.Guilds {
+		agCtx := logging.AddValues(ctx, zap.String("guild_id", aGuild.ID))
+
+		if !aGuild.Enabled || !r.Shards.Owns(aGuild.ID) {
+			continue
+		}
+
+		var digest *models.DailyDigest
+		cacheKey := digest.CacheKey(r.Config.CacheSettings.DailyDigest.Base, aGuild.ID)
+		if gsErr := r.Cache.GetStruct(agCtx, cacheKey, &digest); gsErr != nil {
+			newCtx := logging.AddValues(agCtx,
+				zap.NamedError("error", gsErr.Err),
+				zap.String("error_message", gsErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		if digest == nil || !digest.Enabled {
+			continue
+		}
+
+		loc, lErr := time.LoadLocation(digest.Timezone)
+		if lErr != nil {
+			loc = time.UTC
+		}
+
+		now := time.Now().In(loc)
+		scheduled, tErr := time.ParseInLocation("2006-01-02 15:04", now.Format("2006-01-02")+" "+digest.Time, loc)
+		if tErr != nil || now.Before(scheduled) {
+			continue
+		}
+
+		today := now.Format("2006-01-02")
+		if digest.LastSent == today {
+			continue
+		}
+
+		// The claim keeps a slow send from being queued again by the next tick; LastSent is only saved once the digest goes out
+		claimKey := fmt.Sprintf("%s:sending:%s", cacheKey, today)
+		isNew, snxErr := r.Cache.SetNX(agCtx, claimKey, "1", strconv.Itoa(DailyDigestHours*3600))
+		if snxErr != nil {
+			newCtx := logging.AddValues(agCtx,
+				zap.NamedError("error", snxErr.Err),
+				zap.String("error_message", snxErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		if !isNew {
+			continue
+		}
+
+		var aDigest models.DailyDigest = *digest
+
+		tick.submit(func() {
+			if wdErr := r.WriteDailyDigest(agCtx, aDigest); wdErr != nil {
+				if delErr := r.Cache.Delete(agCtx, claimKey); delErr != nil {
+					newCtx := logging.AddValues(agCtx,
+						zap.NamedError("error", delErr.Err),
+						zap.String("error_message", delErr.Message),
+					)
+					logger := logging.Logger(newCtx)
+					logger.Error("runner_log")
+				}
+				return
+			}
+
+			r.markDigestSent(agCtx, cacheKey, today)
+		})
+	}
+}
+
+// markDigestSent saves the day the digest went out, re-reading it so settings changed during the send are kept
+func (r *Runners) markDigestSent(ctx context.Context, cacheKey string, today string) {
+	var digest *models.DailyDigest
+	if gsErr := r.Cache.GetStruct(ctx, cacheKey, &digest); gsErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", gsErr.Err),
+			zap.String("error_message", gsErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if digest == nil {
+		return
+	}
+
+	digest.LastSent = today
+	if ssErr := r.Cache.SetStruct(ctx, cacheKey, digest, r.Config.CacheSettings.DailyDigest.TTL); ssErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", ssErr.Err),
+			zap.String("error_message", ssErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+}
+
+// WriteDailyDigest func
+func (r *Runners) WriteDailyDigest(ctx context.Context, digest models.DailyDigest) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, r.GuildConfigService, digest.GuildID)
+	if gfErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", gfErr), zap.String("error_message", gfErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return &Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		}
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+		return &Error{
+			Message: vErr.Message,
+			Err:     vErr,
+		}
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	end := time.Now()
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		if !server.Enabled {
+			continue
+		}
+
+		summary, gsErr := serverstats.GetSummary(ctx, r.Cache, r.Config.CacheSettings.ServerStats, digest.GuildID, server.NitradoID, end, DailyDigestHours)
+		if gsErr != nil {
+			embeddableErrors = append(embeddableErrors, &DailyDigestErrorOutput{
+				Server:  *server,
+				Message: gsErr.Message,
+			})
+			continue
+		}
+
+		embeddableFields = append(embeddableFields, &DailyDigestOutput{
+			Server:  *server,
+			Summary: *summary,
+		})
+	}
+
+	if len(embeddableFields) == 0 && len(embeddableErrors) == 0 {
+		return nil
+	}
+
+	params := discordapi.EmbeddableParams{
+		Title:       "Daily Server Digest",
+		Description: fmt.Sprintf("Activity for the last %d hours.", DailyDigestHours),
+		Color:       r.Config.Bot.OkColor,
+		TitleURL:    r.Config.Bot.DocumentationURL,
+		Footer:      "Generated",
+	}
+
+	if len(embeddableErrors) > 0 {
+		params.Color = r.Config.Bot.WarnColor
+	}
+
+	combinedFields := append(embeddableFields, embeddableErrors...)
+	embeds := discordapi.CreateEmbeds(params, combinedFields)
+
+	if _, smErr := r.Scheduler.SendEmbeds(digest.GuildID, digest.Channel.ID, embeds); smErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", smErr.Err), zap.String("error_message", smErr.Message), zap.Int("status_code", smErr.Code))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return &Error{
+			Message: smErr.Message,
+			Err:     smErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// ConvertToEmbedField for DailyDigestOutput struct
+func (ddo *DailyDigestOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	summary := ddo.Summary
+
+	uptime := "Unknown"
+	if summary.Polls > 0 {
+		uptime = fmt.Sprintf("%.1f%%", summary.Uptime())
+	}
+
+	fieldVal := fmt.Sprintf("**Uptime:** %s\n**Players:** %d peak / %.1f average / %d unique\n**Chat Messages:** %d\n**Admin Commands:** %d\n**PvP Kills:** %d\n**Bans:** %d / **Unbans:** %d\n**Whitelists:** %d / **Unwhitelists:** %d\n\u200b",
+		uptime,
+		summary.PeakPlayers,
+		summary.AveragePlayers(),
+		summary.UniquePlayers,
+		summary.ChatMessages,
+		summary.AdminCount,
+		summary.Kills,
+		summary.Bans,
+		summary.Unbans,
+		summary.Whitelists,
+		summary.Unwhitelists,
+	)
+
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("%s (%d)", ddo.Server.Name, ddo.Server.NitradoID),
+		Value:  fieldVal,
+		Inline: false,
+	}, nil
+}
+
+// ConvertToEmbedField for DailyDigestErrorOutput struct
+func (ddo *DailyDigestErrorOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	return &discordgo.MessageEmbedField{
+		Name:   fmt.Sprintf("%s (%d)", ddo.Server.Name, ddo.Server.NitradoID),
+		Value:  ddo.Message,
+		Inline: false,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// PublishGameEvents converts bus events to game events for the HTTP endpoints, live feeds and event streams.
+// Log event IDs are derived from the log contents so entries returned by overlapping fetches are only sent once.
+func (r *Runners) PublishGameEvents(ctx context.Context, batch eventbus.Batch) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	server := batch.Server
+	now := time.Now().Unix()
+
+	var events []models.GameEvent
+	for _, event := range batch.Events {
+		switch e := event.(type) {
+		case eventbus.ChatMessage:
+			id := logHash(server.NitradoID, e.Log.Gamertag, e.Log.Name, e.Log.Message, e.Log.Timestamp)
+			events = append(events, models.NewGameEvent(id, models.GameEventChat, server, e.Log.Timestamp, e.Log))
+		case eventbus.AdminCommand:
+			id := logHash(server.NitradoID, e.Log.Name, e.Log.Command, e.Log.Timestamp)
+			events = append(events, models.NewGameEvent(id, models.GameEventAdmin, server, e.Log.Timestamp, e.Log))
+		case eventbus.Kill:
+			id := logHash(server.NitradoID, e.Log.PvEKill, e.Log.KilledName, e.Log.KilledLevel, e.Log.KilledDinoType, e.Log.KilledTribe, e.Log.KillerName, e.Log.KillerLevel, e.Log.KillerDinoType, e.Log.KillerTribe, e.Log.Timestamp)
+			events = append(events, models.NewGameEvent(id, models.GameEventKill, server, e.Log.Timestamp, e.Log))
+		case eventbus.PlayersSnapshot:
+			if e.Online {
+				events = append(events, r.playerEvents(ctx, server, e.Players, now)...)
+			}
+		case eventbus.ModerationAction:
+			events = append(events, models.NewGameEvent(uuid.New().String(), models.GameEventModeration, server, now, models.ModerationEventData{
+				Action: e.Action,
+				Player: e.Player,
+			}))
+		case eventbus.ServerControl:
+			eventType := models.GameEventRestart
+			if e.Action == eventbus.ActionStop {
+				eventType = models.GameEventStop
+			}
+			events = append(events, models.NewGameEvent(uuid.New().String(), eventType, server, now, nil))
+		}
+	}
+
+	r.PublishEvents(ctx, server.GuildID, events)
+}
+
+// playerEvents compares the players online on a server with the last poll and returns join and leave events.
+// The first poll for a server only records who is online so a restart doesn't announce everyone as joining.
+func (r *Runners) playerEvents(ctx context.Context, server gcscmodels.Server, players []nsv2.Player, now int64) []models.GameEvent {
+	// Store an empty list rather than null so an empty server isn't mistaken for a first poll
+	current := models.OnlinePlayers{
+		Players: []string{},
+	}
+	for _, player := range players {
+		if player.Name == "" {
+			continue
+		}
+
+		current.Players = append(current.Players, player.Name)
+	}
+
+	var previous *models.OnlinePlayers
+	cacheKey := current.CacheKey(r.Config.CacheSettings.OnlinePlayers.Base, server.GuildID, server.NitradoID)
+	if gsErr := r.Cache.GetStruct(ctx, cacheKey, &previous); gsErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", gsErr.Err), zap.String("error_message", gsErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return nil
+	}
+
+	if ssErr := r.Cache.SetStruct(ctx, cacheKey, &current, r.Config.CacheSettings.OnlinePlayers.TTL); ssErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", ssErr.Err), zap.String("error_message", ssErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return nil
+	}
+
+	if previous == nil {
+		return nil
+	}
+
+	joined, left := previous.Changes(current.Players)
+
+	var events []models.GameEvent
+	for _, name := range joined {
+		events = append(events, models.NewGameEvent(uuid.New().String(), models.GameEventJoin, server, now, models.PlayerEventData{Name: name}))
+	}
+
+	for _, name := range left {
+		events = append(events, models.NewGameEvent(uuid.New().String(), models.GameEventLeave, server, now, models.PlayerEventData{Name: name}))
+	}
+
+	return events
+}
+
+// PublishEvents hands events to every enabled event consumer
+func (r *Runners) PublishEvents(ctx context.Context, guildID string, events []models.GameEvent) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if len(events) == 0 {
+		return
+	}
+
+	if r.LiveFeed != nil {
+		if pErr := r.LiveFeed.Publish(ctx, guildID, events); pErr != nil {
+			ctx = logging.AddValues(ctx, zap.NamedError("error", pErr.Err), zap.String("error_message", pErr.Message))
+			logger := logging.Logger(ctx)
+			logger.Error("runner_log")
+		}
+	}
+
+	if r.EventStream != nil {
+		if pErr := r.EventStream.Publish(ctx, guildID, events); pErr != nil {
+			ctx = logging.AddValues(ctx, zap.NamedError("error", pErr.Err), zap.String("error_message", pErr.Message))
+			logger := logging.Logger(ctx)
+			logger.Error("runner_log")
+		}
+	}
+
+	if r.HTTPSink != nil {
+		r.HTTPSink.Publish(ctx, guildID, events)
+	}
+}
+
+// publishesEvents reports whether any event consumer is enabled
+func (r *Runners) publishesEvents() bool {
+	return r.HTTPSink != nil || r.LiveFeed != nil || r.EventStream != nil
+}
@@ -0,0 +1,351 @@
+package runners
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/jobqueue"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// requeueBatchSize is the most claimed jobs made ready again per check
+const requeueBatchSize = 100
+
+// logsJob is a logs request for one server.
+// A queued job re-reads the server's config when it runs, so the snapshot only identifies the server.
+type logsJob struct {
+	Server   gcscmodels.Server `json:"server"`
+	GetChat  bool              `json:"get_chat"`
+	GetAdmin bool              `json:"get_admin"`
+	GetKills bool              `json:"get_kills"`
+}
+
+// playersJob is a queued online players request for one server.
+// Like logsJob, the server's config is re-read when the job runs.
+type playersJob struct {
+	Server gcscmodels.Server `json:"server"`
+}
+
+// jobRunner runs a job's payload, returning an error if it should be tried again
+type jobRunner func(ctx context.Context, payload string) *Error
+
+// enqueueJob queues a server's job for the current interval.
+// It returns false with no error when a job for the server was already queued this interval.
+// Jobs are grouped by guild, so all of a guild's Discord output is sent from one instance at a time and keeps its order.
+func (r *Runners) enqueueJob(ctx context.Context, queue *jobqueue.Queue, server gcscmodels.Server, payload interface{}, interval time.Duration) (bool, *Error) {
+	queued, err := queue.Enqueue(ctx, fmt.Sprint(server.ID), server.GuildID, payload, interval)
+	if err != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", err.Err),
+			zap.String("error_message", err.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return false, &Error{
+			Message: err.Message,
+			Err:     err.Err,
+		}
+	}
+
+	if queued {
+		metrics.Jobs.WithLabelValues(queue.Name, "queued").Inc()
+	} else {
+		metrics.Jobs.WithLabelValues(queue.Name, "skipped").Inc()
+	}
+
+	return queued, nil
+}
+
+// startJobWorkers starts the workers that run queued jobs on this instance, whether or not it leads
+func (r *Runners) startJobWorkers(ctx context.Context) {
+	if r.LogsJobs != nil {
+		for i := 0; i < r.Config.Runners.Logs.Workers; i++ {
+			r.start("logs_jobs", func() { r.consume(ctx, r.LogsJobs, r.runLogsJob) })
+		}
+		r.start("logs_requeue", func() { r.requeue(ctx, r.LogsJobs) })
+	}
+
+	if r.PlayersJobs != nil {
+		for i := 0; i < r.Config.Runners.Players.Workers; i++ {
+			r.start("players_jobs", func() { r.consume(ctx, r.PlayersJobs, r.runPlayersJob) })
+		}
+		r.start("players_requeue", func() { r.requeue(ctx, r.PlayersJobs) })
+	}
+}
+
+// consume claims and runs jobs until the runners stop, waiting the poll interval whenever the queue is empty
+func (r *Runners) consume(ctx context.Context, queue *jobqueue.Queue, run jobRunner) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("job_queue", queue.Name),
+	)
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		job, err := queue.Claim(ctx)
+		if err != nil {
+			newCtx := logging.AddValues(ctx,
+				zap.NamedError("error", err.Err),
+				zap.String("error_message", err.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+		}
+
+		if job == nil {
+			if !r.sleep(r.Config.JobQueue.PollInterval * time.Second) {
+				return
+			}
+			continue
+		}
+
+		r.runJob(ctx, queue, job, run)
+	}
+}
+
+// runJob runs a claimed job and acks it, or holds it for the retry delay if it failed and has attempts left.
+// A job that panics is left claimed and is tried again once its visibility timeout passes.
+func (r *Runners) runJob(ctx context.Context, queue *jobqueue.Queue, job *jobqueue.Job, run jobRunner) {
+	ctx = logging.AddValues(ctx,
+		zap.String("request_id", uuid.New().String()),
+		zap.String("job_id", job.ID),
+		zap.Int("attempt", job.Attempts),
+	)
+	defer recovery.Recover(ctx, "runner:"+queue.Name, "")
+
+	result := "done"
+	switch {
+	case job.Payload == "":
+		// The job was acked after its visibility timeout passed, so this is a stale copy
+		result = "dropped"
+	case job.Attempts > queue.MaxAttempts:
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", fmt.Errorf("claimed %d times", job.Attempts)),
+			zap.String("error_message", "Dropped job that kept timing out"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		result = "dropped"
+	default:
+		stopHeartbeat := r.heartbeat(ctx, queue, job)
+		rErr := run(ctx, job.Payload)
+		stopHeartbeat()
+
+		if rErr != nil {
+			if job.Attempts < queue.MaxAttempts {
+				if reErr := queue.Retry(ctx, job, r.Config.JobQueue.RetryDelay*time.Second); reErr != nil {
+					newCtx := logging.AddValues(ctx,
+						zap.NamedError("error", reErr.Err),
+						zap.String("error_message", reErr.Message),
+					)
+					logger := logging.Logger(newCtx)
+					logger.Error("runner_log")
+				}
+				metrics.Jobs.WithLabelValues(queue.Name, "retried").Inc()
+				return
+			}
+			result = "failed"
+		}
+	}
+
+	if aErr := queue.Ack(ctx, job); aErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", aErr.Err),
+			zap.String("error_message", aErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+
+	metrics.Jobs.WithLabelValues(queue.Name, result).Inc()
+}
+
+// heartbeat extends a running job's visibility timeout every third of it until the returned func is called,
+// so a job that runs long is not handed to another worker and run twice
+func (r *Runners) heartbeat(ctx context.Context, queue *jobqueue.Queue, job *jobqueue.Job) func() {
+	done := make(chan struct{})
+	ticker := time.NewTicker(queue.Visibility / 3)
+
+	recovery.Go(ctx, "runner:"+queue.Name+":heartbeat", "", func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			extended, eErr := queue.Extend(ctx, job)
+			if eErr != nil {
+				newCtx := logging.AddValues(ctx,
+					zap.NamedError("error", eErr.Err),
+					zap.String("error_message", eErr.Message),
+				)
+				logger := logging.Logger(newCtx)
+				logger.Error("runner_log")
+				continue
+			}
+
+			if !extended {
+				newCtx := logging.AddValues(ctx,
+					zap.NamedError("error", errors.New("visibility timeout passed")),
+					zap.String("error_message", "Job was made ready again while it was still running"),
+				)
+				logger := logging.Logger(newCtx)
+				logger.Error("runner_log")
+				return
+			}
+		}
+	})
+
+	return func() {
+		close(done)
+	}
+}
+
+// jobServer re-reads a queued server's current config.
+// It returns nil with no error if the server or its guild has since been removed or disabled.
+func (r *Runners) jobServer(ctx context.Context, queued gcscmodels.Server) (*gcscmodels.Server, *Error) {
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, r.GuildConfigService, queued.GuildID)
+	if gfErr != nil {
+		return nil, &Error{
+			Message: gfErr.Message,
+			Err:     gfErr,
+		}
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+		return nil, nil
+	}
+
+	if !guildFeed.Payload.Guild.Enabled {
+		return nil, nil
+	}
+
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		if server.ID == queued.ID && server.Enabled {
+			return server, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// requeue makes jobs ready again once their visibility timeout or retry delay has passed, and records the queue depth
+func (r *Runners) requeue(ctx context.Context, queue *jobqueue.Queue) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("job_queue", queue.Name),
+	)
+
+	ticker := time.NewTicker(r.Config.JobQueue.PollInterval * time.Second)
+
+	for r.nextTick(ticker) {
+		if _, rqErr := queue.Requeue(ctx, requeueBatchSize); rqErr != nil {
+			newCtx := logging.AddValues(ctx,
+				zap.NamedError("error", rqErr.Err),
+				zap.String("error_message", rqErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+		}
+
+		depth, dErr := queue.Depth(ctx)
+		if dErr != nil {
+			continue
+		}
+		metrics.JobQueueDepth.WithLabelValues(queue.Name).Set(float64(depth))
+	}
+}
+
+// runLogsJob func
+func (r *Runners) runLogsJob(ctx context.Context, payload string) *Error {
+	var job logsJob
+	if err := json.Unmarshal([]byte(payload), &job); err != nil {
+		return &Error{
+			Message: "Failed to unmarshal logs job",
+			Err:     err,
+		}
+	}
+
+	ctx = logging.AddValues(ctx,
+		zap.String("guild_id", job.Server.GuildID),
+		zap.Uint64("server_id", job.Server.ID),
+		zap.Int64("server_nitrado_id", job.Server.NitradoID),
+	)
+
+	server, sErr := r.jobServer(ctx, job.Server)
+	if sErr != nil {
+		return sErr
+	}
+
+	// The server's poll was started on the schedule, so it is ended here when there is nothing left to poll
+	if server == nil {
+		r.releasePoll(ctx, r.LogsSchedule, job.Server)
+		return nil
+	}
+
+	current := logsJob{
+		Server:   *server,
+		GetChat:  outputChannel(*server, "chat") != nil,
+		GetAdmin: outputChannel(*server, "admin") != nil,
+		GetKills: outputChannel(*server, "kills") != nil,
+	}
+
+	if !current.GetChat && !current.GetAdmin && !current.GetKills {
+		r.releasePoll(ctx, r.LogsSchedule, job.Server)
+		return nil
+	}
+
+	return r.pollLogs(ctx, current)
+}
+
+// runPlayersJob is never retried since a failed poll is reported as the server being offline
+func (r *Runners) runPlayersJob(ctx context.Context, payload string) *Error {
+	var job playersJob
+	if err := json.Unmarshal([]byte(payload), &job); err != nil {
+		return &Error{
+			Message: "Failed to unmarshal players job",
+			Err:     err,
+		}
+	}
+
+	ctx = logging.AddValues(ctx,
+		zap.String("guild_id", job.Server.GuildID),
+		zap.Uint64("server_id", job.Server.ID),
+		zap.Int64("server_nitrado_id", job.Server.NitradoID),
+	)
+
+	server, sErr := r.jobServer(ctx, job.Server)
+	if sErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", sErr.Err),
+			zap.String("error_message", sErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return nil
+	}
+
+	if server == nil || outputChannel(*server, "players") == nil {
+		return nil
+	}
+
+	r.GetOnlinePlayersRequest(ctx, *server)
+	return nil
+}
@@ -0,0 +1,282 @@
+package runners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gammazero/workerpool"
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pvpstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// WeeklyLeaderboardPlayers const
+const WeeklyLeaderboardPlayers int = 10
+
+// WeeklyLeaderboardTribes const
+const WeeklyLeaderboardTribes int = 5
+
+// LeaderboardOutput struct
+type LeaderboardOutput struct {
+	Title  string
+	Entity string
+	Stats  []pvpstats.Stats
+}
+
+// RecordKillStats func
+func (r *Runners) RecordKillStats(ctx context.Context, server gcscmodels.Server, killLogs []nsv2.KillLog) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	rkErr := pvpstats.RecordKills(ctx, r.Cache, r.Config.CacheSettings.PvPStats, server.GuildID, server.NitradoID, killLogs)
+	if rkErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", rkErr.Err), zap.String("error_message", rkErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+
+		return &Error{
+			Message: rkErr.Message,
+			Err:     rkErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// Leaderboard posts the previous week's PvP leaderboard to each server's kill feed
+func (r *Runners) Leaderboard(ctx context.Context, delay time.Duration) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("runner", "leaderboard"),
+	)
+
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
+	}
+
+	ticker := time.NewTicker(r.Config.Runners.Leaderboard.Frequency * time.Second)
+
+	wp := workerpool.New(r.Config.Runners.Leaderboard.Workers)
+	defer wp.StopWait()
+
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			continue
+		}
+
+		requestID := uuid.New()
+		gCtx := logging.AddValues(ctx, zap.String("request_id", requestID.String()))
+
+		if wp.WaitingQueueSize() > 0 {
+			newCtx := logging.AddValues(gCtx,
+				zap.Int("queue_size", wp.WaitingQueueSize()),
+				zap.NamedError("error", errors.New("queue not empty")),
+				zap.String("error_message", "cannot start new leaderboard run with non-empty queue"),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			skipTick("leaderboard")
+			continue
+		}
+
+		r.leaderboardTick(gCtx, wp)
+	}
+}
+
+// leaderboardTick queues last week's leaderboard for every enabled server with a kill feed
+func (r *Runners) leaderboardTick(ctx context.Context, wp *workerpool.WorkerPool) {
+	defer recovery.Recover(ctx, "runner:leaderboard", "")
+
+	tick := startTick(ctx, "leaderboard", wp)
+	defer tick.finish()
+
+	lastWeek := pvpstats.PeriodKey(pvpstats.PeriodWeek, time.Now().AddDate(0, 0, -7))
+
+	allGuilds, agErr := guildconfigservice.GetAllGuilds(ctx, r.GuildConfigService)
+	if agErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", agErr),
+			zap.String("error_message", agErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if allGuilds.Payload == nil || allGuilds.Payload.Guilds == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil guilds")),
+			zap.String("error_message", "nil guilds in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	for _, aGuild := range allGuilds.Payload.Guilds {
+		agCtx := logging.AddValues(ctx, zap.String("guild_id", aGuild.ID))
+
+		if !aGuild.Enabled || !r.Shards.Owns(aGuild.ID) {
+			continue
+		}
+
+		guildFeed, gfErr := guildconfigservice.GetGuildFeed(agCtx, r.GuildConfigService, aGuild.ID)
+		if gfErr != nil {
+			newCtx := logging.AddValues(agCtx,
+				zap.NamedError("error", gfErr),
+				zap.String("error_message", gfErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+			continue
+		}
+
+		for _, server := range guildFeed.Payload.Guild.Servers {
+			serverCtx := logging.AddValues(agCtx,
+				zap.Uint64("server_id", server.ID),
+				zap.Int64("server_nitrado_id", server.NitradoID),
+			)
+
+			if !server.Enabled {
+				continue
+			}
+
+			var killLogOutputChannel *gcscmodels.ServerOutputChannel
+			for _, oc := range server.ServerOutputChannels {
+				if !oc.Enabled {
+					continue
+				}
+
+				if oc.OutputChannelTypeID == "kills" {
+					var tempKillLogOutputChannel gcscmodels.ServerOutputChannel = *oc
+					killLogOutputChannel = &tempKillLogOutputChannel
+					break
+				}
+			}
+
+			if killLogOutputChannel == nil {
+				continue
+			}
+
+			var aServer gcscmodels.Server = *server
+
+			tick.submit(func() {
+				r.WriteWeeklyLeaderboard(serverCtx, aServer, killLogOutputChannel, lastWeek)
+			})
+		}
+	}
+}
+
+// WriteWeeklyLeaderboard func
+func (r *Runners) WriteWeeklyLeaderboard(ctx context.Context, server gcscmodels.Server, killLogOutput *gcscmodels.ServerOutputChannel, weekKey string) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	settings := r.Config.CacheSettings.PvPLeaderboardPosts
+	postedKey := cache.GenerateKey(settings.Base, fmt.Sprintf("%s:%d:%s", server.GuildID, server.NitradoID, weekKey))
+	isNew, snxErr := r.Cache.SetNX(ctx, postedKey, "1", settings.TTL)
+	if snxErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", snxErr.Err), zap.String("error_message", snxErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if !isNew {
+		return
+	}
+
+	// The marker claims the week for this post; release it unless the post went out so the next tick retries
+	posted := false
+	defer func() {
+		if posted {
+			return
+		}
+
+		if delErr := r.Cache.Delete(ctx, postedKey); delErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", delErr.Err), zap.String("error_message", delErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+		}
+	}()
+
+	players, plErr := pvpstats.GetLeaderboard(ctx, r.Cache, r.Config.CacheSettings.PvPStats, server.GuildID, server.NitradoID, pvpstats.EntityPlayers, weekKey, WeeklyLeaderboardPlayers)
+	if plErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", plErr.Err), zap.String("error_message", plErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if len(players) == 0 {
+		posted = true
+		return
+	}
+
+	tribes, trErr := pvpstats.GetLeaderboard(ctx, r.Cache, r.Config.CacheSettings.PvPStats, server.GuildID, server.NitradoID, pvpstats.EntityTribes, weekKey, WeeklyLeaderboardTribes)
+	if trErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", trErr.Err), zap.String("error_message", trErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+		return
+	}
+
+	var embeddableFields []discordapi.EmbeddableField
+	var embeddableErrors []discordapi.EmbeddableField
+
+	embeddableFields = append(embeddableFields, &LeaderboardOutput{
+		Title:  "__Top Players__",
+		Entity: pvpstats.EntityPlayers,
+		Stats:  players,
+	}, &LeaderboardOutput{
+		Title:  "__Top Tribes__",
+		Entity: pvpstats.EntityTribes,
+		Stats:  tribes,
+	})
+
+	if _, loErr := r.LogsOutput(ctx, RunnerOutputParams{
+		Title:       fmt.Sprintf("%s - Weekly PvP Leaderboard", server.Name),
+		Description: "Top killers from last week's kill feed. All time is in UTC.",
+	}, *killLogOutput, server, embeddableFields, embeddableErrors); loErr != nil {
+		return
+	}
+
+	posted = true
+}
+
+// ConvertToEmbedField for LeaderboardOutput struct
+func (lo *LeaderboardOutput) ConvertToEmbedField() (*discordgo.MessageEmbedField, *discordapi.Error) {
+	fieldVal := ""
+
+	for key, stats := range lo.Stats {
+		name := discordapi.EscapeText(stats.Name)
+
+		fieldVal += fmt.Sprintf("**%d. %s** - %d kills / %d deaths (K/D %.2f)", key+1, name, stats.Kills, stats.Deaths, stats.KD())
+		if lo.Entity == pvpstats.EntityTribes && stats.TamedKills > 0 {
+			fieldVal += fmt.Sprintf(" - %d tamed dino kills", stats.TamedKills)
+		}
+		fieldVal += "\n"
+	}
+
+	if fieldVal == "" {
+		fieldVal = "No PvP data..."
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   lo.Title,
+		Value:  fieldVal + "\u200b",
+		Inline: false,
+	}, nil
+}
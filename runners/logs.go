@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -12,8 +11,12 @@ import (
 	"github.com/google/uuid"
 	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/arkcommands"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
@@ -22,18 +25,27 @@ import (
 type AdminLogsSuccessOutput struct {
 	Data      []AdminLogData
 	Timestamp int64
+	Entries   int
 }
 
 // AdminLogData struct
 type AdminLogData struct {
-	Command string
-	Name    string
+	Commands []AdminCommandData
+	Name     string
+}
+
+// AdminCommandData struct
+type AdminCommandData struct {
+	Readable string
+	Raw      string
+	Flagged  bool
 }
 
 // ChatLogsSuccessOutput
 type ChatLogsSuccessOutput struct {
 	Data      []ChatLogData
 	Timestamp int64
+	Entries   int
 }
 
 // ChatLogData struct
@@ -77,15 +89,27 @@ func (r *Runners) Logs(ctx context.Context, delay time.Duration) {
 		zap.String("runner", "logs"),
 	)
 
-	if delay != 0 {
-		time.Sleep(time.Second * delay)
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
+	}
+
+	wp := workerpool.New(r.Config.Runners.Logs.Workers)
+	defer wp.StopWait()
+
+	if r.LogsSchedule != nil {
+		r.runSchedule(ctx, r.LogsSchedule, r.logsServers, func(serverCtx context.Context, s scheduledServer) {
+			r.dispatchLogs(serverCtx, wp, s.job.(logsJob))
+		})
+		return
 	}
 
 	ticker := time.NewTicker(r.Config.Runners.Logs.Frequency * time.Second)
 
-	wp := workerpool.New(r.Config.Runners.Logs.Workers)
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			continue
+		}
 
-	for range ticker.C {
 		requestID := uuid.New()
 		gCtx := logging.AddValues(ctx, zap.String("request_id", requestID.String()))
 
@@ -97,6 +121,7 @@ func (r *Runners) Logs(ctx context.Context, delay time.Duration) {
 			)
 			logger := logging.Logger(newCtx)
 			logger.Error("runner_log")
+			skipTick("logs")
 			continue
 		} else {
 			newCtx := logging.AddValues(gCtx, zap.String("runner_message", "Started log runner"))
@@ -104,146 +129,187 @@ func (r *Runners) Logs(ctx context.Context, delay time.Duration) {
 			logger.Info("runner_log")
 		}
 
-		allGuilds, agErr := guildconfigservice.GetAllGuilds(gCtx, r.GuildConfigService)
-		if agErr != nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", agErr),
-				zap.String("error_message", agErr.Message),
-			)
-			logger := logging.Logger(newCtx)
-			logger.Error("runner_log")
+		r.logsTick(gCtx, wp)
+	}
+}
+
+// logsTick queues a logs request for every enabled server with a log output channel,
+// on the shared job queue when it is enabled and otherwise on this instance's workerpool
+func (r *Runners) logsTick(ctx context.Context, wp *workerpool.WorkerPool) {
+	defer recovery.Recover(ctx, "runner:logs", "")
+
+	tick := startTick(ctx, "logs", wp)
+	defer tick.finish()
+
+	r.eachLogsServer(ctx, func(serverCtx context.Context, job logsJob) {
+		if r.LogsJobs != nil {
+			r.enqueueJob(serverCtx, r.LogsJobs, job.Server, job, r.Config.Runners.Logs.Frequency*time.Second)
+			return
+		}
+
+		tick.submit(func() {
+			r.GetLogsRequest(serverCtx, job.Server, job.GetChat, job.GetAdmin, job.GetKills)
+		})
+	})
+}
+
+// logsServers lists the servers on the logs schedule
+func (r *Runners) logsServers(ctx context.Context) (map[string]scheduledServer, bool) {
+	servers := make(map[string]scheduledServer)
+
+	complete := r.eachLogsServer(ctx, func(serverCtx context.Context, job logsJob) {
+		servers[fmt.Sprint(job.Server.ID)] = scheduledServer{
+			ctx:    serverCtx,
+			server: job.Server,
+			job:    job,
+		}
+	})
+
+	return servers, complete
+}
+
+// dispatchLogs runs a scheduled logs poll on the shared job queue when it is enabled and otherwise on this instance's workerpool
+func (r *Runners) dispatchLogs(ctx context.Context, wp *workerpool.WorkerPool, job logsJob) {
+	if r.LogsJobs != nil {
+		// The poll was started on the schedule, so it has to be ended here if no job will run it
+		queued, eErr := r.enqueueJob(ctx, r.LogsJobs, job.Server, job, r.Config.Runners.Logs.Frequency*time.Second)
+		if eErr != nil {
+			r.finishPoll(ctx, r.LogsSchedule, job.Server, 0, true)
+		} else if !queued {
+			r.releasePoll(ctx, r.LogsSchedule, job.Server)
+		}
+		return
+	}
+
+	wp.Submit(recovery.Wrap(ctx, "runner:logs", func() {
+		r.pollLogs(ctx, job)
+	}))
+}
+
+// pollLogs fetches a server's logs and records the poll on the logs schedule
+func (r *Runners) pollLogs(ctx context.Context, job logsJob) *Error {
+	start := time.Now()
+	err := r.GetLogsRequest(ctx, job.Server, job.GetChat, job.GetAdmin, job.GetKills)
+	r.finishPoll(ctx, r.LogsSchedule, job.Server, time.Since(start), err != nil)
+
+	return err
+}
+
+// eachLogsServer calls visit for every enabled server with a log output channel.
+// It returns false if the guild list or any guild's feed could not be read.
+func (r *Runners) eachLogsServer(ctx context.Context, visit func(ctx context.Context, job logsJob)) bool {
+	allGuilds, agErr := guildconfigservice.GetAllGuilds(ctx, r.GuildConfigService)
+	if agErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", agErr),
+			zap.String("error_message", agErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return false
+	}
+
+	if allGuilds.Payload == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil payload")),
+			zap.String("error_message", "nil payload in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return false
+	}
+
+	if allGuilds.Payload.Guilds == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil guilds")),
+			zap.String("error_message", "nil guilds in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return false
+	}
+
+	complete := true
+
+	for _, aGuild := range allGuilds.Payload.Guilds {
+		agCtx := logging.AddValues(ctx, zap.String("guild_id", aGuild.ID))
+
+		if !aGuild.Enabled || !r.Shards.Owns(aGuild.ID) {
 			continue
 		}
 
-		if allGuilds.Payload == nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", errors.New("nil payload")),
-				zap.String("error_message", "nil payload in all guilds request"),
+		guildFeed, gfErr := guildconfigservice.GetGuildFeed(agCtx, r.GuildConfigService, aGuild.ID)
+		if gfErr != nil {
+			newCtx := logging.AddValues(agCtx,
+				zap.NamedError("error", gfErr),
+				zap.String("error_message", gfErr.Message),
 			)
 			logger := logging.Logger(newCtx)
 			logger.Error("runner_log")
+			complete = false
 			continue
 		}
 
-		if allGuilds.Payload.Guilds == nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", errors.New("nil guilds")),
-				zap.String("error_message", "nil guilds in all guilds request"),
-			)
-			logger := logging.Logger(newCtx)
-			logger.Error("runner_log")
+		if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+			// newCtx := logging.AddValues(agCtx,
+			// 	zap.NamedError("error", vErr),
+			// 	zap.String("error_message", vErr.Message),
+			// )
+			// logger := logging.Logger(newCtx)
+			// logger.Info("runner_log")
 			continue
 		}
 
-		for _, aGuild := range allGuilds.Payload.Guilds {
-			agCtx := logging.AddValues(gCtx, zap.String("guild_id", aGuild.ID))
+		for _, server := range guildFeed.Payload.Guild.Servers {
+			serverCtx := logging.AddValues(agCtx,
+				zap.Uint64("server_id", server.ID),
+				zap.Int64("server_nitrado_id", server.NitradoID),
+			)
 
-			if !aGuild.Enabled {
+			if !server.Enabled {
 				continue
 			}
 
-			guildFeed, gfErr := guildconfigservice.GetGuildFeed(agCtx, r.GuildConfigService, aGuild.ID)
-			if gfErr != nil {
-				newCtx := logging.AddValues(agCtx,
-					zap.NamedError("error", gfErr),
-					zap.String("error_message", gfErr.Message),
-				)
-				logger := logging.Logger(newCtx)
-				logger.Error("runner_log")
+			if len(server.ServerOutputChannels) == 0 {
 				continue
 			}
 
-			if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
-				// newCtx := logging.AddValues(agCtx,
-				// 	zap.NamedError("error", vErr),
-				// 	zap.String("error_message", vErr.Message),
-				// )
-				// logger := logging.Logger(newCtx)
-				// logger.Info("runner_log")
+			getChat := outputChannel(*server, "chat") != nil
+			getAdmin := outputChannel(*server, "admin") != nil
+			getKills := outputChannel(*server, "kills") != nil
+
+			if !getChat && !getAdmin && !getKills {
 				continue
 			}
 
-			for _, server := range guildFeed.Payload.Guild.Servers {
-				serverCtx := logging.AddValues(agCtx,
-					zap.Uint64("server_id", server.ID),
-					zap.Int64("server_nitrado_id", server.NitradoID),
-				)
-
-				if !server.Enabled {
-					continue
-				}
-
-				if len(server.ServerOutputChannels) == 0 {
-					continue
-				}
-
-				var adminLogOutputChannel *gcscmodels.ServerOutputChannel
-				var chatLogOutputChannel *gcscmodels.ServerOutputChannel
-				var killLogOutputChannel *gcscmodels.ServerOutputChannel
-				for _, oc := range server.ServerOutputChannels {
-					if !oc.Enabled {
-						continue
-					}
-
-					if oc.OutputChannelTypeID == "admin" {
-						var tempAdminLogOutputChannel gcscmodels.ServerOutputChannel = *oc
-						adminLogOutputChannel = &tempAdminLogOutputChannel
-					}
-
-					if oc.OutputChannelTypeID == "chat" {
-						var tempChatLogOutputChannel gcscmodels.ServerOutputChannel = *oc
-						chatLogOutputChannel = &tempChatLogOutputChannel
-					}
-
-					if oc.OutputChannelTypeID == "kills" {
-						var tempKillLogOutputChannel gcscmodels.ServerOutputChannel = *oc
-						killLogOutputChannel = &tempKillLogOutputChannel
-					}
-
-					if adminLogOutputChannel != nil && chatLogOutputChannel != nil && killLogOutputChannel != nil {
-						break
-					}
-				}
-
-				getChat := false
-				getAdmin := false
-				getKills := false
-
-				if chatLogOutputChannel != nil {
-					getChat = true
-				}
-
-				if adminLogOutputChannel != nil {
-					getAdmin = true
-				}
-
-				if killLogOutputChannel != nil {
-					getKills = true
-				}
-
-				var aServer gcscmodels.Server = *server
-
-				wp.Submit(func() {
-					r.GetLogsRequest(serverCtx, aServer, adminLogOutputChannel, chatLogOutputChannel, killLogOutputChannel, getChat, getAdmin, getKills)
-				})
-			}
+			visit(serverCtx, logsJob{
+				Server:   *server,
+				GetChat:  getChat,
+				GetAdmin: getAdmin,
+				GetKills: getKills,
+			})
 		}
 	}
+
+	return complete
 }
 
-// GetLogsRequest func
-func (r *Runners) GetLogsRequest(ctx context.Context, server gcscmodels.Server, adminLogOutput *gcscmodels.ServerOutputChannel, chatLogOutput *gcscmodels.ServerOutputChannel, killLogOutput *gcscmodels.ServerOutputChannel, getChat bool, getAdmin bool, getKills bool) {
+// GetLogsRequest fetches a server's logs and publishes them as one batch on the event bus
+func (r *Runners) GetLogsRequest(ctx context.Context, server gcscmodels.Server, getChat bool, getAdmin bool, getKills bool) *Error {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	logs, err := r.NitradoService.Client.GetLogs(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), getChat, getAdmin, getKills, true)
+	logs, err := r.NitradoService.GetLogs(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), getChat, getAdmin, getKills, true)
 	if err != nil {
 		ctx = logging.AddValues(ctx,
 			zap.NamedError("error", err),
-			zap.String("error_message", err.Message()),
+			zap.String("error_message", err.Message),
 		)
 		logger := logging.Logger(ctx)
 		logger.Error("runner_log")
-		return
+		return &Error{
+			Message: err.Message,
+			Err:     err,
+		}
 	}
 
 	// FOR TESTING
@@ -260,51 +326,98 @@ func (r *Runners) GetLogsRequest(ctx context.Context, server gcscmodels.Server,
 	// 	})
 	// }
 
-	if adminLogOutput != nil && len(logs.AdminLogs) > 0 {
-		go r.WriteAdminLogs(ctx, server, adminLogOutput, logs.AdminLogs)
+	events := []eventbus.Event{
+		eventbus.LogsFetched{
+			Chat:  getChat,
+			Admin: getAdmin,
+			Kills: getKills,
+		},
+	}
+
+	for _, entry := range logs.AdminLogs {
+		events = append(events, eventbus.AdminCommand{Log: entry})
+	}
+
+	for _, entry := range logs.PlayerLogs {
+		events = append(events, eventbus.ChatMessage{Log: entry})
 	}
 
-	if chatLogOutput != nil && len(logs.PlayerLogs) > 0 {
-		go r.WriteChatLogs(ctx, server, chatLogOutput, logs.PlayerLogs)
+	for _, entry := range logs.KillLogs {
+		events = append(events, eventbus.Kill{Log: entry})
 	}
 
-	if killLogOutput != nil && len(logs.KillLogs) > 0 {
-		go r.WriteKillLogs(ctx, server, killLogOutput, logs.KillLogs)
+	if pErr := r.EventBus.Publish(ctx, server, events...); pErr != nil {
+		return &Error{
+			Message: pErr.Message,
+			Err:     pErr.Err,
+		}
 	}
+
+	return nil
 }
 
-// WriteAdminLogs func
-func (r *Runners) WriteAdminLogs(ctx context.Context, server gcscmodels.Server, chatLogOutput *gcscmodels.ServerOutputChannel, adminLogs []nsv2.AdminLog) {
+// RecordLogStats func
+func (r *Runners) RecordLogStats(ctx context.Context, server gcscmodels.Server, logs nsv2.GetLogsResponse) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	rlErr := serverstats.RecordLogs(ctx, r.Cache, r.Config.CacheSettings.ServerStats, server.GuildID, server.NitradoID, len(logs.PlayerLogs), len(logs.AdminLogs), len(logs.KillLogs))
+	if rlErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", rlErr.Err), zap.String("error_message", rlErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+
+		return &Error{
+			Message: rlErr.Message,
+			Err:     rlErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// WriteAdminLogs posts admin logs and returns how many of them Discord accepted and how many wait in the outbox
+func (r *Runners) WriteAdminLogs(ctx context.Context, server gcscmodels.Server, chatLogOutput *gcscmodels.ServerOutputChannel, adminLogs []nsv2.AdminLog, receipt string) LogsDelivery {
 	var outputs []AdminLogsSuccessOutput
 	var output AdminLogsSuccessOutput
 
 	var embedFieldCharacterCount int = 50 // Set to 50 to account for embed field titles
 	var prevPlayerName string = ""
 	for _, entry := range adminLogs {
-		name := strings.Replace(entry.Name, "_", "\\_", -1)
-		name = strings.Replace(name, "*", "\\*", -1)
-		command := strings.Replace(entry.Command, "_", "\\_", -1)
-		command = strings.Replace(command, "*", "\\*", -1)
+		name := discordapi.EscapeText(entry.Name)
+
+		parsed := arkcommands.Parse(entry.Command)
+		readable := discordapi.EscapeText(parsed.Readable())
+		command := AdminCommandData{
+			Readable: readable,
+			Raw:      discordapi.EscapeCode(entry.Command),
+			Flagged:  parsed.IsFlagged(r.Config.AdminLogs.FlaggedCommands),
+		}
+		commandLength := discordapi.TextLength(command.Readable) + discordapi.TextLength(command.Raw) + 10
+
 		data := AdminLogData{
-			Command: command,
-			Name:    name,
+			Commands: []AdminCommandData{
+				command,
+			},
+			Name: name,
 		}
 
 		if output.Timestamp == 0 {
 			output.Timestamp = entry.Timestamp
 		}
 
-		if prevPlayerName == name && embedFieldCharacterCount+len(command)+2 < MaxEmbedFieldSize && len(output.Data) > 0 {
+		if prevPlayerName == name && embedFieldCharacterCount+commandLength+2 < MaxEmbedFieldSize && len(output.Data) > 0 {
 			prevData := output.Data[len(output.Data)-1]
-			prevData.Command += "\n" + command
+			prevData.Commands = append(prevData.Commands, command)
 			output.Data[len(output.Data)-1] = prevData
-			embedFieldCharacterCount += len(command) + 2
+			output.Entries++
+			embedFieldCharacterCount += commandLength + 2
 			continue
 		}
 
-		if embedFieldCharacterCount+len(command)+len(name)+10 < MaxEmbedFieldSize {
+		if embedFieldCharacterCount+commandLength+discordapi.TextLength(name)+10 < MaxEmbedFieldSize {
 			output.Data = append(output.Data, data)
-			embedFieldCharacterCount += len(command) + len(name) + 10
+			output.Entries++
+			embedFieldCharacterCount += commandLength + discordapi.TextLength(name) + 10
 		} else {
 			outputs = append(outputs, output)
 			output = AdminLogsSuccessOutput{
@@ -312,6 +425,7 @@ func (r *Runners) WriteAdminLogs(ctx context.Context, server gcscmodels.Server,
 					data,
 				},
 				Timestamp: entry.Timestamp,
+				Entries:   1,
 			}
 			embedFieldCharacterCount = 50
 		}
@@ -325,36 +439,38 @@ func (r *Runners) WriteAdminLogs(ctx context.Context, server gcscmodels.Server,
 
 	var embeddableFields []discordapi.EmbeddableField
 	var embeddableErrors []discordapi.EmbeddableField
+	var fieldEntries []int
 
 	if len(outputs) == 0 {
-		return
+		return LogsDelivery{}
 	}
 
 	for i := 0; i < len(outputs); i++ {
 		embeddableFields = append(embeddableFields, &outputs[i])
+		fieldEntries = append(fieldEntries, outputs[i].Entries)
 	}
 
 	freq := r.Config.Runners.Logs.Frequency * time.Second
-	r.LogsOutput(ctx, RunnerOutputParams{
+	result, _ := r.LogsOutput(ctx, RunnerOutputParams{
 		Title:       server.Name,
 		Description: fmt.Sprintf("Admin logs are retrieved every %.1f minutes.", freq.Seconds()/60),
+		Receipt:     receipt,
 	}, *chatLogOutput, server, embeddableFields, embeddableErrors)
+
+	return outputDelivery(result, fieldEntries)
 }
 
-// WriteChatLogs func
-func (r *Runners) WriteChatLogs(ctx context.Context, server gcscmodels.Server, chatLogOutput *gcscmodels.ServerOutputChannel, chatLogs []nsv2.PlayerLog) {
+// WriteChatLogs posts chat logs and returns how many of them Discord accepted and how many wait in the outbox
+func (r *Runners) WriteChatLogs(ctx context.Context, server gcscmodels.Server, chatLogOutput *gcscmodels.ServerOutputChannel, chatLogs []nsv2.PlayerLog, receipt string) LogsDelivery {
 	var outputs []ChatLogsSuccessOutput
 	var output ChatLogsSuccessOutput
 
 	var embedFieldCharacterCount int = 50 // Set to 50 to account for embed field titles
 	var prevPlayerGT string = ""
 	for _, entry := range chatLogs {
-		name := strings.Replace(entry.Name, "_", "\\_", -1)
-		name = strings.Replace(name, "*", "\\*", -1)
-		gt := strings.Replace(entry.Gamertag, "_", "\\_", -1)
-		gt = strings.Replace(gt, "*", "\\*", -1)
-		message := strings.Replace(entry.Message, "_", "\\_", -1)
-		message = strings.Replace(message, "*", "\\*", -1)
+		name := discordapi.EscapeText(entry.Name)
+		gt := discordapi.EscapeText(entry.Gamertag)
+		message := discordapi.EscapeText(entry.Message)
 		data := ChatLogData{
 			Gamertag: gt,
 			Name:     name,
@@ -365,17 +481,19 @@ func (r *Runners) WriteChatLogs(ctx context.Context, server gcscmodels.Server, c
 			output.Timestamp = entry.Timestamp
 		}
 
-		if prevPlayerGT == gt && embedFieldCharacterCount+len(message)+2 < MaxEmbedFieldSize && len(output.Data) > 0 {
+		if prevPlayerGT == gt && embedFieldCharacterCount+discordapi.TextLength(message)+2 < MaxEmbedFieldSize && len(output.Data) > 0 {
 			prevData := output.Data[len(output.Data)-1]
 			prevData.Message += "\n" + message
 			output.Data[len(output.Data)-1] = prevData
-			embedFieldCharacterCount += len(message) + 2
+			output.Entries++
+			embedFieldCharacterCount += discordapi.TextLength(message) + 2
 			continue
 		}
 
-		if embedFieldCharacterCount+len(gt)+len(name)+len(message)+10 < MaxEmbedFieldSize {
+		if embedFieldCharacterCount+discordapi.TextLength(gt)+discordapi.TextLength(name)+discordapi.TextLength(message)+10 < MaxEmbedFieldSize {
 			output.Data = append(output.Data, data)
-			embedFieldCharacterCount += len(gt) + len(name) + len(message) + 10
+			output.Entries++
+			embedFieldCharacterCount += discordapi.TextLength(gt) + discordapi.TextLength(name) + discordapi.TextLength(message) + 10
 		} else {
 			outputs = append(outputs, output)
 			output = ChatLogsSuccessOutput{
@@ -383,6 +501,7 @@ func (r *Runners) WriteChatLogs(ctx context.Context, server gcscmodels.Server, c
 					data,
 				},
 				Timestamp: entry.Timestamp,
+				Entries:   1,
 			}
 			embedFieldCharacterCount = 50
 		}
@@ -396,49 +515,53 @@ func (r *Runners) WriteChatLogs(ctx context.Context, server gcscmodels.Server, c
 
 	var embeddableFields []discordapi.EmbeddableField
 	var embeddableErrors []discordapi.EmbeddableField
+	var fieldEntries []int
 
 	if len(outputs) == 0 {
-		return
+		return LogsDelivery{}
 	}
 
 	for i := 0; i < len(outputs); i++ {
 		embeddableFields = append(embeddableFields, &outputs[i])
+		fieldEntries = append(fieldEntries, outputs[i].Entries)
 	}
 
 	freq := r.Config.Runners.Logs.Frequency * time.Second
-	r.LogsOutput(ctx, RunnerOutputParams{
+	result, _ := r.LogsOutput(ctx, RunnerOutputParams{
 		Title:       server.Name,
 		Description: fmt.Sprintf("Chat logs are retrieved every %.1f minutes.", freq.Seconds()/60),
+		Receipt:     receipt,
 	}, *chatLogOutput, server, embeddableFields, embeddableErrors)
+
+	return outputDelivery(result, fieldEntries)
 }
 
-// WriteChatLogs func
-func (r *Runners) WriteKillLogs(ctx context.Context, server gcscmodels.Server, killLogOutput *gcscmodels.ServerOutputChannel, killLogs []nsv2.KillLog) {
+// WriteKillLogs posts kill logs and returns how many of them Discord accepted and how many wait in the outbox
+func (r *Runners) WriteKillLogs(ctx context.Context, server gcscmodels.Server, killLogOutput *gcscmodels.ServerOutputChannel, killLogs []nsv2.KillLog, receipt string) LogsDelivery {
 	var miniOutputs []KillLogsSuccessOutputMini
 	var miniOutput KillLogsSuccessOutputMini
 
 	var embeddableFields []discordapi.EmbeddableField
 	var embeddableErrors []discordapi.EmbeddableField
+	var fieldEntries []int
 
 	minified := len(killLogs) > 320
 
 	var embedFieldCharacterCount int = 20 // Set to 20 to account for descriptive text
 	for _, entry := range killLogs {
-		killedName := strings.Replace(entry.KilledName, "_", "\\_", -1)
-		killedName = strings.Replace(killedName, "*", "\\*", -1)
-		killerName := strings.Replace(entry.KillerName, "_", "\\_", -1)
-		killerName = strings.Replace(killerName, "*", "\\*", -1)
+		killedName := discordapi.EscapeText(entry.KilledName)
+		killerName := discordapi.EscapeText(entry.KillerName)
 
 		data := KillLogData{
 			PvEKill:        entry.PvEKill,
 			KilledName:     killedName,
 			KilledLevel:    entry.KilledLevel,
-			KilledDinoType: entry.KilledDinoType,
-			KilledTribe:    entry.KilledTribe,
+			KilledDinoType: discordapi.EscapeText(entry.KilledDinoType),
+			KilledTribe:    discordapi.EscapeText(entry.KilledTribe),
 			KillerName:     killerName,
 			KillerLevel:    entry.KillerLevel,
-			KillerDinoType: entry.KillerDinoType,
-			KillerTribe:    entry.KillerTribe,
+			KillerDinoType: discordapi.EscapeText(entry.KillerDinoType),
+			KillerTribe:    discordapi.EscapeText(entry.KillerTribe),
 			Timestamp:      entry.Timestamp,
 		}
 
@@ -446,6 +569,7 @@ func (r *Runners) WriteKillLogs(ctx context.Context, server gcscmodels.Server, k
 			embeddableFields = append(embeddableFields, &KillLogsSuccessOutputFull{
 				Data: data,
 			})
+			fieldEntries = append(fieldEntries, 1)
 			continue
 		}
 
@@ -453,7 +577,7 @@ func (r *Runners) WriteKillLogs(ctx context.Context, server gcscmodels.Server, k
 			miniOutput.Timestamp = entry.Timestamp
 		}
 
-		outputLength := len(killedName) + len(entry.KilledDinoType) + len(entry.KilledTribe) + len(killerName) + len(entry.KillerDinoType) + len(entry.KillerTribe)
+		outputLength := discordapi.TextLength(data.KilledName) + discordapi.TextLength(data.KilledDinoType) + discordapi.TextLength(data.KilledTribe) + discordapi.TextLength(data.KillerName) + discordapi.TextLength(data.KillerDinoType) + discordapi.TextLength(data.KillerTribe)
 
 		if embedFieldCharacterCount+outputLength+40 < MaxEmbedFieldSize {
 			miniOutput.Data = append(miniOutput.Data, data)
@@ -475,25 +599,30 @@ func (r *Runners) WriteKillLogs(ctx context.Context, server gcscmodels.Server, k
 	}
 
 	if !minified {
-		r.LogsOutput(ctx, RunnerOutputParams{
+		result, _ := r.LogsOutput(ctx, RunnerOutputParams{
 			Title:       server.Name,
 			Description: fmt.Sprintf("PvP kill feed for %s. All time is in UTC.", time.Now().UTC().Format("January 2, 2006")),
+			Receipt:     receipt,
 		}, *killLogOutput, server, embeddableFields, embeddableErrors)
-		return
+		return outputDelivery(result, fieldEntries)
 	}
 
 	if len(miniOutputs) == 0 {
-		return
+		return LogsDelivery{}
 	}
 
 	for i := 0; i < len(miniOutputs); i++ {
 		embeddableFields = append(embeddableFields, &miniOutputs[i])
+		fieldEntries = append(fieldEntries, len(miniOutputs[i].Data))
 	}
 
-	r.LogsOutput(ctx, RunnerOutputParams{
+	result, _ := r.LogsOutput(ctx, RunnerOutputParams{
 		Title:       server.Name,
 		Description: fmt.Sprintf("PvP kill feed for %s. All time is in UTC.", time.Now().UTC().Format("January 2, 2006")),
+		Receipt:     receipt,
 	}, *killLogOutput, server, embeddableFields, embeddableErrors)
+
+	return outputDelivery(result, fieldEntries)
 }
 
 // ConvertToEmbedField for NameServerOutput struct
@@ -502,7 +631,15 @@ func (bps *AdminLogsSuccessOutput) ConvertToEmbedField() (*discordgo.MessageEmbe
 
 	for _, entry := range bps.Data {
 		fieldVal += fmt.Sprintf("\n**%s**", entry.Name)
-		fieldVal += "\n" + entry.Command + "\n"
+		for _, command := range entry.Commands {
+			if command.Flagged {
+				fieldVal += fmt.Sprintf("\n⚠️ **%s**", command.Readable)
+			} else {
+				fieldVal += "\n" + command.Readable
+			}
+			fieldVal += fmt.Sprintf("\n`%s`", command.Raw)
+		}
+		fieldVal += "\n"
 	}
 
 	if fieldVal == "" {
@@ -0,0 +1,237 @@
+package runners
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gammazero/workerpool"
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outbox"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// Discord error codes that will not succeed on retry
+var permanentDiscordErrors = map[int]bool{
+	10003: true, // Unknown Channel
+	10008: true, // Unknown Message
+	50001: true, // Missing Access
+	50013: true, // Missing Permissions
+}
+
+// Outbox retries queued Discord messages that failed to send, oldest first per channel
+func (r *Runners) Outbox(ctx context.Context, delay time.Duration) {
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("runner", "outbox"),
+	)
+
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
+	}
+
+	ticker := time.NewTicker(r.Config.Runners.Outbox.Frequency * time.Second)
+
+	wp := workerpool.New(r.Config.Runners.Outbox.Workers)
+	defer wp.StopWait()
+
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			continue
+		}
+
+		requestID := uuid.New()
+		gCtx := logging.AddValues(ctx, zap.String("request_id", requestID.String()))
+
+		r.outboxTick(gCtx, wp)
+	}
+}
+
+// outboxTick drains every channel with queued messages that are due
+func (r *Runners) outboxTick(ctx context.Context, wp *workerpool.WorkerPool) {
+	defer recovery.Recover(ctx, "runner:outbox", "")
+
+	tick := startTick(ctx, "outbox", wp)
+	defer tick.finish()
+
+	channelIDs, dErr := outbox.Due(ctx, r.Cache, r.Config.CacheSettings.Outbox, time.Now(), r.Config.Outbox.BatchSize)
+	if dErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", dErr.Err),
+			zap.String("error_message", dErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	// Wait for every channel to drain so a channel is never sent from twice at once
+	var wg sync.WaitGroup
+	for _, channelID := range channelIDs {
+		aChannelID := channelID
+		wg.Add(1)
+		tick.submit(func() {
+			defer wg.Done()
+			r.DrainOutboxChannel(logging.AddValues(ctx, zap.String("channel_id", aChannelID)), aChannelID)
+		})
+	}
+	wg.Wait()
+}
+
+// DrainOutboxChannel sends a channel's queued messages in order until one fails
+func (r *Runners) DrainOutboxChannel(ctx context.Context, channelID string) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	settings := r.Config.CacheSettings.Outbox
+
+	for i := 0; i < r.Config.Outbox.BatchSize; i++ {
+		msg, hErr := outbox.Head(ctx, r.Cache, settings, channelID)
+		if hErr != nil {
+			r.logOutboxError(ctx, hErr)
+			return
+		}
+
+		if msg == nil {
+			return
+		}
+
+		msgCtx := logging.AddValues(ctx, zap.String("outbox_message_id", msg.ID), zap.String("guild_id", msg.GuildID))
+
+		embed := msg.Embed
+		var sendErr *discordapi.Error
+		switch {
+		case msg.EditMessageID != "":
+			_, sendErr = r.Scheduler.EditEmbed(msg.GuildID, msg.ChannelID, msg.EditMessageID, embed)
+		case msg.ServerID != 0:
+			_, sendErr = r.SendOutputEmbeds(msgCtx, gcscmodels.Server{
+				ID:      msg.ServerID,
+				GuildID: msg.GuildID,
+				Name:    msg.ServerName,
+			}, gcscmodels.ServerOutputChannel{
+				ChannelID: msg.ChannelID,
+			}, []discordgo.MessageEmbed{embed})
+		default:
+			_, sendErr = r.Scheduler.SendEmbed(msg.GuildID, msg.ChannelID, embed)
+		}
+
+		if sendErr == nil {
+			if aErr := outbox.Ack(msgCtx, r.Cache, settings, *msg); aErr != nil {
+				r.logOutboxError(msgCtx, aErr)
+				return
+			}
+			continue
+		}
+
+		msg.Attempts++
+		msg.LastError = sendErr.Message
+
+		if permanentDiscordErrors[sendErr.Code] || msg.Attempts >= r.Config.Outbox.MaxAttempts {
+			newCtx := logging.AddValues(msgCtx,
+				zap.NamedError("error", sendErr.Err),
+				zap.String("error_message", "Moved outbox message to dead letters: "+sendErr.Message),
+				zap.Int("status_code", sendErr.Code),
+				zap.Int("attempts", msg.Attempts),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+
+			if dlErr := outbox.DeadLetter(msgCtx, r.Cache, settings, *msg, r.Config.Outbox.MaxDeadLetters); dlErr != nil {
+				r.logOutboxError(msgCtx, dlErr)
+				return
+			}
+			continue
+		}
+
+		wait := outbox.Backoff(msg.Attempts, r.Config.Outbox.BaseBackoff*time.Second, r.Config.Outbox.MaxBackoff*time.Second)
+		if sendErr.RetryAfter > wait {
+			wait = sendErr.RetryAfter
+		}
+
+		if rErr := outbox.Retry(msgCtx, r.Cache, settings, *msg, time.Now().Add(wait)); rErr != nil {
+			r.logOutboxError(msgCtx, rErr)
+		}
+		return
+	}
+}
+
+// QueueEmbeds adds a server's output embeds to a channel's outbox under a receipt, which may be empty, and returns their outbox message IDs
+func (r *Runners) QueueEmbeds(ctx context.Context, server gcscmodels.Server, channelID string, receipt string, embeds []discordgo.MessageEmbed) ([]string, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !r.Config.CacheSettings.Outbox.Enabled {
+		return nil, &Error{
+			Message: "Outbox is disabled",
+			Err:     errors.New("outbox disabled"),
+		}
+	}
+
+	var ids []string
+	for i := range embeds {
+		id := uuid.New().String()
+		eErr := outbox.Enqueue(ctx, r.Cache, r.Config.CacheSettings.Outbox, outbox.Message{
+			ID:         id,
+			GuildID:    server.GuildID,
+			ChannelID:  channelID,
+			ServerID:   server.ID,
+			ServerName: server.Name,
+			Receipt:    receipt,
+			Embed:      embeds[i],
+		})
+		if eErr != nil {
+			r.logOutboxError(ctx, eErr)
+			return ids, &Error{
+				Message: eErr.Message,
+				Err:     eErr.Err,
+			}
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// QueueSupersedingEmbed adds an embed to a channel's outbox, replacing any queued embed with the same supersede key
+func (r *Runners) QueueSupersedingEmbed(ctx context.Context, guildID string, channelID string, editMessageID string, supersede string, embed discordgo.MessageEmbed) bool {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !r.Config.CacheSettings.Outbox.Enabled {
+		return false
+	}
+
+	eErr := outbox.Enqueue(ctx, r.Cache, r.Config.CacheSettings.Outbox, outbox.Message{
+		GuildID:       guildID,
+		ChannelID:     channelID,
+		EditMessageID: editMessageID,
+		Supersede:     supersede,
+		Embed:         embed,
+	})
+	if eErr != nil {
+		r.logOutboxError(ctx, eErr)
+		return false
+	}
+
+	return true
+}
+
+// DiscardSupersededEmbed drops a queued embed once a newer copy has been delivered directly
+func (r *Runners) DiscardSupersededEmbed(ctx context.Context, channelID string, supersede string) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if dErr := outbox.Discard(ctx, r.Cache, r.Config.CacheSettings.Outbox, channelID, supersede); dErr != nil {
+		r.logOutboxError(ctx, dErr)
+	}
+}
+
+// logOutboxError func
+func (r *Runners) logOutboxError(ctx context.Context, err *outbox.Error) {
+	ctx = logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
+	logger := logging.Logger(ctx)
+	logger.Error("runner_log")
+}
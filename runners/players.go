@@ -4,20 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/gammazero/workerpool"
 	"github.com/google/uuid"
 	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/guildconfigservice"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
 	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
 	"go.uber.org/zap"
 )
 
+// OnlinePlayersRepostDebounce const
+const OnlinePlayersRepostDebounce time.Duration = 10 * time.Second
+
 // OnlinePlayersSuccessOutput
 type OnlinePlayersSuccessOutput struct {
 	Data []PlayerData
@@ -40,15 +46,20 @@ func (r *Runners) OnlinePlayers(ctx context.Context, delay time.Duration) {
 		zap.String("runner", "players"),
 	)
 
-	if delay != 0 {
-		time.Sleep(time.Second * delay)
+	if delay != 0 && !r.sleep(time.Second*delay) {
+		return
 	}
 
 	ticker := time.NewTicker(r.Config.Runners.Players.Frequency * time.Second)
 
 	wp := workerpool.New(r.Config.Runners.Players.Workers)
+	defer wp.StopWait()
+
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			continue
+		}
 
-	for range ticker.C {
 		requestID := uuid.New()
 		gCtx := logging.AddValues(ctx, zap.String("request_id", requestID.String()))
 
@@ -60,6 +71,7 @@ func (r *Runners) OnlinePlayers(ctx context.Context, delay time.Duration) {
 			)
 			logger := logging.Logger(newCtx)
 			logger.Error("runner_log")
+			skipTick("players")
 			continue
 		} else {
 			newCtx := logging.AddValues(gCtx, zap.String("runner_message", "Started online players runner"))
@@ -67,127 +79,129 @@ func (r *Runners) OnlinePlayers(ctx context.Context, delay time.Duration) {
 			logger.Info("runner_log")
 		}
 
-		allGuilds, agErr := guildconfigservice.GetAllGuilds(gCtx, r.GuildConfigService)
-		if agErr != nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", agErr),
-				zap.String("error_message", agErr.Message),
-			)
-			logger := logging.Logger(newCtx)
-			logger.Error("runner_log")
+		r.onlinePlayersTick(gCtx, wp)
+	}
+}
+
+// onlinePlayersTick queues an online players request for every enabled server with a players output channel,
+// on the shared job queue when it is enabled and otherwise on this instance's workerpool
+func (r *Runners) onlinePlayersTick(ctx context.Context, wp *workerpool.WorkerPool) {
+	defer recovery.Recover(ctx, "runner:players", "")
+
+	tick := startTick(ctx, "players", wp)
+	defer tick.finish()
+
+	allGuilds, agErr := guildconfigservice.GetAllGuilds(ctx, r.GuildConfigService)
+	if agErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", agErr),
+			zap.String("error_message", agErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if allGuilds.Payload == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil payload")),
+			zap.String("error_message", "nil payload in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if allGuilds.Payload.Guilds == nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", errors.New("nil guilds")),
+			zap.String("error_message", "nil guilds in all guilds request"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	for _, aGuild := range allGuilds.Payload.Guilds {
+		agCtx := logging.AddValues(ctx, zap.String("guild_id", aGuild.ID))
+
+		if !aGuild.Enabled || !r.Shards.Owns(aGuild.ID) {
 			continue
 		}
 
-		if allGuilds.Payload == nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", errors.New("nil payload")),
-				zap.String("error_message", "nil payload in all guilds request"),
+		guildFeed, gfErr := guildconfigservice.GetGuildFeed(agCtx, r.GuildConfigService, aGuild.ID)
+		if gfErr != nil {
+			newCtx := logging.AddValues(agCtx,
+				zap.NamedError("error", gfErr),
+				zap.String("error_message", gfErr.Message),
 			)
 			logger := logging.Logger(newCtx)
 			logger.Error("runner_log")
 			continue
 		}
 
-		if allGuilds.Payload.Guilds == nil {
-			newCtx := logging.AddValues(gCtx,
-				zap.NamedError("error", errors.New("nil guilds")),
-				zap.String("error_message", "nil guilds in all guilds request"),
-			)
-			logger := logging.Logger(newCtx)
-			logger.Error("runner_log")
+		if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+			// newCtx := logging.AddValues(agCtx,
+			// 	zap.NamedError("error", vErr),
+			// 	zap.String("error_message", vErr.Message),
+			// )
+			// logger := logging.Logger(newCtx)
+			// logger.Info("runner_log")
 			continue
 		}
 
-		for _, aGuild := range allGuilds.Payload.Guilds {
-			agCtx := logging.AddValues(gCtx, zap.String("guild_id", aGuild.ID))
+		for _, server := range guildFeed.Payload.Guild.Servers {
+			serverCtx := logging.AddValues(agCtx,
+				zap.Uint64("server_id", server.ID),
+				zap.Int64("server_nitrado_id", server.NitradoID),
+			)
 
-			if !aGuild.Enabled {
+			if !server.Enabled {
 				continue
 			}
 
-			guildFeed, gfErr := guildconfigservice.GetGuildFeed(agCtx, r.GuildConfigService, aGuild.ID)
-			if gfErr != nil {
-				newCtx := logging.AddValues(agCtx,
-					zap.NamedError("error", gfErr),
-					zap.String("error_message", gfErr.Message),
-				)
-				logger := logging.Logger(newCtx)
-				logger.Error("runner_log")
+			if len(server.ServerOutputChannels) == 0 {
 				continue
 			}
 
-			if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
-				// newCtx := logging.AddValues(agCtx,
-				// 	zap.NamedError("error", vErr),
-				// 	zap.String("error_message", vErr.Message),
-				// )
-				// logger := logging.Logger(newCtx)
-				// logger.Info("runner_log")
+			if outputChannel(*server, "players") == nil {
 				continue
 			}
 
-			for _, server := range guildFeed.Payload.Guild.Servers {
-				serverCtx := logging.AddValues(agCtx,
-					zap.Uint64("server_id", server.ID),
-					zap.Int64("server_nitrado_id", server.NitradoID),
-				)
-
-				if !server.Enabled {
-					continue
-				}
-
-				if len(server.ServerOutputChannels) == 0 {
-					continue
-				}
-
-				var onlinePlayersOutputChannel *gcscmodels.ServerOutputChannel
-				for _, oc := range server.ServerOutputChannels {
-					if !oc.Enabled {
-						continue
-					}
-
-					if oc.OutputChannelTypeID == "players" {
-						var tempAdminLogOutputChannel gcscmodels.ServerOutputChannel = *oc
-						onlinePlayersOutputChannel = &tempAdminLogOutputChannel
-					}
-
-					if onlinePlayersOutputChannel != nil {
-						break
-					}
-				}
-
-				var aServer gcscmodels.Server = *server
-
-				wp.Submit(func() {
-					r.GetOnlinePlayersRequest(serverCtx, aServer, onlinePlayersOutputChannel)
-				})
+			var aServer gcscmodels.Server = *server
+
+			if r.PlayersJobs != nil {
+				r.enqueueJob(serverCtx, r.PlayersJobs, aServer, playersJob{
+					Server: aServer,
+				}, r.Config.Runners.Players.Frequency*time.Second)
+				continue
 			}
+
+			tick.submit(func() {
+				r.GetOnlinePlayersRequest(serverCtx, aServer)
+			})
 		}
 	}
 }
 
-// GetOnlinePlayersRequest func
-func (r *Runners) GetOnlinePlayersRequest(ctx context.Context, server gcscmodels.Server, onlinePlayersOutput *gcscmodels.ServerOutputChannel) {
+// GetOnlinePlayersRequest polls a server's online players and publishes the result on the event bus
+func (r *Runners) GetOnlinePlayersRequest(ctx context.Context, server gcscmodels.Server) {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
-	if onlinePlayersOutput == nil {
-		return
-	}
-
-	logs, err := r.NitradoService.Client.GetPlayers(server.NitradoToken.Token, fmt.Sprint(server.NitradoID), true, false)
+	logs, err := r.NitradoService.GetPlayers(ctx, server.NitradoToken.Token, fmt.Sprint(server.NitradoID), true, false)
 	if err != nil {
 		ctx = logging.AddValues(ctx,
 			zap.NamedError("error", err),
-			zap.String("error_message", err.Message()),
+			zap.String("error_message", err.Message),
 		)
 		logger := logging.Logger(ctx)
 		logger.Error("runner_log")
 
-		go r.WriteOnlinePlayers(ctx, server, onlinePlayersOutput, logs.Players, &OnlinePlayersErrorOutput{
-			Message: err.Message(),
-			Err: Error{
-				Err: err,
-			},
+		r.EventBus.Publish(ctx, server, eventbus.PlayersSnapshot{
+			Online:       false,
+			Players:      logs.Players,
+			Error:        err.Error(),
+			ErrorMessage: err.Message,
 		})
 		return
 	}
@@ -199,7 +213,139 @@ func (r *Runners) GetOnlinePlayersRequest(ctx context.Context, server gcscmodels
 	// 	})
 	// }
 
-	go r.WriteOnlinePlayers(ctx, server, onlinePlayersOutput, logs.Players, nil)
+	r.EventBus.Publish(ctx, server, eventbus.PlayersSnapshot{
+		Online:  true,
+		Players: logs.Players,
+	})
+}
+
+// OnlinePlayersMessageDelete re-posts a server's online players as soon as one of its tracked messages is deleted,
+// instead of leaving the channel empty until the next tick. Only the leader re-posts, like it runs the ticks.
+// The runner's own clean up never matches because the tracked messages are replaced before the old ones are deleted.
+// Each server's players are re-posted in a channel at most once per debounce window, so deleting several tracked messages at once posts them again only once.
+func (r *Runners) OnlinePlayersMessageDelete(s *discordgo.Session, md *discordgo.MessageDelete) {
+	if md.GuildID == "" || !r.Leader.IsLeader() {
+		return
+	}
+
+	if !r.begin() {
+		return
+	}
+	defer r.running.Done()
+
+	ctx := context.Background()
+	ctx = logging.AddValues(ctx, zap.String("request_id", uuid.New().String()))
+	defer recovery.Recover(ctx, "message_delete", "")
+
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("guild_id", md.GuildID),
+		zap.String("channel_id", md.ChannelID),
+		zap.String("message_id", md.ID),
+	)
+
+	guildFeed, gfErr := guildconfigservice.GetGuildFeed(ctx, r.GuildConfigService, md.GuildID)
+	if gfErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", gfErr),
+			zap.String("error_message", gfErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	if vErr := guildconfigservice.ValidateGuildFeed(guildFeed, r.Config.Bot.GuildService, "Servers"); vErr != nil {
+		return
+	}
+
+	for _, server := range guildFeed.Payload.Guild.Servers {
+		if !server.Enabled {
+			continue
+		}
+
+		playersOutput := outputChannel(*server, "players")
+		if playersOutput == nil || playersOutput.ChannelID != md.ChannelID {
+			continue
+		}
+
+		var tracked *models.OnlinePlayersOutputChannelMessages
+		cacheKey := tracked.CacheKey(r.Config.CacheSettings.OnlinePlayersOutputChannelMessages.Base, md.ChannelID, int64(server.ID))
+		if gsErr := r.Cache.GetStruct(ctx, cacheKey, &tracked); gsErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", gsErr.Err), zap.String("error_message", gsErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		if tracked == nil || !trackedMessage(tracked.Messages, md.ID) {
+			continue
+		}
+
+		if !r.claimRepost(md.ChannelID, int64(server.ID)) {
+			continue
+		}
+
+		serverCtx := logging.AddValues(ctx,
+			zap.Uint64("server_id", server.ID),
+			zap.Int64("server_nitrado_id", server.NitradoID),
+		)
+		r.GetOnlinePlayersRequest(serverCtx, *server)
+	}
+}
+
+// claimRepost reports whether a server's online players may be re-posted in a channel, starting a new debounce window if so.
+// Windows that have passed are pruned on the way.
+func (r *Runners) claimRepost(channelID string, serverID int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, until := range r.reposts {
+		if now.After(until) {
+			delete(r.reposts, key)
+		}
+	}
+
+	key := fmt.Sprintf("%s:%d", channelID, serverID)
+	if _, ok := r.reposts[key]; ok {
+		return false
+	}
+
+	r.reposts[key] = now.Add(OnlinePlayersRepostDebounce)
+	return true
+}
+
+// trackedMessage reports whether a message ID is among the tracked messages
+func trackedMessage(messages []models.Message, id string) bool {
+	for _, message := range messages {
+		if message.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RecordPlayerStats func
+func (r *Runners) RecordPlayerStats(ctx context.Context, server gcscmodels.Server, online bool, players []nsv2.Player) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var names []string
+	for _, player := range players {
+		if player.Name == "" {
+			continue
+		}
+
+		names = append(names, player.Name)
+	}
+
+	rpErr := serverstats.RecordPlayers(ctx, r.Cache, r.Config.CacheSettings.ServerStats, server.GuildID, server.NitradoID, online, names)
+	if rpErr != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", rpErr.Err), zap.String("error_message", rpErr.Message))
+		logger := logging.Logger(ctx)
+		logger.Error("runner_log")
+	}
 }
 
 // WriteOnlinePlayers func
@@ -209,17 +355,16 @@ func (r *Runners) WriteOnlinePlayers(ctx context.Context, server gcscmodels.Serv
 
 	var embedFieldCharacterCount int = 25 // Set to 50 to account for embed field titles
 	for _, entry := range onlinePlayers {
-		name := strings.Replace(entry.Name, "_", "\\_", -1)
-		name = strings.Replace(name, "*", "\\*", -1)
+		name := discordapi.EscapeText(entry.Name)
 		name = "🟢 " + name
 
 		data := PlayerData{
 			Gamertag: name,
 		}
 
-		if embedFieldCharacterCount+len(name)+5 < MaxEmbedFieldSize {
+		if embedFieldCharacterCount+discordapi.TextLength(name)+5 < MaxEmbedFieldSize {
 			output.Data = append(output.Data, data)
-			embedFieldCharacterCount += len(name) + 5
+			embedFieldCharacterCount += discordapi.TextLength(name) + 5
 		} else {
 			outputs = append(outputs, output)
 			output = OnlinePlayersSuccessOutput{
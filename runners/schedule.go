@@ -0,0 +1,174 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/pollschedule"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// scheduledServer is a server a runner's schedule polls, with the job that polls it
+type scheduledServer struct {
+	ctx    context.Context
+	server gcscmodels.Server
+	job    interface{}
+}
+
+// serverLoader lists the servers a runner polls, keyed by server ID.
+// It returns false if some guilds could not be read, so servers missing from the list may still exist.
+type serverLoader func(ctx context.Context) (map[string]scheduledServer, bool)
+
+// runSchedule starts each server's poll when the schedule says it is due instead of polling every server at once.
+// The server list is reloaded every refresh interval and whenever this replica becomes the leader.
+func (r *Runners) runSchedule(ctx context.Context, schedule *pollschedule.Schedule, load serverLoader, dispatch func(ctx context.Context, s scheduledServer)) {
+	ticker := time.NewTicker(r.Config.PollSchedule.Tick * time.Second)
+
+	var servers map[string]scheduledServer
+	var loaded time.Time
+
+	for r.nextTick(ticker) {
+		if !r.Leader.IsLeader() {
+			servers = nil
+			continue
+		}
+
+		if servers == nil || time.Since(loaded) >= r.Config.PollSchedule.Refresh*time.Second {
+			servers = r.loadSchedule(ctx, schedule, load, servers)
+			loaded = time.Now()
+		}
+
+		r.scheduleTick(ctx, schedule, servers, dispatch)
+	}
+}
+
+// loadSchedule reloads the server list and syncs the schedule with it.
+// Servers are only removed from the schedule when every guild was read, otherwise the previous entries are kept.
+func (r *Runners) loadSchedule(ctx context.Context, schedule *pollschedule.Schedule, load serverLoader, previous map[string]scheduledServer) map[string]scheduledServer {
+	defer recovery.Recover(ctx, "runner:"+schedule.Name, "")
+
+	ctx = logging.AddValues(ctx, zap.String("request_id", uuid.New().String()))
+
+	servers, complete := load(ctx)
+	if !complete {
+		for id, s := range previous {
+			if _, ok := servers[id]; !ok {
+				servers[id] = s
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(servers))
+	for id := range servers {
+		ids = append(ids, id)
+	}
+
+	if sErr := schedule.Sync(ctx, ids, complete); sErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", sErr.Err),
+			zap.String("error_message", sErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+
+	newCtx := logging.AddValues(ctx,
+		zap.String("runner_message", "Loaded runner schedule"),
+		zap.Int("servers", len(servers)),
+	)
+	logger := logging.Logger(newCtx)
+	logger.Info("runner_log")
+
+	return servers
+}
+
+// scheduleTick starts the polls of the servers that are due
+func (r *Runners) scheduleTick(ctx context.Context, schedule *pollschedule.Schedule, servers map[string]scheduledServer, dispatch func(ctx context.Context, s scheduledServer)) {
+	defer recovery.Recover(ctx, "runner:"+schedule.Name, "")
+
+	due, dErr := schedule.Due(ctx, r.Config.PollSchedule.BatchSize)
+	if dErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", dErr.Err),
+			zap.String("error_message", dErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return
+	}
+
+	for _, id := range due {
+		s, ok := servers[id]
+		if !ok {
+			// Added by another leader since this one loaded the server list, or removed on the next sync
+			continue
+		}
+
+		serverCtx := logging.AddValues(s.ctx, zap.String("request_id", uuid.New().String()))
+
+		result, lag, sErr := schedule.Start(serverCtx, id, s.server.NitradoToken.Token)
+		if sErr != nil {
+			newCtx := logging.AddValues(serverCtx,
+				zap.NamedError("error", sErr.Err),
+				zap.String("error_message", sErr.Message),
+			)
+			logger := logging.Logger(newCtx)
+			logger.Error("runner_log")
+			continue
+		}
+
+		switch result {
+		case pollschedule.StillRunning:
+			metrics.PollsDeferred.WithLabelValues(schedule.Name, "still_running").Inc()
+		case pollschedule.TokenBusy:
+			metrics.PollsDeferred.WithLabelValues(schedule.Name, "token_busy").Inc()
+		case pollschedule.Started:
+			metrics.PollLag.WithLabelValues(schedule.Name).Observe(lag.Seconds())
+			dispatch(serverCtx, s)
+		}
+	}
+}
+
+// finishPoll records a finished poll on its runner's schedule, if the runner has one
+func (r *Runners) finishPoll(ctx context.Context, schedule *pollschedule.Schedule, server gcscmodels.Server, duration time.Duration, failed bool) {
+	if schedule == nil {
+		return
+	}
+
+	result := "ok"
+	if failed {
+		result = "failed"
+	}
+	metrics.PollDuration.WithLabelValues(schedule.Name, result).Observe(duration.Seconds())
+
+	if fErr := schedule.Finish(ctx, fmt.Sprint(server.ID), server.NitradoToken.Token, duration, failed); fErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", fErr.Err),
+			zap.String("error_message", fErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+}
+
+// releasePoll frees a started poll that was never run, if the runner has a schedule
+func (r *Runners) releasePoll(ctx context.Context, schedule *pollschedule.Schedule, server gcscmodels.Server) {
+	if schedule == nil {
+		return
+	}
+
+	if rErr := schedule.Release(ctx, fmt.Sprint(server.ID), server.NitradoToken.Token); rErr != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", rErr.Err),
+			zap.String("error_message", rErr.Message),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+}
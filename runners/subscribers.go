@@ -0,0 +1,240 @@
+package runners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/eventbus"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/serverstats"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// Subscribe registers every feature that consumes runner and reaction events on the event bus.
+// Everything but the online players message subscribes reliably, since a dropped batch would lose its log entries
+// and events for good; the online players message is replaced by the next snapshot anyway.
+// Overlapping fetches return entries again, so each subscriber skips the ones it has already handled.
+func (r *Runners) Subscribe() {
+	r.EventBus.SubscribeReliable("discord_admin_logs", r.OutputAdminLogs, eventbus.TypeLogsFetched, eventbus.TypeAdminCommand)
+	r.EventBus.SubscribeReliable("discord_chat_logs", r.OutputChatLogs, eventbus.TypeLogsFetched, eventbus.TypeChatMessage)
+	r.EventBus.SubscribeReliable("discord_kill_logs", r.OutputKillLogs, eventbus.TypeLogsFetched, eventbus.TypeKill)
+	r.EventBus.Subscribe("discord_online_players", r.OutputOnlinePlayers, eventbus.TypePlayersSnapshot)
+	r.EventBus.SubscribeReliable("server_stats", r.RecordStats, eventbus.TypeChatMessage, eventbus.TypeAdminCommand, eventbus.TypeKill, eventbus.TypePlayersSnapshot, eventbus.TypeModerationAction)
+	r.EventBus.SubscribeReliable("pvp_stats", r.RecordPvPStats, eventbus.TypeKill)
+
+	if r.LogArchive != nil {
+		r.EventBus.SubscribeReliable("log_archive", r.ArchiveBatch, eventbus.TypeChatMessage, eventbus.TypeAdminCommand, eventbus.TypeKill)
+	}
+
+	if r.publishesEvents() {
+		r.EventBus.SubscribeReliable("game_events", r.PublishGameEvents, eventbus.TypeChatMessage, eventbus.TypeAdminCommand, eventbus.TypeKill, eventbus.TypePlayersSnapshot, eventbus.TypeModerationAction, eventbus.TypeServerControl)
+	}
+}
+
+// OutputAdminLogs posts fetched admin logs to the server's admin log channel
+func (r *Runners) OutputAdminLogs(ctx context.Context, batch eventbus.Batch) {
+	fetched, logs := batchLogs(batch)
+	if !fetched.Admin {
+		return
+	}
+
+	if channel := outputChannel(batch.Server, "admin"); channel != nil {
+		r.DeliverAdminLogs(ctx, batch.Server, channel, logs.AdminLogs)
+	}
+}
+
+// OutputChatLogs posts fetched chat logs to the server's chat log channel
+func (r *Runners) OutputChatLogs(ctx context.Context, batch eventbus.Batch) {
+	fetched, logs := batchLogs(batch)
+	if !fetched.Chat {
+		return
+	}
+
+	if channel := outputChannel(batch.Server, "chat"); channel != nil {
+		r.DeliverChatLogs(ctx, batch.Server, channel, logs.PlayerLogs)
+	}
+}
+
+// OutputKillLogs posts fetched kill logs to the server's kill log channel
+func (r *Runners) OutputKillLogs(ctx context.Context, batch eventbus.Batch) {
+	fetched, logs := batchLogs(batch)
+	if !fetched.Kills {
+		return
+	}
+
+	if channel := outputChannel(batch.Server, "kills"); channel != nil {
+		r.DeliverKillLogs(ctx, batch.Server, channel, logs.KillLogs)
+	}
+}
+
+// OutputOnlinePlayers updates the server's online players channel
+func (r *Runners) OutputOnlinePlayers(ctx context.Context, batch eventbus.Batch) {
+	channel := outputChannel(batch.Server, "players")
+	if channel == nil {
+		return
+	}
+
+	for _, event := range batch.Events {
+		snapshot, ok := event.(eventbus.PlayersSnapshot)
+		if !ok {
+			continue
+		}
+
+		if snapshot.Online {
+			r.WriteOnlinePlayers(ctx, batch.Server, channel, snapshot.Players, nil)
+			continue
+		}
+
+		r.WriteOnlinePlayers(ctx, batch.Server, channel, snapshot.Players, &OnlinePlayersErrorOutput{
+			Message: snapshot.ErrorMessage,
+			Err: Error{
+				Err: errors.New(snapshot.Error),
+			},
+		})
+	}
+}
+
+// RecordStats adds log volume, player polls and moderation actions to the server's hourly stats
+func (r *Runners) RecordStats(ctx context.Context, batch eventbus.Batch) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	_, logs := batchLogs(batch)
+	logs, marked := r.freshLogs(ctx, "server_stats", batch.Server, logs)
+	if len(logs.PlayerLogs) > 0 || len(logs.AdminLogs) > 0 || len(logs.KillLogs) > 0 {
+		if rlErr := r.RecordLogStats(ctx, batch.Server, logs); rlErr != nil {
+			r.forgetLogs(ctx, marked)
+		}
+	}
+
+	for _, event := range batch.Events {
+		switch e := event.(type) {
+		case eventbus.PlayersSnapshot:
+			r.RecordPlayerStats(ctx, batch.Server, e.Online, e.Players)
+		case eventbus.ModerationAction:
+			rmErr := serverstats.RecordModeration(ctx, r.Cache, r.Config.CacheSettings.ServerStats, batch.Server.GuildID, batch.Server.NitradoID, e.Action)
+			if rmErr != nil {
+				newCtx := logging.AddValues(ctx, zap.NamedError("error", rmErr.Err), zap.String("error_message", rmErr.Message))
+				logger := logging.Logger(newCtx)
+				logger.Error("runner_log")
+			}
+		}
+	}
+}
+
+// RecordPvPStats adds fetched kills to the leaderboard stats
+func (r *Runners) RecordPvPStats(ctx context.Context, batch eventbus.Batch) {
+	_, logs := batchLogs(batch)
+	logs, marked := r.freshLogs(ctx, "pvp_stats", batch.Server, logs)
+	if len(logs.KillLogs) > 0 {
+		if rkErr := r.RecordKillStats(ctx, batch.Server, logs.KillLogs); rkErr != nil {
+			r.forgetLogs(ctx, marked)
+		}
+	}
+}
+
+// ArchiveBatch stores fetched logs in the log archive
+func (r *Runners) ArchiveBatch(ctx context.Context, batch eventbus.Batch) {
+	_, logs := batchLogs(batch)
+	logs, marked := r.freshLogs(ctx, "log_archive", batch.Server, logs)
+	if len(logs.PlayerLogs) > 0 || len(logs.AdminLogs) > 0 || len(logs.KillLogs) > 0 {
+		if alErr := r.ArchiveLogs(ctx, batch.Server, logs); alErr != nil {
+			r.forgetLogs(ctx, marked)
+		}
+	}
+}
+
+// freshLogs drops the entries a subscriber has already handled and marks the rest as handled.
+// It returns the keys marking them, so they can be forgotten if handling them fails and handled on a later fetch.
+// If Redis can't be reached every entry is handled, since counting one twice is better than losing it.
+func (r *Runners) freshLogs(ctx context.Context, subscriber string, server gcscmodels.Server, logs nsv2.GetLogsResponse) (nsv2.GetLogsResponse, []string) {
+	settings := r.Config.CacheSettings.SeenLogs
+	if !settings.Enabled {
+		return logs, nil
+	}
+
+	entries := append(append(adminLogEntries(logs.AdminLogs), chatLogEntries(logs.PlayerLogs)...), killLogEntries(logs.KillLogs)...)
+	if len(entries) == 0 {
+		return logs, nil
+	}
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = cache.GenerateKey(settings.Base, fmt.Sprintf("%s:%d:%s", subscriber, server.NitradoID, entry.Hash))
+	}
+
+	set, sErr := r.Cache.SetNXAll(ctx, keys, "1", settings.TTL)
+	if sErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", sErr.Err), zap.String("error_message", sErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+		return logs, nil
+	}
+
+	var fresh nsv2.GetLogsResponse
+	var marked []string
+	for i, entry := range entries {
+		if !set[i] {
+			continue
+		}
+
+		marked = append(marked, keys[i])
+		switch log := entry.Log.(type) {
+		case nsv2.AdminLog:
+			fresh.AdminLogs = append(fresh.AdminLogs, log)
+		case nsv2.PlayerLog:
+			fresh.PlayerLogs = append(fresh.PlayerLogs, log)
+		case nsv2.KillLog:
+			fresh.KillLogs = append(fresh.KillLogs, log)
+		}
+	}
+
+	return fresh, marked
+}
+
+// forgetLogs un-marks entries freshLogs marked as handled
+func (r *Runners) forgetLogs(ctx context.Context, keys []string) {
+	if dErr := r.Cache.Delete(ctx, keys...); dErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", dErr.Err), zap.String("error_message", dErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("runner_log")
+	}
+}
+
+// batchLogs collects the log entries in a batch back into a logs response
+func batchLogs(batch eventbus.Batch) (eventbus.LogsFetched, nsv2.GetLogsResponse) {
+	var fetched eventbus.LogsFetched
+	var logs nsv2.GetLogsResponse
+
+	for _, event := range batch.Events {
+		switch e := event.(type) {
+		case eventbus.LogsFetched:
+			fetched = e
+		case eventbus.ChatMessage:
+			logs.PlayerLogs = append(logs.PlayerLogs, e.Log)
+		case eventbus.AdminCommand:
+			logs.AdminLogs = append(logs.AdminLogs, e.Log)
+		case eventbus.Kill:
+			logs.KillLogs = append(logs.KillLogs, e.Log)
+		}
+	}
+
+	return fetched, logs
+}
+
+// outputChannel returns a server's enabled output channel of a type, or nil if it has none
+func outputChannel(server gcscmodels.Server, outputType string) *gcscmodels.ServerOutputChannel {
+	for _, oc := range server.ServerOutputChannels {
+		if !oc.Enabled || oc.OutputChannelTypeID != outputType {
+			continue
+		}
+
+		var channel gcscmodels.ServerOutputChannel = *oc
+		return &channel
+	}
+
+	return nil
+}
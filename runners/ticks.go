@@ -0,0 +1,78 @@
+package runners
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gammazero/workerpool"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+)
+
+// runnerTick times one runner tick until every job it queued has finished
+type runnerTick struct {
+	ctx    context.Context
+	runner string
+	wp     *workerpool.WorkerPool
+	start  time.Time
+	wg     sync.WaitGroup
+}
+
+// startTick func
+func startTick(ctx context.Context, runner string, wp *workerpool.WorkerPool) *runnerTick {
+	return &runnerTick{
+		ctx:    ctx,
+		runner: runner,
+		wp:     wp,
+		start:  time.Now(),
+	}
+}
+
+// submit queues a job on the runner's workerpool as part of the tick, recovering its panics
+func (t *runnerTick) submit(job func()) {
+	t.wg.Add(1)
+	t.wp.Submit(func() {
+		defer t.wg.Done()
+		recovery.Wrap(t.ctx, "runner:"+t.runner, job)()
+	})
+}
+
+// finish records the queue depth now that the tick has queued its jobs, and the tick duration once they are done
+func (t *runnerTick) finish() {
+	metrics.WorkerpoolQueueDepth.WithLabelValues(t.runner).Set(float64(t.wp.WaitingQueueSize()))
+
+	go func() {
+		t.wg.Wait()
+		metrics.RunnerTickDuration.WithLabelValues(t.runner).Observe(time.Since(t.start).Seconds())
+	}()
+}
+
+// nextTick waits for the runner's next tick, returning false once the runners are stopping
+func (r *Runners) nextTick(ticker *time.Ticker) bool {
+	select {
+	case <-r.stop:
+		ticker.Stop()
+		return false
+	case <-ticker.C:
+		return true
+	}
+}
+
+// sleep waits out a runner's start delay, returning false if the runners stop first
+func (r *Runners) sleep(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-r.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// skipTick records a tick skipped because the previous tick's queue was not empty
+func skipTick(runner string) {
+	metrics.RunnerSkippedTicks.WithLabelValues(runner).Inc()
+}
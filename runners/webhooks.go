@@ -0,0 +1,95 @@
+package runners
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/outputwebhook"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// SendOutputEmbeds sends embeds to an output channel through its webhook when one is enabled, otherwise as the bot user.
+// A webhook that was deleted from Discord is re-created and the unsent embeds go through the new one.
+// A channel with no stored webhook record has it rebuilt from the webhooks in the channel.
+func (r *Runners) SendOutputEmbeds(ctx context.Context, server gcscmodels.Server, channel gcscmodels.ServerOutputChannel, embeds []discordgo.MessageEmbed) ([]*discordgo.Message, *discordapi.Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	settings := r.Config.CacheSettings.OutputWebhooks
+	if !settings.Enabled || channel.OutputChannelTypeID == "players" {
+		return r.Scheduler.SendEmbeds(server.GuildID, channel.ChannelID, embeds)
+	}
+
+	webhook, gErr := outputwebhook.Get(ctx, r.Cache, settings, channel.ChannelID)
+	if gErr != nil {
+		r.logOutputWebhookError(ctx, gErr)
+		return r.Scheduler.SendEmbeds(server.GuildID, channel.ChannelID, embeds)
+	}
+
+	if webhook == nil {
+		recovered, rErr := outputwebhook.Recover(ctx, r.Session, r.Cache, settings, server.GuildID, channel.ChannelID)
+		if rErr != nil {
+			r.logOutputWebhookError(ctx, rErr)
+			return r.Scheduler.SendEmbeds(server.GuildID, channel.ChannelID, embeds)
+		}
+		webhook = recovered
+	}
+
+	if !webhook.Enabled {
+		return r.Scheduler.SendEmbeds(server.GuildID, channel.ChannelID, embeds)
+	}
+
+	target := r.webhookTarget(ctx, server)
+	target.ID, target.Token = webhook.WebhookID, webhook.Token
+
+	messages, err := r.Scheduler.SendWebhookEmbeds(server.GuildID, channel.ChannelID, target, embeds)
+	if !outputwebhook.IsUnknownWebhook(err) {
+		return messages, err
+	}
+
+	remaining := embeds[len(messages):]
+
+	recreated, cErr := outputwebhook.Create(ctx, r.Session, r.Cache, settings, server.GuildID, channel.ChannelID, r.Config.OutputWebhooks.Name)
+	if cErr != nil {
+		r.logOutputWebhookError(ctx, cErr)
+
+		sent, sErr := r.Scheduler.SendEmbeds(server.GuildID, channel.ChannelID, remaining)
+		return append(messages, sent...), sErr
+	}
+
+	target.ID, target.Token = recreated.WebhookID, recreated.Token
+	sent, sErr := r.Scheduler.SendWebhookEmbeds(server.GuildID, channel.ChannelID, target, remaining)
+	return append(messages, sent...), sErr
+}
+
+// webhookTarget posts as the server's name and avatar so each server's output is easy to tell apart,
+// falling back to the bot's configured name and avatar
+func (r *Runners) webhookTarget(ctx context.Context, server gcscmodels.Server) discordapi.WebhookTarget {
+	username := server.Name
+	if username == "" {
+		username = r.Config.OutputWebhooks.Name
+	}
+
+	avatarURL, gaErr := outputwebhook.GetAvatar(ctx, r.Cache, r.Config.CacheSettings.OutputWebhooks, server.ID)
+	if gaErr != nil {
+		r.logOutputWebhookError(ctx, gaErr)
+	}
+
+	if avatarURL == "" {
+		avatarURL = r.Config.OutputWebhooks.AvatarURL
+	}
+
+	return discordapi.WebhookTarget{
+		Username:  username,
+		AvatarURL: avatarURL,
+	}
+}
+
+// logOutputWebhookError func
+func (r *Runners) logOutputWebhookError(ctx context.Context, err *outputwebhook.Error) {
+	ctx = logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
+	logger := logging.Logger(ctx)
+	logger.Error("runner_log")
+}
@@ -5,4 +5,4 @@ export NITRADO_SERVICE_TOKEN=$(echo ${ENV_VARS} | jq -r '.NITRADO_SERVICE_TOKEN'
 export GUILD_CONFIG_SERVICE_TOKEN=$(echo ${ENV_VARS} | jq -r '.GUILD_CONFIG_SERVICE_TOKEN')
 export BASE_PATH=$(echo ${ENV_VARS} | jq -r '.BASE_PATH')
 
-/main
\ No newline at end of file
+exec /main
\ No newline at end of file
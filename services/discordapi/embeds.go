@@ -1,6 +1,7 @@
 package discordapi
 
 import (
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -21,56 +22,99 @@ type EmbeddableField interface {
 	ConvertToEmbedField() (*discordgo.MessageEmbedField, *Error)
 }
 
-// MaxEmbedFields const
-const MaxEmbedFields = 23 // actually 25
-
-// MaxEmbedCharCount const
-const MaxEmbedCharCount = 5600 // actually 6000
+// Discord embed limits, counted in UTF-16 code units
+const (
+	MaxEmbedTitleCharCount       = 256
+	MaxEmbedDescriptionCharCount = 4096
+	MaxEmbedFieldNameCharCount   = 256
+	MaxEmbedFieldValueCharCount  = 1024
+	MaxEmbedFooterCharCount      = 2048
+	MaxEmbedFields               = 25
+	MaxEmbedCharCount            = 6000
+)
 
-// MaxEmbedFieldCharCount const
-const MaxEmbedFieldCharCount = 950 // actually 1000
+// continuedFieldSuffix is added to the name of fields that were split across several values
+const continuedFieldSuffix = " (cont.)"
 
-// CreateEmbeds func
+// CreateEmbeds converts fields into as many embeds as needed to stay within Discord's limits.
+// Every embed repeats the title, description and footer. Field values that are too long are
+// split into several fields at line or word boundaries.
 func CreateEmbeds(embedParams EmbeddableParams, embedableFields []EmbeddableField) []discordgo.MessageEmbed {
-	var embeds []discordgo.MessageEmbed
+	embeds, _ := CreateEmbedsWithSources(embedParams, embedableFields)
+
+	return embeds
+}
 
-	embed := &discordgo.MessageEmbed{
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Executed",
-		},
-		Color:       embedParams.Color,
-		Description: embedParams.Description,
-		Fields:      []*discordgo.MessageEmbedField{},
-		Timestamp:   time.Now().Format(time.RFC3339), // Discord wants ISO8601; RFC3339 is an extension of ISO8601 and should be completely compatible.
-		Title:       embedParams.Title,
-		URL:         embedParams.TitleURL,
+// CreateEmbedsWithSources works like CreateEmbeds and also returns, for each field of the embeds in order,
+// the index of the embeddable field it came from. A split field has several entries with the same index,
+// and a field that failed to convert has none.
+func CreateEmbedsWithSources(embedParams EmbeddableParams, embedableFields []EmbeddableField) ([]discordgo.MessageEmbed, []int) {
+	var fields []*discordgo.MessageEmbedField
+	var fieldSources []int
+	for i := 0; i < len(embedableFields); i++ {
+		field, err := embedableFields[i].ConvertToEmbedField()
+
+		if err != nil {
+			continue
+		}
+
+		fields = append(fields, field)
+		fieldSources = append(fieldSources, i)
 	}
 
+	title := TruncateText(embedParams.Title, MaxEmbedTitleCharCount)
+
+	footer := "Executed"
 	if embedParams.Footer != "" {
-		embed.Footer.Text = embedParams.Footer
+		footer = TruncateText(embedParams.Footer, MaxEmbedFooterCharCount)
 	}
 
-	if embedParams.ThumbnailURL != "" {
-		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
-			URL: embedParams.ThumbnailURL,
-		}
+	// Make sure a full size field always fits next to the text repeated on every embed
+	description := TruncateText(embedParams.Description, MaxEmbedDescriptionCharCount)
+	if len(fields) > 0 {
+		descriptionLimit := MaxEmbedCharCount - TextLength(title) - TextLength(footer) - MaxEmbedFieldNameCharCount - MaxEmbedFieldValueCharCount
+		description = TruncateText(description, descriptionLimit)
 	}
 
-	embedCharCount := 0
-	for i := 0; i < len(embedableFields); i++ {
-		field, err := embedableFields[i].ConvertToEmbedField()
+	timestamp := time.Now().Format(time.RFC3339) // Discord wants ISO8601; RFC3339 is an extension of ISO8601 and should be completely compatible.
+	newEmbed := func() *discordgo.MessageEmbed {
+		embed := &discordgo.MessageEmbed{
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: footer,
+			},
+			Color:       embedParams.Color,
+			Description: description,
+			Fields:      []*discordgo.MessageEmbedField{},
+			Timestamp:   timestamp,
+			Title:       title,
+			URL:         embedParams.TitleURL,
+		}
 
-		if err != nil {
-			continue
+		if embedParams.ThumbnailURL != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+				URL: embedParams.ThumbnailURL,
+			}
 		}
 
-		if len(field.Name)+len(field.Value)+embedCharCount >= MaxEmbedCharCount || len(embed.Fields) >= MaxEmbedFields {
+		return embed
+	}
+
+	var embeds []discordgo.MessageEmbed
+
+	split, sources := splitFields(fields, fieldSources)
+
+	embed := newEmbed()
+	embedCharCount := EmbedLength(embed)
+	for _, field := range split {
+		fieldCharCount := TextLength(field.Name) + TextLength(field.Value)
+
+		if embedCharCount+fieldCharCount > MaxEmbedCharCount || len(embed.Fields) >= MaxEmbedFields {
 			embeds = append(embeds, *embed)
-			embed.Fields = []*discordgo.MessageEmbedField{}
-			embedCharCount = 0
+			embed = newEmbed()
+			embedCharCount = EmbedLength(embed)
 		}
 
-		embedCharCount += len(field.Name) + len(field.Value)
+		embedCharCount += fieldCharCount
 		embed.Fields = append(embed.Fields, field)
 	}
 
@@ -78,5 +122,129 @@ func CreateEmbeds(embedParams EmbeddableParams, embedableFields []EmbeddableFiel
 		embeds = append(embeds, *embed)
 	}
 
-	return embeds
+	return embeds, sources
+}
+
+// splitFields truncates field names and splits field values that are over Discord's limits.
+// Each resulting field keeps the source of the field it was split from.
+func splitFields(fields []*discordgo.MessageEmbedField, fieldSources []int) ([]*discordgo.MessageEmbedField, []int) {
+	var split []*discordgo.MessageEmbedField
+	var sources []int
+	for i, field := range fields {
+		if TextLength(field.Name) <= MaxEmbedFieldNameCharCount && TextLength(field.Value) <= MaxEmbedFieldValueCharCount {
+			split = append(split, field)
+			sources = append(sources, fieldSources[i])
+			continue
+		}
+
+		name := TruncateText(field.Name, MaxEmbedFieldNameCharCount)
+		for key, value := range SplitText(field.Value, MaxEmbedFieldValueCharCount) {
+			if key > 0 {
+				name = TruncateText(field.Name, MaxEmbedFieldNameCharCount-TextLength(continuedFieldSuffix)) + continuedFieldSuffix
+			}
+
+			split = append(split, &discordgo.MessageEmbedField{
+				Name:   name,
+				Value:  value,
+				Inline: field.Inline,
+			})
+			sources = append(sources, fieldSources[i])
+		}
+	}
+
+	return split, sources
+}
+
+// EmbedLength returns the characters of an embed that count towards Discord's per-message limit
+func EmbedLength(embed *discordgo.MessageEmbed) int {
+	length := TextLength(embed.Title) + TextLength(embed.Description)
+
+	if embed.Footer != nil {
+		length += TextLength(embed.Footer.Text)
+	}
+
+	if embed.Author != nil {
+		length += TextLength(embed.Author.Name)
+	}
+
+	for _, field := range embed.Fields {
+		length += TextLength(field.Name) + TextLength(field.Value)
+	}
+
+	return length
+}
+
+// TextLength returns the length of text the way Discord counts it, in UTF-16 code units
+func TextLength(text string) int {
+	length := 0
+	for _, r := range text {
+		length += runeLength(r)
+	}
+
+	return length
+}
+
+// runeLength returns how many UTF-16 code units a character takes; characters outside the BMP need a surrogate pair
+func runeLength(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+
+	return 1
+}
+
+// TruncateText shortens text to at most limit UTF-16 code units, ending it with an ellipsis if it was cut
+func TruncateText(text string, limit int) string {
+	if TextLength(text) <= limit {
+		return text
+	}
+
+	if limit <= 0 {
+		return ""
+	}
+
+	head, _ := cutText(text, limit-1)
+
+	return head + "…"
+}
+
+// SplitText breaks text into pieces of at most limit UTF-16 code units.
+// Pieces end at the last newline or space that fits, and never inside a character.
+func SplitText(text string, limit int) []string {
+	var pieces []string
+	for TextLength(text) > limit {
+		head, rest := cutText(text, limit)
+
+		if i := strings.LastIndex(head, "\n"); i > 0 {
+			head, rest = head[:i], text[i+1:]
+		} else if i := strings.LastIndex(head, " "); i > 0 {
+			head, rest = head[:i], text[i+1:]
+		}
+
+		if head == "" {
+			break
+		}
+
+		pieces = append(pieces, head)
+		text = rest
+	}
+
+	if text != "" || len(pieces) == 0 {
+		pieces = append(pieces, text)
+	}
+
+	return pieces
+}
+
+// cutText splits text after the last whole character that fits in limit UTF-16 code units
+func cutText(text string, limit int) (string, string) {
+	length := 0
+	for i, r := range text {
+		length += runeLength(r)
+		if length > limit {
+			return text[:i], text[i:]
+		}
+	}
+
+	return text, ""
 }
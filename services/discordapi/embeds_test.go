@@ -0,0 +1,243 @@
+package discordapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// testField is an embeddable field with a fixed name and value
+type testField struct {
+	name  string
+	value string
+}
+
+// ConvertToEmbedField func
+func (f testField) ConvertToEmbedField() (*discordgo.MessageEmbedField, *Error) {
+	return &discordgo.MessageEmbedField{
+		Name:  f.name,
+		Value: f.value,
+	}, nil
+}
+
+func TestTextLength(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "empty", text: "", want: 0},
+		{name: "ascii", text: "hello", want: 5},
+		{name: "multi-byte in the BMP", text: "héllo…", want: 6},
+		{name: "surrogate pair", text: "😀", want: 2},
+		{name: "mixed", text: "a😀b", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TextLength(tt.text); got != tt.want {
+				t.Errorf("TextLength(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{name: "under the limit", text: "abc", limit: 5, want: "abc"},
+		{name: "at the limit", text: "abcde", limit: 5, want: "abcde"},
+		{name: "one over the limit", text: "abcdef", limit: 5, want: "abcd…"},
+		{name: "zero limit", text: "abc", limit: 0, want: ""},
+		{name: "limit of one", text: "abc", limit: 1, want: "…"},
+		{name: "surrogate pair at the limit", text: "ab😀", limit: 4, want: "ab😀"},
+		{name: "surrogate pair not split", text: "ab😀cd", limit: 4, want: "ab…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.text, tt.limit)
+			if got != tt.want {
+				t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if TextLength(got) > tt.limit && tt.limit >= 0 {
+				t.Errorf("TruncateText(%q, %d) is %d long", tt.text, tt.limit, TextLength(got))
+			}
+		})
+	}
+}
+
+func TestCutText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		wantHead string
+		wantRest string
+	}{
+		{name: "fits", text: "abc", limit: 3, wantHead: "abc", wantRest: ""},
+		{name: "one over", text: "abcd", limit: 3, wantHead: "abc", wantRest: "d"},
+		{name: "surrogate pair fits exactly", text: "a😀b", limit: 3, wantHead: "a😀", wantRest: "b"},
+		{name: "surrogate pair straddles the limit", text: "a😀b", limit: 2, wantHead: "a", wantRest: "😀b"},
+		{name: "zero limit", text: "abc", limit: 0, wantHead: "", wantRest: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, rest := cutText(tt.text, tt.limit)
+			if head != tt.wantHead || rest != tt.wantRest {
+				t.Errorf("cutText(%q, %d) = %q, %q, want %q, %q", tt.text, tt.limit, head, rest, tt.wantHead, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{name: "empty", text: "", limit: 5, want: []string{""}},
+		{name: "at the limit", text: "abcde", limit: 5, want: []string{"abcde"}},
+		{name: "one over without breaks", text: "abcdef", limit: 5, want: []string{"abcde", "f"}},
+		{name: "at a newline", text: "abc\ndefgh", limit: 5, want: []string{"abc", "defgh"}},
+		{name: "at a space", text: "ab cdefg", limit: 5, want: []string{"ab", "cdefg"}},
+		{name: "prefers the newline", text: "a b\ncdefg", limit: 5, want: []string{"a b", "cdefg"}},
+		{name: "surrogate pairs are kept whole", text: "😀😀😀", limit: 3, want: []string{"😀", "😀", "😀"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitText(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("SplitText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			for _, piece := range got {
+				if TextLength(piece) > tt.limit {
+					t.Errorf("SplitText(%q, %d) piece %q is %d long", tt.text, tt.limit, piece, TextLength(piece))
+				}
+			}
+		})
+	}
+}
+
+// repeatFields returns count fields with values of the given length
+func repeatFields(count int, length int) []EmbeddableField {
+	var fields []EmbeddableField
+	for i := 0; i < count; i++ {
+		fields = append(fields, testField{name: "f", value: strings.Repeat("a", length)})
+	}
+
+	return fields
+}
+
+func TestCreateEmbeds(t *testing.T) {
+	params := EmbeddableParams{
+		Title:  "Title",
+		Footer: "Footer",
+	}
+
+	tests := []struct {
+		name       string
+		fields     []EmbeddableField
+		wantFields []int
+	}{
+		{
+			name:       "no fields",
+			fields:     nil,
+			wantFields: []int{0},
+		},
+		{
+			name:       "exactly 25 fields",
+			fields:     repeatFields(25, 10),
+			wantFields: []int{25},
+		},
+		{
+			name:       "26 fields",
+			fields:     repeatFields(26, 10),
+			wantFields: []int{25, 1},
+		},
+		{
+			// Title and footer take 11 characters, so 5 fields of 1024 fit in 6000 but the sixth does not
+			name:       "full fields over 6000 characters",
+			fields:     repeatFields(6, 1023),
+			wantFields: []int{5, 1},
+		},
+		{
+			// 11 for the title and footer, 5 fields of 1024 and one of 869 add up to exactly 6000
+			name:       "exactly 6000 characters",
+			fields:     append(repeatFields(5, 1023), testField{name: "f", value: strings.Repeat("a", 868)}),
+			wantFields: []int{6},
+		},
+		{
+			name:       "one character over 6000",
+			fields:     append(repeatFields(5, 1023), testField{name: "f", value: strings.Repeat("a", 869)}),
+			wantFields: []int{5, 1},
+		},
+		{
+			name:       "field over the value limit is split",
+			fields:     []EmbeddableField{testField{name: "f", value: strings.Repeat("a", MaxEmbedFieldValueCharCount+1)}},
+			wantFields: []int{2},
+		},
+		{
+			name:       "more than 10 embeds are all kept",
+			fields:     repeatFields(11*MaxEmbedFields, 1),
+			wantFields: []int{25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embeds := CreateEmbeds(params, tt.fields)
+
+			var gotFields []int
+			for i := range embeds {
+				gotFields = append(gotFields, len(embeds[i].Fields))
+
+				if length := EmbedLength(&embeds[i]); length > MaxEmbedCharCount {
+					t.Errorf("embed %d is %d characters long", i, length)
+				}
+			}
+
+			if len(gotFields) != len(tt.wantFields) {
+				t.Fatalf("CreateEmbeds() field counts = %v, want %v", gotFields, tt.wantFields)
+			}
+			for i := range gotFields {
+				if gotFields[i] != tt.wantFields[i] {
+					t.Fatalf("CreateEmbeds() field counts = %v, want %v", gotFields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateEmbedsWithSources(t *testing.T) {
+	fields := []EmbeddableField{
+		testField{name: "a", value: "short"},
+		testField{name: "b", value: strings.Repeat("word ", MaxEmbedFieldValueCharCount/5+1)},
+		testField{name: "c", value: "short"},
+	}
+
+	embeds, sources := CreateEmbedsWithSources(EmbeddableParams{Title: "Title"}, fields)
+
+	want := []int{0, 1, 1, 2}
+	if len(sources) != len(want) {
+		t.Fatalf("sources = %v, want %v", sources, want)
+	}
+	for i := range want {
+		if sources[i] != want[i] {
+			t.Fatalf("sources = %v, want %v", sources, want)
+		}
+	}
+
+	if name := embeds[0].Fields[2].Name; name != "b"+continuedFieldSuffix {
+		t.Errorf("continued field name = %q, want %q", name, "b"+continuedFieldSuffix)
+	}
+}
@@ -2,7 +2,14 @@ package discordapi
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
 )
 
 // DiscordError struct
@@ -13,9 +20,11 @@ type DiscordError struct {
 
 // Error struct
 type Error struct {
-	Message string `json:"message"`
-	Err     error  `json:"error"`
-	Code    int    `json:"code"`
+	Message    string        `json:"message"`
+	Err        error         `json:"error"`
+	Code       int           `json:"code"`
+	StatusCode int           `json:"status_code"`
+	RetryAfter time.Duration `json:"retry_after"`
 }
 
 // Error func
@@ -25,6 +34,39 @@ func (e *Error) Error() string {
 
 // ParseDiscordError func
 func ParseDiscordError(err error) *Error {
+	parsedErr := parseDiscordErrorBody(err)
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		parsedErr.StatusCode = restErr.Response.StatusCode
+		if parsedErr.StatusCode == http.StatusTooManyRequests {
+			parsedErr.RetryAfter = parseRetryAfter(restErr)
+		}
+	}
+
+	metrics.DiscordErrors.WithLabelValues(strconv.Itoa(parsedErr.Code), strconv.Itoa(parsedErr.StatusCode)).Inc()
+
+	return parsedErr
+}
+
+// parseRetryAfter reads the retry delay from a 429 response body or Retry-After header
+func parseRetryAfter(restErr *discordgo.RESTError) time.Duration {
+	var body struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if json.Unmarshal(restErr.ResponseBody, &body) == nil && body.RetryAfter > 0 {
+		return time.Duration(body.RetryAfter * float64(time.Second))
+	}
+
+	if seconds, pErr := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64); pErr == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	return 0
+}
+
+// parseDiscordErrorBody func
+func parseDiscordErrorBody(err error) *Error {
 	splitErr := strings.SplitN(err.Error(), ", ", 2)
 
 	if len(splitErr) != 2 {
@@ -1,13 +1,80 @@
 package discordapi
 
 import (
+	"encoding/json"
+	"io"
+
 	"github.com/bwmarrin/discordgo"
 )
 
+// MaxMessageEmbeds const
+const MaxMessageEmbeds = 10
+
+// MaxMessageEmbedCharCount const
+const MaxMessageEmbedCharCount = 6000
+
+// messageEmbedsSend is the message create payload for sending several embeds at once
+type messageEmbedsSend struct {
+	Embeds          []*discordgo.MessageEmbed         `json:"embeds"`
+	AllowedMentions *discordgo.MessageAllowedMentions `json:"allowed_mentions"`
+}
+
 // SendMessage func
 func SendMessage(session *discordgo.Session, channelID string, content *string, embed *discordgo.MessageEmbed) (*discordgo.Message, *Error) {
 	messageSend := &discordgo.MessageSend{
-		Embed: embed,
+		Embed:           embed,
+		AllowedMentions: allowedMentions(),
+	}
+	if content != nil {
+		messageSend.Content = *content
+	}
+
+	message, err := session.ChannelMessageSendComplex(channelID, messageSend)
+
+	if err != nil {
+		return nil, ParseDiscordError(err)
+	}
+
+	return message, nil
+}
+
+// SendEmbeds sends up to MaxMessageEmbeds embeds in a single message
+func SendEmbeds(session *discordgo.Session, channelID string, embeds []*discordgo.MessageEmbed) (*discordgo.Message, *Error) {
+	for _, embed := range embeds {
+		if embed.Type == "" {
+			embed.Type = "rich"
+		}
+	}
+
+	endpoint := discordgo.EndpointChannelMessages(channelID)
+	response, err := session.RequestWithBucketID("POST", endpoint, &messageEmbedsSend{Embeds: embeds, AllowedMentions: allowedMentions()}, endpoint)
+	if err != nil {
+		return nil, ParseDiscordError(err)
+	}
+
+	var message discordgo.Message
+	if umErr := json.Unmarshal(response, &message); umErr != nil {
+		return nil, &Error{
+			Code:    -1,
+			Err:     umErr,
+			Message: "Failed to unmarshal sent message",
+		}
+	}
+
+	return &message, nil
+}
+
+// SendFile func
+func SendFile(session *discordgo.Session, channelID string, content *string, fileName string, contentType string, reader io.Reader) (*discordgo.Message, *Error) {
+	messageSend := &discordgo.MessageSend{
+		Files: []*discordgo.File{
+			{
+				Name:        fileName,
+				ContentType: contentType,
+				Reader:      reader,
+			},
+		},
+		AllowedMentions: allowedMentions(),
 	}
 	if content != nil {
 		messageSend.Content = *content
@@ -25,10 +92,11 @@ func SendMessage(session *discordgo.Session, channelID string, content *string,
 // EditMessage func
 func EditMessage(session *discordgo.Session, channelID string, messageID string, content *string, embed *discordgo.MessageEmbed) (*discordgo.Message, *Error) {
 	message, err := session.ChannelMessageEditComplex(&discordgo.MessageEdit{
-		Content: content,
-		Embed:   embed,
-		ID:      messageID,
-		Channel: channelID,
+		Content:         content,
+		Embed:           embed,
+		AllowedMentions: allowedMentions(),
+		ID:              messageID,
+		Channel:         channelID,
 	})
 
 	if err != nil {
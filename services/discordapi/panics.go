@@ -0,0 +1,89 @@
+package discordapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// maxPanicLength keeps the panic value, with its code block, within an embed field
+const maxPanicLength = MaxEmbedFieldValueCharCount - 6
+
+// PanicReporter shows recovered panics in Discord for the recovery package.
+// It sends directly rather than through the scheduler, since a panic in a scheduler worker could otherwise wait on itself.
+type PanicReporter struct {
+	Session *discordgo.Session
+	Config  *configs.Config
+}
+
+// NotifyUser tells a user their command failed without exposing the panic
+func (pr *PanicReporter) NotifyUser(ctx context.Context, channelID string) {
+	pr.send(ctx, channelID, &discordgo.MessageEmbed{
+		Title:       "Error",
+		Description: "Something went wrong. The error has been reported, please try again later.",
+		Color:       pr.Config.Bot.ErrorColor,
+		URL:         pr.Config.Bot.DocumentationURL,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: pr.Config.Bot.ErrorThumbnail,
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Error",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// ReportPanic describes a panic in the operator channel
+func (pr *PanicReporter) ReportPanic(ctx context.Context, channelID string, report recovery.Report) {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Location",
+			Value: TruncateText(report.Location, MaxEmbedFieldValueCharCount),
+		},
+		{
+			Name:  "Error",
+			Value: "```" + TruncateText(EscapeCode(fmt.Sprintf("%v", report.Value)), maxPanicLength) + "```",
+		},
+		{
+			Name:  "Count",
+			Value: fmt.Sprintf("%d since the last report, %d since start", report.Since, report.Total),
+		},
+	}
+
+	values := logging.GetValues(ctx)
+	for _, key := range []string{"request_id", "guild_id", "runner"} {
+		if field, ok := values[key]; ok && field.String != "" {
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:   key,
+				Value:  field.String,
+				Inline: true,
+			})
+		}
+	}
+
+	pr.send(ctx, channelID, &discordgo.MessageEmbed{
+		Title:     "Panic recovered",
+		Color:     pr.Config.Bot.ErrorColor,
+		Fields:    fields,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// send posts an embed, logging rather than returning any error
+func (pr *PanicReporter) send(ctx context.Context, channelID string, embed *discordgo.MessageEmbed) {
+	if _, err := SendMessage(pr.Session, channelID, nil, embed); err != nil {
+		ctx = logging.AddValues(ctx,
+			zap.NamedError("error", err.Err),
+			zap.String("error_message", "Failed to send panic message: "+err.Message),
+			zap.String("report_channel_id", channelID),
+		)
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+	}
+}
@@ -0,0 +1,50 @@
+package discordapi
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// markdownEscaper escapes every character Discord renders as markdown, including the
+// brackets used by masked links and the angle bracket that starts user, role and channel mentions
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+	`|`, `\|`,
+	`>`, `\>`,
+	`<`, `\<`,
+	`#`, `\#`,
+	`-`, `\-`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`(`, `\(`,
+	`)`, `\)`,
+)
+
+// mentionNeutralizer breaks @everyone, @here and links so Discord shows them as plain text
+var mentionNeutralizer = strings.NewReplacer(
+	"@", "@\u200b",
+	"://", "\u200b://",
+)
+
+// EscapeText makes player supplied text safe to put in an embed.
+// Markdown is escaped, mentions can't notify anyone and links aren't clickable.
+func EscapeText(text string) string {
+	return mentionNeutralizer.Replace(markdownEscaper.Replace(text))
+}
+
+// EscapeCode makes player supplied text safe to put inside an inline code span or code block
+func EscapeCode(text string) string {
+	return strings.Replace(text, "`", "'", -1)
+}
+
+// allowedMentions stops Discord from parsing any mentions that a message wasn't explicitly allowed to make
+func allowedMentions() *discordgo.MessageAllowedMentions {
+	return &discordgo.MessageAllowedMentions{
+		Parse: []discordgo.AllowedMentionType{},
+	}
+}
@@ -0,0 +1,398 @@
+package discordapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+)
+
+// Scheduler queues outgoing embeds per channel and sends them from a fixed set of workers.
+// Guilds take turns so one busy guild cannot starve the rest, each channel has at most one
+// message in flight so order is kept, and queued embeds are packed into as few messages as
+// Discord allows.
+type Scheduler struct {
+	Session *discordgo.Session
+	Workers int
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	guilds    []*guildQueue
+	nextGuild int
+	byGuild   map[string]*guildQueue
+	byChannel map[string]*channelQueue
+}
+
+// guildQueue struct
+type guildQueue struct {
+	id          string
+	channels    []*channelQueue
+	nextChannel int
+}
+
+// channelQueue struct
+type channelQueue struct {
+	id       string
+	guild    *guildQueue
+	pending  []*scheduledEmbed
+	inFlight bool
+}
+
+// scheduledEmbed struct
+// An embed with an editID replaces the embed of that message instead of being sent.
+// Edits and standalone embeds are never packed with other embeds.
+type scheduledEmbed struct {
+	embed      *discordgo.MessageEmbed
+	webhook    *WebhookTarget
+	editID     string
+	standalone bool
+	group      *sendGroup
+	index      int
+}
+
+// sendGroup tracks the embeds from a single SendEmbeds call
+type sendGroup struct {
+	messages  []*discordgo.Message
+	err       *Error
+	remaining int
+	done      chan struct{}
+}
+
+// NewScheduler func
+func NewScheduler(session *discordgo.Session, workers int) *Scheduler {
+	s := &Scheduler{
+		Session:   session,
+		Workers:   workers,
+		byGuild:   make(map[string]*guildQueue),
+		byChannel: make(map[string]*channelQueue),
+	}
+	s.cond = sync.NewCond(&s.mu)
+
+	return s
+}
+
+// Start launches the send workers
+func (s *Scheduler) Start() {
+	for i := 0; i < s.Workers; i++ {
+		go s.work()
+	}
+}
+
+// Drain waits until every queued embed has been sent
+func (s *Scheduler) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mu.Lock()
+		for len(s.byChannel) > 0 {
+			s.cond.Wait()
+		}
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendEmbeds queues embeds for a channel and waits until they are sent.
+// The returned messages are narrowed to the caller's embed, one per embed, in order.
+// If a send fails, the embeds sent before it are returned along with the error and the rest are not sent.
+func (s *Scheduler) SendEmbeds(guildID string, channelID string, embeds []discordgo.MessageEmbed) ([]*discordgo.Message, *Error) {
+	return s.send(guildID, channelID, nil, embeds)
+}
+
+// SendWebhookEmbeds works like SendEmbeds but posts through a webhook in the channel
+func (s *Scheduler) SendWebhookEmbeds(guildID string, channelID string, webhook WebhookTarget, embeds []discordgo.MessageEmbed) ([]*discordgo.Message, *Error) {
+	return s.send(guildID, channelID, &webhook, embeds)
+}
+
+// EditEmbed queues an edit of one of the bot's messages in a channel and waits until it is made.
+// Edits share the channel's queue with sends so they keep their order and rate limit.
+func (s *Scheduler) EditEmbed(guildID string, channelID string, messageID string, embed discordgo.MessageEmbed) (*discordgo.Message, *Error) {
+	messages, err := s.queue(guildID, channelID, nil, messageID, true, []discordgo.MessageEmbed{embed})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages[0], nil
+}
+
+// SendStandaloneEmbed works like SendEmbed but never packs the embed with others,
+// so the message can later be edited with EditEmbed without touching anyone else's embeds
+func (s *Scheduler) SendStandaloneEmbed(guildID string, channelID string, embed discordgo.MessageEmbed) (*discordgo.Message, *Error) {
+	messages, err := s.queue(guildID, channelID, nil, "", true, []discordgo.MessageEmbed{embed})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages[0], nil
+}
+
+// send queues embeds and waits for them to be sent as the bot user, or through a webhook if one is given
+func (s *Scheduler) send(guildID string, channelID string, webhook *WebhookTarget, embeds []discordgo.MessageEmbed) ([]*discordgo.Message, *Error) {
+	return s.queue(guildID, channelID, webhook, "", false, embeds)
+}
+
+// queue adds embeds to a channel's queue and waits until every one is sent or edited
+func (s *Scheduler) queue(guildID string, channelID string, webhook *WebhookTarget, editID string, standalone bool, embeds []discordgo.MessageEmbed) ([]*discordgo.Message, *Error) {
+	if len(embeds) == 0 {
+		return nil, nil
+	}
+
+	group := &sendGroup{
+		messages:  make([]*discordgo.Message, len(embeds)),
+		remaining: len(embeds),
+		done:      make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	cq := s.channelQueue(guildID, channelID)
+	for i := range embeds {
+		embed := embeds[i]
+		cq.pending = append(cq.pending, &scheduledEmbed{
+			embed:      &embed,
+			webhook:    webhook,
+			editID:     editID,
+			standalone: standalone,
+			group:      group,
+			index:      i,
+		})
+	}
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	<-group.done
+
+	var sent []*discordgo.Message
+	for _, message := range group.messages {
+		if message == nil {
+			break
+		}
+		sent = append(sent, message)
+	}
+
+	return sent, group.err
+}
+
+// SendEmbed queues a single embed for a channel and waits until it is sent
+func (s *Scheduler) SendEmbed(guildID string, channelID string, embed discordgo.MessageEmbed) (*discordgo.Message, *Error) {
+	messages, err := s.SendEmbeds(guildID, channelID, []discordgo.MessageEmbed{embed})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages[0], nil
+}
+
+// work sends batches until the process exits
+func (s *Scheduler) work() {
+	for {
+		cq, batch := s.nextBatch()
+		message, err := s.sendBatch(cq, batch)
+		s.complete(cq, batch, message, err)
+	}
+}
+
+// sendBatch sends one batch as a single message.
+// A panic while sending is recovered and returned as the error so the channel is still freed.
+func (s *Scheduler) sendBatch(cq *channelQueue, batch []*scheduledEmbed) (message *discordgo.Message, err *Error) {
+	err = &Error{
+		Message: "Failed to send embeds",
+		Err:     errors.New("panic while sending"),
+	}
+	defer recovery.Recover(context.Background(), "discord_scheduler", "")
+
+	embeds := make([]*discordgo.MessageEmbed, len(batch))
+	for i, item := range batch {
+		embeds[i] = item.embed
+	}
+
+	if batch[0].editID != "" {
+		return EditMessage(s.Session, cq.id, batch[0].editID, nil, embeds[0])
+	}
+
+	if batch[0].webhook != nil {
+		return ExecuteWebhook(s.Session, *batch[0].webhook, embeds)
+	}
+
+	return SendEmbeds(s.Session, cq.id, embeds)
+}
+
+// nextBatch waits for a channel with queued embeds, taking turns between guilds and then channels
+func (s *Scheduler) nextBatch() (*channelQueue, []*scheduledEmbed) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		for g := 0; g < len(s.guilds); g++ {
+			gq := s.guilds[(s.nextGuild+g)%len(s.guilds)]
+
+			for c := 0; c < len(gq.channels); c++ {
+				cq := gq.channels[(gq.nextChannel+c)%len(gq.channels)]
+				if cq.inFlight || len(cq.pending) == 0 {
+					continue
+				}
+
+				s.nextGuild = (s.nextGuild + g + 1) % len(s.guilds)
+				gq.nextChannel = (gq.nextChannel + c + 1) % len(gq.channels)
+
+				cq.inFlight = true
+				return cq, s.takeBatch(cq)
+			}
+		}
+
+		s.cond.Wait()
+	}
+}
+
+// takeBatch removes as many queued embeds as fit in one message from the same sender. A standalone embed or edit is always taken on its own.
+func (s *Scheduler) takeBatch(cq *channelQueue) []*scheduledEmbed {
+	var batch []*scheduledEmbed
+	length := 0
+
+	for len(cq.pending) > 0 && len(batch) < MaxMessageEmbeds {
+		item := cq.pending[0]
+		itemLength := EmbedLength(item.embed)
+		if len(batch) > 0 && (length+itemLength > MaxMessageEmbedCharCount || webhookID(item.webhook) != webhookID(batch[0].webhook) || item.standalone) {
+			break
+		}
+
+		batch = append(batch, item)
+		length += itemLength
+		cq.pending = cq.pending[1:]
+
+		if item.standalone {
+			break
+		}
+	}
+
+	return batch
+}
+
+// complete hands results back to the waiting callers and frees the channel
+func (s *Scheduler) complete(cq *channelQueue, batch []*scheduledEmbed, message *discordgo.Message, err *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range batch {
+		if err != nil {
+			s.fail(item.group, err)
+			continue
+		}
+
+		narrowed := *message
+		narrowed.Embeds = []*discordgo.MessageEmbed{item.embed}
+		if i < len(message.Embeds) {
+			narrowed.Embeds = []*discordgo.MessageEmbed{message.Embeds[i]}
+		}
+
+		item.group.messages[item.index] = &narrowed
+		s.resolve(item.group, 1)
+	}
+
+	// Once any embed in a group fails the rest of that group is dropped so callers never see gaps
+	if err != nil {
+		var pending []*scheduledEmbed
+		for _, item := range cq.pending {
+			if item.group.err != nil {
+				s.resolve(item.group, 1)
+				continue
+			}
+			pending = append(pending, item)
+		}
+		cq.pending = pending
+	}
+
+	cq.inFlight = false
+	if len(cq.pending) == 0 {
+		s.removeChannel(cq)
+	}
+
+	s.cond.Broadcast()
+}
+
+// fail records the first error for a group
+func (s *Scheduler) fail(group *sendGroup, err *Error) {
+	if group.err == nil {
+		group.err = err
+	}
+	s.resolve(group, 1)
+}
+
+// resolve marks embeds in a group as finished and wakes the caller once all are
+func (s *Scheduler) resolve(group *sendGroup, count int) {
+	group.remaining -= count
+	if group.remaining == 0 {
+		close(group.done)
+	}
+}
+
+// channelQueue returns the queue for a channel, creating it and its guild queue if needed
+func (s *Scheduler) channelQueue(guildID string, channelID string) *channelQueue {
+	if cq, ok := s.byChannel[channelID]; ok {
+		return cq
+	}
+
+	gq, ok := s.byGuild[guildID]
+	if !ok {
+		gq = &guildQueue{
+			id: guildID,
+		}
+		s.byGuild[guildID] = gq
+		s.guilds = append(s.guilds, gq)
+	}
+
+	cq := &channelQueue{
+		id:    channelID,
+		guild: gq,
+	}
+	s.byChannel[channelID] = cq
+	gq.channels = append(gq.channels, cq)
+
+	return cq
+}
+
+// removeChannel drops an idle channel and its guild once the guild has no channels left
+func (s *Scheduler) removeChannel(cq *channelQueue) {
+	delete(s.byChannel, cq.id)
+
+	gq := cq.guild
+	for i, val := range gq.channels {
+		if val == cq {
+			gq.channels = append(gq.channels[:i], gq.channels[i+1:]...)
+			break
+		}
+	}
+	if len(gq.channels) > 0 {
+		gq.nextChannel %= len(gq.channels)
+		return
+	}
+
+	delete(s.byGuild, gq.id)
+	for i, val := range s.guilds {
+		if val == gq {
+			s.guilds = append(s.guilds[:i], s.guilds[i+1:]...)
+			break
+		}
+	}
+	if len(s.guilds) > 0 {
+		s.nextGuild %= len(s.guilds)
+	} else {
+		s.nextGuild = 0
+	}
+}
+
+// webhookID returns the ID of a webhook target, or an empty string for the bot user
+func webhookID(webhook *WebhookTarget) string {
+	if webhook == nil {
+		return ""
+	}
+
+	return webhook.ID
+}
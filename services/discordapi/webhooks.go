@@ -0,0 +1,82 @@
+package discordapi
+
+import (
+	"encoding/json"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxWebhookUsernameLength const
+const MaxWebhookUsernameLength = 80
+
+// WebhookTarget identifies a webhook and how its posts should appear
+type WebhookTarget struct {
+	ID        string
+	Token     string
+	Username  string
+	AvatarURL string
+}
+
+// CreateWebhook func
+func CreateWebhook(session *discordgo.Session, channelID string, name string) (*discordgo.Webhook, *Error) {
+	webhook, err := session.WebhookCreate(channelID, name, "")
+	if err != nil {
+		return nil, ParseDiscordError(err)
+	}
+
+	return webhook, nil
+}
+
+// GetChannelWebhooks func
+func GetChannelWebhooks(session *discordgo.Session, channelID string) ([]*discordgo.Webhook, *Error) {
+	webhooks, err := session.ChannelWebhooks(channelID)
+	if err != nil {
+		return nil, ParseDiscordError(err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook func
+func DeleteWebhook(session *discordgo.Session, webhookID string) *Error {
+	err := session.WebhookDelete(webhookID)
+	if err != nil {
+		return ParseDiscordError(err)
+	}
+
+	return nil
+}
+
+// ExecuteWebhook posts embeds through a webhook and waits for the created message.
+// Each webhook gets its own rate limit bucket rather than sharing one for every webhook.
+func ExecuteWebhook(session *discordgo.Session, target WebhookTarget, embeds []*discordgo.MessageEmbed) (*discordgo.Message, *Error) {
+	for _, embed := range embeds {
+		if embed.Type == "" {
+			embed.Type = "rich"
+		}
+	}
+
+	params := &discordgo.WebhookParams{
+		Username:        TruncateText(target.Username, MaxWebhookUsernameLength),
+		AvatarURL:       target.AvatarURL,
+		Embeds:          embeds,
+		AllowedMentions: allowedMentions(),
+	}
+
+	endpoint := discordgo.EndpointWebhookToken(target.ID, target.Token) + "?wait=true"
+	response, err := session.RequestWithBucketID("POST", endpoint, params, discordgo.EndpointWebhook(target.ID))
+	if err != nil {
+		return nil, ParseDiscordError(err)
+	}
+
+	var message discordgo.Message
+	if umErr := json.Unmarshal(response, &message); umErr != nil {
+		return nil, &Error{
+			Code:    -1,
+			Err:     umErr,
+			Message: "Failed to unmarshal webhook message",
+		}
+	}
+
+	return &message, nil
+}
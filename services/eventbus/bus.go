@@ -0,0 +1,286 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/BIC_Dev/guild-config-service-client/gcscmodels"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// Bus passes events from the runners and reactions to every feature that consumes them.
+// Each subscriber has its own bounded queues, one per worker, and a server's batches always go to the same queue
+// so they are handled one at a time and in order. When a queue is full new batches for a best effort subscriber are dropped
+// and counted so a slow consumer never holds up the runners or the other subscribers. Publishing to a reliable subscriber
+// instead waits up to the publish timeout for room.
+type Bus struct {
+	BufferSize     int
+	Workers        int
+	PublishTimeout time.Duration
+
+	mu          sync.RWMutex
+	subscribers []*subscriber
+	closed      bool
+	workers     sync.WaitGroup
+}
+
+// Batch is a set of events from one server, such as the entries from a single log fetch
+type Batch struct {
+	Server gcscmodels.Server
+	Events []Event
+}
+
+// Handler processes a batch containing only the event types it subscribed to
+type Handler func(ctx context.Context, batch Batch)
+
+// SubscriberStats struct
+type SubscriberStats struct {
+	Name      string
+	Queued    int
+	Delivered uint64
+	Dropped   uint64
+}
+
+// subscriber struct
+// The counters come first so they stay 64-bit aligned for atomic access on 32-bit platforms.
+type subscriber struct {
+	delivered uint64
+	dropped   uint64
+
+	name     string
+	types    map[string]bool
+	handler  Handler
+	reliable bool
+	queues   []chan delivery
+}
+
+// delivery struct
+type delivery struct {
+	ctx   context.Context
+	batch Batch
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// NewBus func
+func NewBus(config *configs.Config) *Bus {
+	workers := config.EventBus.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Bus{
+		BufferSize:     config.EventBus.BufferSize,
+		Workers:        workers,
+		PublishTimeout: config.EventBus.PublishTimeout * time.Second,
+	}
+}
+
+// Subscribe registers a best effort handler for the given event types and starts its workers.
+// Its batches are dropped when its queue is full, so it suits consumers such as stats that can miss a batch.
+func (b *Bus) Subscribe(name string, handler Handler, types ...string) {
+	b.subscribe(name, handler, false, types)
+}
+
+// SubscribeReliable registers a handler that must see every batch, such as one posting logs to Discord.
+// Publishing waits for room in its queue instead of dropping the batch straight away.
+func (b *Bus) SubscribeReliable(name string, handler Handler, types ...string) {
+	b.subscribe(name, handler, true, types)
+}
+
+// subscribe creates the subscriber's queues and starts one worker for each
+func (b *Bus) subscribe(name string, handler Handler, reliable bool, types []string) {
+	sub := &subscriber{
+		name:     name,
+		types:    make(map[string]bool),
+		handler:  handler,
+		reliable: reliable,
+	}
+	for _, eventType := range types {
+		sub.types[eventType] = true
+	}
+
+	queueSize := (b.BufferSize + b.Workers - 1) / b.Workers
+	for i := 0; i < b.Workers; i++ {
+		queue := make(chan delivery, queueSize)
+		sub.queues = append(sub.queues, queue)
+
+		b.workers.Add(1)
+		go func() {
+			defer b.workers.Done()
+			sub.work(queue)
+		}()
+	}
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, sub)
+	b.mu.Unlock()
+}
+
+// Publish queues a batch of a server's events for each subscriber that wants any of them.
+// It only blocks while a reliable subscriber's queue is full, and returns an error if that subscriber's batch was still dropped.
+// Events published once the bus is closed are ignored.
+func (b *Bus) Publish(ctx context.Context, server gcscmodels.Server, events ...Event) *Error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil
+	}
+
+	var dropErr *Error
+
+	for _, sub := range b.subscribers {
+		var wanted []Event
+		for _, event := range events {
+			if sub.types[event.EventType()] {
+				wanted = append(wanted, event)
+			}
+		}
+
+		if len(wanted) == 0 {
+			continue
+		}
+
+		if sub.enqueue(delivery{ctx: ctx, batch: Batch{Server: server, Events: wanted}}, b.PublishTimeout) {
+			continue
+		}
+
+		dropped := atomic.AddUint64(&sub.dropped, 1)
+		metrics.EventBusDropped.WithLabelValues(sub.name).Inc()
+
+		newCtx := logging.AddValues(ctx,
+			zap.String("scope", logging.GetFuncName()),
+			zap.String("subscriber", sub.name),
+			zap.Int("events", len(wanted)),
+			zap.Uint64("dropped_batches", dropped),
+			zap.NamedError("error", errors.New("subscriber queue full")),
+			zap.String("error_message", "Dropped event batch for slow subscriber"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+
+		if sub.reliable {
+			dropErr = &Error{
+				Message: "Dropped event batch for " + sub.name,
+				Err:     errors.New("subscriber queue full"),
+			}
+		}
+	}
+
+	return dropErr
+}
+
+// Close stops accepting events and waits for every subscriber to finish the batches already queued
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subscribers {
+			for _, queue := range sub.queues {
+				close(queue)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the queue length and delivery counts of every subscriber
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var stats []SubscriberStats
+	for _, sub := range b.subscribers {
+		queued := 0
+		for _, queue := range sub.queues {
+			queued += len(queue)
+		}
+
+		stats = append(stats, SubscriberStats{
+			Name:      sub.name,
+			Queued:    queued,
+			Delivered: atomic.LoadUint64(&sub.delivered),
+			Dropped:   atomic.LoadUint64(&sub.dropped),
+		})
+	}
+
+	return stats
+}
+
+// enqueue puts a batch on the queue for its server, waiting up to timeout for room if the subscriber is reliable
+func (sub *subscriber) enqueue(d delivery, timeout time.Duration) bool {
+	queue := sub.queues[d.batch.Server.ID%uint64(len(sub.queues))]
+
+	select {
+	case queue <- d:
+		metrics.EventBusQueueDepth.WithLabelValues(sub.name).Inc()
+		return true
+	default:
+	}
+
+	if !sub.reliable || timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case queue <- d:
+		metrics.EventBusQueueDepth.WithLabelValues(sub.name).Inc()
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// work runs the subscriber's handler for each batch on one of its queues until the bus is closed
+func (sub *subscriber) work(queue chan delivery) {
+	for d := range queue {
+		metrics.EventBusQueueDepth.WithLabelValues(sub.name).Dec()
+		sub.deliver(d)
+	}
+}
+
+// deliver runs the handler for one batch, recovering its panics so the worker keeps going
+func (sub *subscriber) deliver(d delivery) {
+	defer recovery.Recover(d.ctx, "event_bus:"+sub.name, "")
+
+	sub.handler(d.ctx, d.batch)
+	atomic.AddUint64(&sub.delivered, 1)
+	metrics.EventBusDelivered.WithLabelValues(sub.name).Inc()
+}
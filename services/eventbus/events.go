@@ -0,0 +1,91 @@
+package eventbus
+
+import (
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+)
+
+// Event types
+const (
+	TypeLogsFetched      = "logs_fetched"
+	TypeChatMessage      = "chat_message"
+	TypeAdminCommand     = "admin_command"
+	TypeKill             = "kill"
+	TypePlayersSnapshot  = "players_snapshot"
+	TypeModerationAction = "moderation_action"
+	TypeServerControl    = "server_control"
+)
+
+// Server control actions
+const (
+	ActionRestart = "restart"
+	ActionStop    = "stop"
+)
+
+// Event is anything published on the bus
+type Event interface {
+	EventType() string
+}
+
+// LogsFetched starts every batch of log events and says which log types were requested.
+// Subscribers that retry earlier entries rely on it to run even when nothing new was fetched.
+type LogsFetched struct {
+	Chat  bool
+	Admin bool
+	Kills bool
+}
+
+// ChatMessage is a chat log entry fetched from a server
+type ChatMessage struct {
+	Log nsv2.PlayerLog
+}
+
+// AdminCommand is an admin log entry fetched from a server
+type AdminCommand struct {
+	Log nsv2.AdminLog
+}
+
+// Kill is a kill log entry fetched from a server
+type Kill struct {
+	Log nsv2.KillLog
+}
+
+// PlayersSnapshot is the result of an online players poll.
+// When the poll failed Online is false and Error describes why.
+type PlayersSnapshot struct {
+	Online       bool
+	Players      []nsv2.Player
+	Error        string
+	ErrorMessage string
+}
+
+// ModerationAction is a ban, unban, whitelist or unwhitelist issued through the bot
+type ModerationAction struct {
+	Action string
+	Player string
+}
+
+// ServerControl is a restart or stop issued through the bot
+type ServerControl struct {
+	Action string
+}
+
+// EventType func
+func (e LogsFetched) EventType() string { return TypeLogsFetched }
+
+// EventType func
+func (e ChatMessage) EventType() string { return TypeChatMessage }
+
+// EventType func
+func (e AdminCommand) EventType() string { return TypeAdminCommand }
+
+// EventType func
+func (e Kill) EventType() string { return TypeKill }
+
+// EventType func
+func (e PlayersSnapshot) EventType() string { return TypePlayersSnapshot }
+
+// EventType func
+func (e ModerationAction) EventType() string { return TypeModerationAction }
+
+// EventType func
+func (e ServerControl) EventType() string { return TypeServerControl }
@@ -0,0 +1,49 @@
+package eventstream
+
+import (
+	"fmt"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// Stream appends game events to a Redis Stream per guild so external tools can read them with consumer groups
+type Stream struct {
+	Cache     *cache.Cache
+	Settings  configs.CacheSetting
+	MaxLength int
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitStream sets up event stream publishing, returning nil when it is disabled
+func InitStream(config *configs.Config, ca *cache.Cache) *Stream {
+	if !config.CacheSettings.EventStreams.Enabled {
+		return nil
+	}
+
+	return &Stream{
+		Cache:     ca,
+		Settings:  config.CacheSettings.EventStreams,
+		MaxLength: config.EventStreams.MaxLength,
+	}
+}
+
+// StreamKey is the stream holding a guild's events
+func StreamKey(base string, guildID string) string {
+	return fmt.Sprintf("%s:%s", base, guildID)
+}
+
+// seenKey marks an event as already added so repeated logs aren't added twice
+func seenKey(base string, guildID string, eventID string) string {
+	return fmt.Sprintf("%s:seen:%s:%s", base, guildID, eventID)
+}
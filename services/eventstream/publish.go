@@ -0,0 +1,124 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Stream entry fields.
+// Type and version are repeated outside the event so consumers can skip entries without decoding them.
+const (
+	FieldID       = "id"
+	FieldType     = "type"
+	FieldVersion  = "version"
+	FieldServerID = "server_id"
+	FieldEvent    = "event"
+)
+
+// Publish appends events to the guild's stream, trimming it to roughly the configured length.
+// Events with an ID that was already added are skipped, so logs fetched twice are only added once.
+// Streams are never expired, since that would also delete the consumer groups reading them.
+func (s *Stream) Publish(ctx context.Context, guildID string, events []models.GameEvent) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()), zap.String("guild_id", guildID))
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	bodies := make(map[string]string, len(events))
+	for _, event := range events {
+		body, mErr := json.Marshal(event)
+		if mErr != nil {
+			return &Error{
+				Message: "Failed to marshal stream event",
+				Err:     mErr,
+			}
+		}
+		bodies[event.ID] = string(body)
+	}
+
+	fresh, uErr := s.unseen(guildID, events)
+	if uErr != nil {
+		return uErr
+	}
+
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	key := StreamKey(s.Settings.Base, guildID)
+
+	var cmds []radix.CmdAction
+	for _, event := range fresh {
+		cmds = append(cmds, radix.Cmd(nil, "XADD", key, "MAXLEN", "~", fmt.Sprint(s.MaxLength), "*",
+			FieldID, event.ID,
+			FieldType, event.Type,
+			FieldVersion, fmt.Sprint(event.Version),
+			FieldServerID, fmt.Sprint(event.ServerID),
+			FieldEvent, bodies[event.ID],
+		))
+	}
+
+	if err := s.Cache.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		// The events were marked as added before the XADD, so they are unmarked to let the next fetch add them
+		s.forget(ctx, guildID, fresh)
+
+		return &Error{
+			Message: "Failed to add events to stream",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// unseen marks events as added and returns the ones that weren't already
+func (s *Stream) unseen(guildID string, events []models.GameEvent) ([]models.GameEvent, *Error) {
+	results := make([]radix.MaybeNil, len(events))
+	replies := make([]string, len(events))
+
+	var cmds []radix.CmdAction
+	for i, event := range events {
+		results[i] = radix.MaybeNil{Rcv: &replies[i]}
+		cmds = append(cmds, radix.Cmd(&results[i], "SET", seenKey(s.Settings.Base, guildID, event.ID), "1", "EX", s.Settings.TTL, "NX"))
+	}
+
+	if err := s.Cache.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return nil, &Error{
+			Message: "Failed to check for added events",
+			Err:     err,
+		}
+	}
+
+	var fresh []models.GameEvent
+	for i, event := range events {
+		if !results[i].Nil {
+			fresh = append(fresh, event)
+		}
+	}
+
+	return fresh, nil
+}
+
+// forget unmarks events that unseen marked but that never made it into the stream
+func (s *Stream) forget(ctx context.Context, guildID string, events []models.GameEvent) {
+	keys := make([]string, len(events))
+	for i, event := range events {
+		keys[i] = seenKey(s.Settings.Base, guildID, event.ID)
+	}
+
+	if err := s.Cache.Client.Do(radix.Cmd(nil, "DEL", keys...)); err != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", err),
+			zap.String("error_message", "Failed to unmark events that were not added to the stream"),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+}
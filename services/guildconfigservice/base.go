@@ -8,6 +8,7 @@ import (
 	"github.com/go-openapi/strfmt"
 	"gitlab.com/BIC_Dev/guild-config-service-client/gcsc"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
 	"go.uber.org/zap"
 )
@@ -16,6 +17,7 @@ import (
 type GuildConfigService struct {
 	Client *gcsc.GuildConfigServiceClient
 	Auth   runtime.ClientAuthInfoWriter
+	Feeds  *FeedCache
 }
 
 // Error struct
@@ -30,11 +32,11 @@ func (e *Error) Error() string {
 }
 
 // InitService initializes the guild config service client
-func InitService(ctx context.Context, config *configs.Config, serviceToken string) *GuildConfigService {
+func InitService(ctx context.Context, config *configs.Config, ca *cache.Cache, serviceToken string) *GuildConfigService {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
 
 	transport := httptransport.New(config.GuildConfigService.Host, config.GuildConfigService.BasePath, gcsc.DefaultSchemes)
-	client := gcsc.New(transport, strfmt.Default)
+	client := gcsc.New(timedTransport{transport}, strfmt.Default)
 	apiKeyAuth := httptransport.APIKeyAuth("Service-Token", "header", serviceToken)
 	// client.Guilds.DeleteGuild(&guilds.DeleteGuildParams{}, apiKeyAuth)
 	// client.Guilds.CreateGuild(&guilds.CreateGuildParams{
@@ -43,5 +45,6 @@ func InitService(ctx context.Context, config *configs.Config, serviceToken strin
 	return &GuildConfigService{
 		Client: client,
 		Auth:   apiKeyAuth,
+		Feeds:  InitFeedCache(ctx, config, ca),
 	}
 }
@@ -0,0 +1,278 @@
+package guildconfigservice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// storeScript caches a fetched feed only if the guild's generation still matches the one read before the fetch,
+// so a fetch that raced an invalidation on any replica never puts the stale feed back
+var storeScript = radix.NewEvalScript(2, `
+if (redis.call("GET", KEYS[1]) or "") ~= ARGV[1] then
+	return 0
+end
+if ARGV[3] == "" then
+	redis.call("SET", KEYS[2], ARGV[2])
+else
+	redis.call("SET", KEYS[2], ARGV[2], "EX", ARGV[3])
+end
+return 1
+`)
+
+// FeedCache is a read-through cache of guild feeds kept in process and in Redis.
+// Feeds are stored encoded so every caller decodes its own copy and can change it freely.
+// Concurrent misses for a guild share one request to the guild config service.
+// Invalidations are published through Redis so every replica drops its copy in process too.
+// The generations of guilds with a fetch in flight are kept only until their fetches finish.
+type FeedCache struct {
+	Cache    *cache.Cache
+	Settings configs.CacheSetting
+	LocalTTL time.Duration
+
+	mu           sync.Mutex
+	local        map[string]cachedFeed
+	generations  map[string]uint64
+	fetching     map[string]int
+	group        singleflight.Group
+	subscription *cache.Subscription
+}
+
+// cachedFeed struct
+type cachedFeed struct {
+	body    []byte
+	expires time.Time
+}
+
+// InitFeedCache returns nil if guild feed caching is disabled.
+// Without a subscription to other replicas' invalidations feeds are only cached in Redis.
+func InitFeedCache(ctx context.Context, config *configs.Config, ca *cache.Cache) *FeedCache {
+	if !config.CacheSettings.GuildFeed.Enabled {
+		return nil
+	}
+
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	fc := &FeedCache{
+		Cache:       ca,
+		Settings:    config.CacheSettings.GuildFeed,
+		LocalTTL:    time.Duration(config.GuildConfigService.FeedLocalTTL) * time.Second,
+		local:       make(map[string]cachedFeed),
+		generations: make(map[string]uint64),
+		fetching:    make(map[string]int),
+	}
+
+	subscription, sErr := ca.Subscribe(ctx, invalidationChannel(fc.Settings.Base), func(message []byte) {
+		fc.dropLocal(string(message))
+	})
+	if sErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", sErr.Err), zap.String("error_message", sErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+
+		fc.LocalTTL = 0
+		return fc
+	}
+
+	fc.subscription = subscription
+
+	return fc
+}
+
+// FeedKey func
+func FeedKey(base string, guildID string) string {
+	return cache.GenerateKey(base, guildID)
+}
+
+// generationKey holds a counter bumped each time the guild's feed is invalidated
+func generationKey(base string, guildID string) string {
+	return cache.GenerateKey(base, guildID+":GENERATION")
+}
+
+// invalidationChannel is the Redis channel guild IDs are published to when their feed changes
+func invalidationChannel(base string) string {
+	return cache.GenerateKey(base, "INVALIDATIONS")
+}
+
+// Close stops receiving other replicas' invalidations
+func (fc *FeedCache) Close(ctx context.Context) error {
+	if fc == nil {
+		return nil
+	}
+
+	return fc.subscription.Close()
+}
+
+// get returns the encoded guild feed from process memory, then Redis, then the guild config service
+func (fc *FeedCache) get(ctx context.Context, gcs *GuildConfigService, guildID string) ([]byte, *Error) {
+	if body, ok := fc.getLocal(guildID); ok {
+		return body, nil
+	}
+
+	val, err, _ := fc.group.Do(guildID, func() (interface{}, error) {
+		generation := fc.beginFetch(guildID)
+		defer fc.endFetch(guildID)
+
+		key := FeedKey(fc.Settings.Base, guildID)
+
+		// Without the shared generation the fetched feed cannot be checked against other replicas' invalidations, so it is not stored in Redis
+		remoteGeneration, rgErr := fc.Cache.Get(ctx, generationKey(fc.Settings.Base, guildID))
+		if rgErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", rgErr.Err), zap.String("error_message", rgErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+		}
+
+		cached, cErr := fc.Cache.Get(ctx, key)
+		if cErr != nil {
+			ctx = logging.AddValues(ctx, zap.NamedError("error", cErr.Err), zap.String("error_message", cErr.Message))
+			logger := logging.Logger(ctx)
+			logger.Error("error_log")
+		} else if cached != "" {
+			fc.setLocal(guildID, []byte(cached), generation)
+			return []byte(cached), nil
+		}
+
+		guildFeed, gfErr := fetchGuildFeed(gcs, guildID)
+		if gfErr != nil {
+			return nil, gfErr
+		}
+
+		body, jsonErr := json.Marshal(guildFeed.Payload)
+		if jsonErr != nil {
+			return nil, &Error{
+				Message: "Failed to encode guild feed",
+				Err:     jsonErr,
+			}
+		}
+
+		// A write made while this fetch was in flight may not be in the response, so it is not stored
+		if fc.setLocal(guildID, body, generation) && rgErr == nil {
+			if err := fc.Cache.Client.Do(storeScript.Cmd(nil, generationKey(fc.Settings.Base, guildID), key, remoteGeneration, string(body), fc.Settings.TTL)); err != nil {
+				ctx = logging.AddValues(ctx, zap.NamedError("error", err), zap.String("error_message", "Failed to cache guild feed"))
+				logger := logging.Logger(ctx)
+				logger.Error("error_log")
+			}
+		}
+
+		return body, nil
+	})
+	if err != nil {
+		if gfErr, ok := err.(*Error); ok {
+			return nil, gfErr
+		}
+
+		return nil, &Error{
+			Message: "Failed to get guild feed",
+			Err:     err,
+		}
+	}
+
+	return val.([]byte), nil
+}
+
+// invalidate drops a guild feed from Redis and from the process memory of every replica
+func (fc *FeedCache) invalidate(ctx context.Context, guildID string) {
+	fc.dropLocal(guildID)
+
+	// Bumped before the Redis copy goes so a fetch still in flight on any replica cannot store its feed afterwards
+	if err := fc.Cache.Client.Do(radix.Cmd(nil, "INCR", generationKey(fc.Settings.Base, guildID))); err != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", err), zap.String("error_message", "Failed to bump guild feed generation"))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+
+	if eErr := cache.ExpireCache(ctx, fc.Cache, fc.Settings, FeedKey(fc.Settings.Base, guildID)); eErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", eErr.Err), zap.String("error_message", eErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+
+	// Published after the Redis copy is gone so other replicas don't reload the stale feed from it
+	if pErr := fc.Cache.Publish(ctx, invalidationChannel(fc.Settings.Base), guildID); pErr != nil {
+		newCtx := logging.AddValues(ctx, zap.NamedError("error", pErr.Err), zap.String("error_message", pErr.Message))
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	}
+}
+
+// dropLocal drops a guild feed from process memory, along with any fetch already in flight for it
+func (fc *FeedCache) dropLocal(guildID string) {
+	fc.mu.Lock()
+	delete(fc.local, guildID)
+	if fc.fetching[guildID] > 0 {
+		fc.generations[guildID]++
+	}
+	fc.mu.Unlock()
+
+	fc.group.Forget(guildID)
+}
+
+// getLocal returns an unexpired feed held in process memory
+func (fc *FeedCache) getLocal(guildID string) ([]byte, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	entry, ok := fc.local[guildID]
+	if ok && time.Now().After(entry.expires) {
+		delete(fc.local, guildID)
+		ok = false
+	}
+
+	metrics.CacheLookup(fc.Settings.Base+"_LOCAL", ok)
+	if !ok {
+		return nil, false
+	}
+
+	return entry.body, true
+}
+
+// setLocal stores a feed in process memory unless the guild was invalidated since generation was read
+func (fc *FeedCache) setLocal(guildID string, body []byte, generation uint64) bool {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if fc.generations[guildID] != generation {
+		return false
+	}
+
+	fc.local[guildID] = cachedFeed{
+		body:    body,
+		expires: time.Now().Add(fc.LocalTTL),
+	}
+
+	return true
+}
+
+// beginFetch records a fetch in flight for the guild and returns the generation it started at
+func (fc *FeedCache) beginFetch(guildID string) uint64 {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.fetching[guildID]++
+
+	return fc.generations[guildID]
+}
+
+// endFetch forgets the guild's generation once none of its fetches are in flight, as no fetch can be compared against it anymore
+func (fc *FeedCache) endFetch(guildID string) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.fetching[guildID]--
+	if fc.fetching[guildID] > 0 {
+		return
+	}
+
+	delete(fc.fetching, guildID)
+	delete(fc.generations, guildID)
+}
@@ -2,14 +2,46 @@ package guildconfigservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"gitlab.com/BIC_Dev/guild-config-service-client/gcsc/guild_feeds"
 )
 
-// GetGuildFeed func
+// GetGuildFeed returns the guild feed, from the feed cache if one is configured
 func GetGuildFeed(ctx context.Context, gcs *GuildConfigService, guildID string) (*guild_feeds.GetGuildFeedByIDOK, *Error) {
+	if gcs.Feeds == nil {
+		return fetchGuildFeed(gcs, guildID)
+	}
+
+	body, gErr := gcs.Feeds.get(ctx, gcs, guildID)
+	if gErr != nil {
+		return nil, gErr
+	}
+
+	guildFeed := guild_feeds.NewGetGuildFeedByIDOK()
+	if jsonErr := json.Unmarshal(body, &guildFeed.Payload); jsonErr != nil {
+		return nil, &Error{
+			Message: "Failed to decode guild feed",
+			Err:     jsonErr,
+		}
+	}
+
+	return guildFeed, nil
+}
+
+// InvalidateGuildFeed drops a cached guild feed after a change made through the bot
+func InvalidateGuildFeed(ctx context.Context, gcs *GuildConfigService, guildID string) {
+	if gcs.Feeds == nil {
+		return
+	}
+
+	gcs.Feeds.invalidate(ctx, guildID)
+}
+
+// fetchGuildFeed requests the guild feed from the guild config service
+func fetchGuildFeed(gcs *GuildConfigService, guildID string) (*guild_feeds.GetGuildFeedByIDOK, *Error) {
 	guildFeedParams := guild_feeds.NewGetGuildFeedByIDParamsWithTimeout(30)
 	guildFeedParams.Guild = guildID
 	guildFeedParams.GuildID = guildID
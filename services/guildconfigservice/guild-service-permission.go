@@ -29,6 +29,8 @@ func CreateGuildServicePermission(ctx context.Context, gcs *GuildConfigService,
 		}
 	}
 
+	InvalidateGuildFeed(ctx, gcs, guildID)
+
 	return outputChannel.Payload, nil
 }
 
@@ -47,5 +49,7 @@ func DeleteGuildServicePermission(ctx context.Context, gcs *GuildConfigService,
 		}
 	}
 
+	InvalidateGuildFeed(ctx, gcs, guildID)
+
 	return outputChannel.Payload, nil
 }
@@ -29,6 +29,8 @@ func CreateServerOutputChannel(ctx context.Context, gcs *GuildConfigService, gui
 		}
 	}
 
+	InvalidateGuildFeed(ctx, gcs, guildID)
+
 	return outputChannel.Payload, nil
 }
 
@@ -73,6 +75,8 @@ func UpdateServerOutputChannel(ctx context.Context, gcs *GuildConfigService, gui
 		}
 	}
 
+	InvalidateGuildFeed(ctx, gcs, guildID)
+
 	return outputChannel.Payload, nil
 }
 
@@ -91,5 +95,7 @@ func DeleteServerOutputChannel(ctx context.Context, gcs *GuildConfigService, gui
 		}
 	}
 
+	InvalidateGuildFeed(ctx, gcs, guildID)
+
 	return outputChannel.Payload, nil
 }
@@ -0,0 +1,22 @@
+package guildconfigservice
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+)
+
+// timedTransport records the latency of every guild config service operation
+type timedTransport struct {
+	runtime.ClientTransport
+}
+
+// Submit func
+func (t timedTransport) Submit(operation *runtime.ClientOperation) (interface{}, error) {
+	start := time.Now()
+	result, err := t.ClientTransport.Submit(operation)
+	metrics.ObserveLatency(metrics.GuildConfigLatency, operation.ID, start, err != nil)
+
+	return result, err
+}
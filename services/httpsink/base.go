@@ -0,0 +1,166 @@
+package httpsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gammazero/workerpool"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// Sink posts game events to the HTTP endpoints guilds have registered
+type Sink struct {
+	Cache        *cache.Cache
+	Settings     configs.CacheSetting
+	Client       *http.Client
+	MaxEndpoints int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	LogSize      int
+	PendingTTL   time.Duration
+
+	pool    *workerpool.WorkerPool
+	mu      sync.RWMutex
+	closed  bool
+	retries map[*time.Timer]retry
+}
+
+// retry is a delivery waiting for its backoff to pass before its next attempt
+type retry struct {
+	ctx      context.Context
+	guildID  string
+	endpoint Endpoint
+	event    models.GameEvent
+	attempt  int
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Endpoint is an HTTP endpoint a guild receives events on
+type Endpoint struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// Wants reports whether the endpoint subscribes to an event type
+func (e Endpoint) Wants(eventType string) bool {
+	for _, val := range e.Events {
+		if val == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is a delivery log entry for one event sent to one endpoint
+type Delivery struct {
+	EndpointID string `json:"endpoint_id"`
+	EventID    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Attempts   int    `json:"attempts"`
+	Error      string `json:"error,omitempty"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// InitSink sets up HTTP event delivery, returning nil when it is disabled
+func InitSink(config *configs.Config, ca *cache.Cache) *Sink {
+	if !config.CacheSettings.HTTPSinks.Enabled {
+		return nil
+	}
+
+	return &Sink{
+		Cache:        ca,
+		Settings:     config.CacheSettings.HTTPSinks,
+		Client:       newClient(config.HTTPSinks.Timeout * time.Second),
+		MaxEndpoints: config.HTTPSinks.MaxEndpoints,
+		MaxAttempts:  config.HTTPSinks.MaxAttempts,
+		BaseBackoff:  config.HTTPSinks.BaseBackoff * time.Second,
+		MaxBackoff:   config.HTTPSinks.MaxBackoff * time.Second,
+		LogSize:      config.HTTPSinks.DeliveryLogSize,
+		// Long enough for every attempt and backoff, after which a delivery lost with its instance may be sent again
+		PendingTTL: time.Duration(config.HTTPSinks.MaxAttempts) * (config.HTTPSinks.Timeout + config.HTTPSinks.MaxBackoff) * time.Second,
+		pool:       workerpool.New(config.HTTPSinks.Workers),
+		retries:    make(map[*time.Timer]retry),
+	}
+}
+
+// Close stops accepting deliveries and waits for the queued ones to finish.
+// Retries still waiting for their backoff are dropped and recorded as failed.
+func (s *Sink) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	var dropped []retry
+	for timer, r := range s.retries {
+		if timer.Stop() {
+			dropped = append(dropped, r)
+		}
+		delete(s.retries, timer)
+	}
+	s.mu.Unlock()
+
+	for _, r := range dropped {
+		s.drop(r.ctx, r.guildID, r.endpoint, r.event, r.attempt)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.pool.StopWait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errClosed is recorded for deliveries dropped because the sink has closed
+var errClosed = errors.New("http sink closed")
+
+// endpointsKey is the hash of a guild's endpoints by ID
+func endpointsKey(base string, guildID string) string {
+	return fmt.Sprintf("%s:endpoints:%s", base, guildID)
+}
+
+// deliveriesKey is the list of a guild's most recent deliveries, newest first
+func deliveriesKey(base string, guildID string) string {
+	return fmt.Sprintf("%s:deliveries:%s", base, guildID)
+}
+
+// seenKey marks an event as being or already delivered to an endpoint so repeated logs aren't sent twice
+func seenKey(base string, guildID string, endpointID string, eventID string) string {
+	return fmt.Sprintf("%s:seen:%s:%s:%s", base, guildID, endpointID, eventID)
+}
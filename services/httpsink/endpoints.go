@@ -0,0 +1,208 @@
+package httpsink
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// AddEndpoint registers a new endpoint for a guild with a freshly generated signing secret
+func (s *Sink) AddEndpoint(ctx context.Context, guildID string, rawURL string, events []string) (*Endpoint, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if vErr := validateURL(rawURL); vErr != nil {
+		return nil, vErr
+	}
+
+	for _, event := range events {
+		if !models.IsGameEventType(event) {
+			return nil, &Error{
+				Message: fmt.Sprintf("Unknown event type: %s. Use %s.", event, strings.Join(models.GameEventTypes, ", ")),
+				Err:     errors.New("invalid event type"),
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		events = models.GameEventTypes
+	}
+
+	var count int
+	if err := s.Cache.Client.Do(radix.Cmd(&count, "HLEN", endpointsKey(s.Settings.Base, guildID))); err != nil {
+		return nil, &Error{
+			Message: "Failed to count HTTP endpoints",
+			Err:     err,
+		}
+	}
+
+	if count >= s.MaxEndpoints {
+		return nil, &Error{
+			Message: fmt.Sprintf("This Discord server already has the maximum of %d HTTP endpoints", s.MaxEndpoints),
+			Err:     errors.New("too many endpoints"),
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, &Error{
+			Message: "Failed to generate signing secret",
+			Err:     err,
+		}
+	}
+
+	endpoint := Endpoint{
+		ID:        strings.Split(uuid.New().String(), "-")[0],
+		URL:       rawURL,
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	value, mErr := json.Marshal(endpoint)
+	if mErr != nil {
+		return nil, &Error{
+			Message: "Failed to marshal HTTP endpoint",
+			Err:     mErr,
+		}
+	}
+
+	if err := s.Cache.Client.Do(radix.Cmd(nil, "HSET", endpointsKey(s.Settings.Base, guildID), endpoint.ID, string(value))); err != nil {
+		return nil, &Error{
+			Message: "Failed to save HTTP endpoint",
+			Err:     err,
+		}
+	}
+
+	return &endpoint, nil
+}
+
+// RemoveEndpoint deletes a guild's endpoint, reporting whether it existed
+func (s *Sink) RemoveEndpoint(ctx context.Context, guildID string, endpointID string) (bool, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var removed int
+	if err := s.Cache.Client.Do(radix.Cmd(&removed, "HDEL", endpointsKey(s.Settings.Base, guildID), endpointID)); err != nil {
+		return false, &Error{
+			Message: "Failed to remove HTTP endpoint",
+			Err:     err,
+		}
+	}
+
+	return removed > 0, nil
+}
+
+// GetEndpoints returns a guild's endpoints, oldest first
+func (s *Sink) GetEndpoints(ctx context.Context, guildID string) ([]Endpoint, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var values map[string]string
+	if err := s.Cache.Client.Do(radix.Cmd(&values, "HGETALL", endpointsKey(s.Settings.Base, guildID))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get HTTP endpoints",
+			Err:     err,
+		}
+	}
+
+	var endpoints []Endpoint
+	for _, value := range values {
+		var endpoint Endpoint
+		if err := json.Unmarshal([]byte(value), &endpoint); err != nil {
+			return nil, &Error{
+				Message: "Failed to unmarshal HTTP endpoint",
+				Err:     err,
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt < endpoints[j].CreatedAt
+	})
+
+	return endpoints, nil
+}
+
+// GetDeliveries returns a guild's most recent deliveries, newest first
+func (s *Sink) GetDeliveries(ctx context.Context, guildID string, limit int) ([]Delivery, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var values []string
+	if err := s.Cache.Client.Do(radix.Cmd(&values, "LRANGE", deliveriesKey(s.Settings.Base, guildID), "0", fmt.Sprint(limit-1))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get HTTP deliveries",
+			Err:     err,
+		}
+	}
+
+	var deliveries []Delivery
+	for _, value := range values {
+		var delivery Delivery
+		if err := json.Unmarshal([]byte(value), &delivery); err != nil {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// recordDelivery adds a delivery to the guild's log and trims it to the configured size
+func (s *Sink) recordDelivery(ctx context.Context, guildID string, delivery Delivery) {
+	value, mErr := json.Marshal(delivery)
+	if mErr != nil {
+		return
+	}
+
+	key := deliveriesKey(s.Settings.Base, guildID)
+	err := s.Cache.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "LPUSH", key, string(value)),
+		radix.Cmd(nil, "LTRIM", key, "0", fmt.Sprint(s.LogSize-1)),
+		radix.Cmd(nil, "EXPIRE", key, s.Settings.TTL),
+	))
+	if err != nil {
+		ctx = logging.AddValues(ctx, zap.NamedError("error", err), zap.String("error_message", "Failed to record HTTP delivery"))
+		logger := logging.Logger(ctx)
+		logger.Error("error_log")
+	}
+}
+
+// validateURL only accepts HTTPS URLs that don't point at an IP in a private range
+func validateURL(rawURL string) *Error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return &Error{
+			Message: "Invalid URL",
+			Err:     errors.New("invalid url"),
+		}
+	}
+
+	if parsed.Scheme != "https" {
+		return &Error{
+			Message: "Endpoint URL must use https",
+			Err:     errors.New("insecure url"),
+		}
+	}
+
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && isBlocked(ip) {
+		return &Error{
+			Message: "Endpoint URL can't point at a private address",
+			Err:     errors.New("private url"),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package httpsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// Request headers sent with every event
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderID        = "X-Webhook-ID"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Publish queues events for every endpoint of the guild that subscribes to them.
+// An event is claimed for an endpoint while it is being delivered and marked as seen once it has been delivered
+// or has failed for good, so logs fetched twice are only sent once. A delivery dropped at shutdown gives up its claim.
+func (s *Sink) Publish(ctx context.Context, guildID string, events []models.GameEvent) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()), zap.String("guild_id", guildID))
+
+	if len(events) == 0 {
+		return
+	}
+
+	endpoints, geErr := s.GetEndpoints(ctx, guildID)
+	if geErr != nil {
+		s.logError(ctx, geErr)
+		return
+	}
+
+	var deliveries []retry
+	for _, event := range events {
+		for _, endpoint := range endpoints {
+			if endpoint.Wants(event.Type) {
+				deliveries = append(deliveries, retry{
+					ctx:      ctx,
+					guildID:  guildID,
+					endpoint: endpoint,
+					event:    event,
+					attempt:  1,
+				})
+			}
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+
+	fresh, fErr := s.claim(deliveries)
+	if fErr != nil {
+		s.logError(ctx, fErr)
+		return
+	}
+
+	for _, d := range fresh {
+		s.submit(d.ctx, d.guildID, d.endpoint, d.event, d.attempt)
+	}
+}
+
+// claim marks deliveries as pending and returns the ones that weren't already pending or seen
+func (s *Sink) claim(deliveries []retry) ([]retry, *Error) {
+	results := make([]radix.MaybeNil, len(deliveries))
+	replies := make([]string, len(deliveries))
+	ttl := strconv.Itoa(int(s.PendingTTL / time.Second))
+
+	var cmds []radix.CmdAction
+	for i, d := range deliveries {
+		results[i] = radix.MaybeNil{Rcv: &replies[i]}
+		cmds = append(cmds, radix.Cmd(&results[i], "SET", seenKey(s.Settings.Base, d.guildID, d.endpoint.ID, d.event.ID), "pending", "EX", ttl, "NX"))
+	}
+
+	if err := s.Cache.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return nil, &Error{
+			Message: "Failed to check for published events",
+			Err:     err,
+		}
+	}
+
+	var fresh []retry
+	for i, d := range deliveries {
+		if !results[i].Nil {
+			fresh = append(fresh, d)
+		}
+	}
+
+	return fresh, nil
+}
+
+// settle marks a delivery as seen once it has succeeded or failed for good, or gives up its claim
+// if it was dropped so the event is sent again the next time it is published
+func (s *Sink) settle(ctx context.Context, guildID string, endpoint Endpoint, event models.GameEvent, seen bool) {
+	key := seenKey(s.Settings.Base, guildID, endpoint.ID, event.ID)
+
+	cmd := radix.Cmd(nil, "DEL", key)
+	if seen {
+		cmd = radix.Cmd(nil, "SET", key, "1", "EX", s.Settings.TTL)
+	}
+
+	if err := s.Cache.Client.Do(cmd); err != nil {
+		s.logError(ctx, &Error{
+			Message: "Failed to settle HTTP delivery",
+			Err:     err,
+		})
+	}
+}
+
+// submit queues one attempt at delivering an event to an endpoint
+func (s *Sink) submit(ctx context.Context, guildID string, endpoint Endpoint, event models.GameEvent, attempt int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		s.drop(ctx, guildID, endpoint, event, attempt)
+		return
+	}
+
+	s.pool.Submit(recovery.Wrap(ctx, "http_sink", func() {
+		s.deliver(ctx, guildID, endpoint, event, attempt)
+	}))
+}
+
+// schedule submits the next attempt once its backoff has passed, unless the sink closes first
+func (s *Sink) schedule(ctx context.Context, guildID string, endpoint Endpoint, event models.GameEvent, attempt int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		s.drop(ctx, guildID, endpoint, event, attempt)
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(s.backoff(attempt-1), func() {
+		s.mu.Lock()
+		delete(s.retries, timer)
+		s.mu.Unlock()
+
+		s.submit(ctx, guildID, endpoint, event, attempt)
+	})
+
+	s.retries[timer] = retry{
+		ctx:      ctx,
+		guildID:  guildID,
+		endpoint: endpoint,
+		event:    event,
+		attempt:  attempt,
+	}
+}
+
+// drop records a delivery that will not be attempted because the sink has closed
+func (s *Sink) drop(ctx context.Context, guildID string, endpoint Endpoint, event models.GameEvent, attempt int) {
+	ctx = logging.AddValues(ctx, zap.String("endpoint_id", endpoint.ID), zap.String("event_id", event.ID), zap.Int("attempt", attempt))
+
+	s.recordDelivery(ctx, guildID, Delivery{
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Success:    false,
+		Attempts:   attempt - 1,
+		Error:      "dropped during shutdown: " + errClosed.Error(),
+		Timestamp:  time.Now().Unix(),
+	})
+	s.settle(ctx, guildID, endpoint, event, false)
+
+	s.logError(ctx, &Error{
+		Message: "Dropped event delivery during shutdown",
+		Err:     errClosed,
+	})
+}
+
+// deliver posts an event and retries with backoff on network errors, 408, 429 and 5xx responses
+func (s *Sink) deliver(ctx context.Context, guildID string, endpoint Endpoint, event models.GameEvent, attempt int) {
+	ctx = logging.AddValues(ctx, zap.String("endpoint_id", endpoint.ID), zap.String("event_id", event.ID), zap.Int("attempt", attempt))
+
+	statusCode, err := s.post(endpoint, event)
+
+	delivery := Delivery{
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Success:    err == nil,
+		StatusCode: statusCode,
+		Attempts:   attempt,
+		Timestamp:  time.Now().Unix(),
+	}
+
+	if err == nil {
+		s.recordDelivery(ctx, guildID, delivery)
+		s.settle(ctx, guildID, endpoint, event, true)
+		return
+	}
+
+	retryable := statusCode == 0 || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500
+	if retryable && attempt < s.MaxAttempts {
+		s.schedule(ctx, guildID, endpoint, event, attempt+1)
+		return
+	}
+
+	delivery.Error = err.Error()
+	s.recordDelivery(ctx, guildID, delivery)
+	s.settle(ctx, guildID, endpoint, event, true)
+
+	s.logError(ctx, &Error{
+		Message: "Failed to deliver event to HTTP endpoint",
+		Err:     err,
+	})
+}
+
+// post sends a signed event and returns the response status
+func (s *Sink) post(endpoint Endpoint, event models.GameEvent) (int, error) {
+	body, mErr := json.Marshal(event)
+	if mErr != nil {
+		return 0, mErr
+	}
+
+	req, rErr := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if rErr != nil {
+		return 0, rErr
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderID, event.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.body" using the endpoint secret.
+// Receivers should recompute it and reject requests with an old timestamp.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay before the next attempt, doubling from the base up to the max
+func (s *Sink) backoff(attempt int) time.Duration {
+	delay := s.BaseBackoff
+	for i := 1; i < attempt && delay < s.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > s.MaxBackoff {
+		return s.MaxBackoff
+	}
+
+	return delay
+}
+
+// logError func
+func (s *Sink) logError(ctx context.Context, err *Error) {
+	ctx = logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", err.Message))
+	logger := logging.Logger(ctx)
+	logger.Error("error_log")
+}
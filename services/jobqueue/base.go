@@ -0,0 +1,104 @@
+package jobqueue
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// replicaID identifies this process to the group leases of every queue it claims from
+var replicaID string = newReplicaID()
+
+// Queue is a Redis work queue shared by every bot instance.
+// A claimed job is hidden from other workers until it is acked or its visibility timeout passes,
+// after which it is queued again so a crashed worker's jobs are picked up by another.
+// Jobs queued with a group are only run by the instance holding the group's lease, which it keeps
+// for a visibility timeout after each job it claims, so one group's jobs never run on two instances at once.
+type Queue struct {
+	Cache       *cache.Cache
+	Settings    configs.CacheSetting
+	Name        string
+	Visibility  time.Duration
+	MaxAttempts int
+	Owner       string
+}
+
+// Job is a claimed job
+type Job struct {
+	ID       string
+	Group    string
+	Payload  string
+	Attempts int
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitQueue returns nil if the runner job queue is disabled
+func InitQueue(config *configs.Config, ca *cache.Cache, name string) *Queue {
+	if !config.CacheSettings.RunnerJobs.Enabled {
+		return nil
+	}
+
+	return &Queue{
+		Cache:       ca,
+		Settings:    config.CacheSettings.RunnerJobs,
+		Name:        name,
+		Visibility:  config.JobQueue.VisibilityTimeout * time.Second,
+		MaxAttempts: config.JobQueue.MaxAttempts,
+		Owner:       replicaID,
+	}
+}
+
+// newReplicaID func
+func newReplicaID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%s", hostname, uuid.New().String())
+}
+
+// jobsKey is the hash of queued and claimed job payloads by ID
+func jobsKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:jobs", base, name)
+}
+
+// groupsKey is the hash of each job's group by ID
+func groupsKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:groups", base, name)
+}
+
+// leaseKey names the instance running a group's jobs. It is shared by every queue so a group is pinned across all of them.
+func leaseKey(base string, group string) string {
+	return fmt.Sprintf("%s:leases:%s", base, group)
+}
+
+// attemptsKey is the hash of how many times each job has been claimed
+func attemptsKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:attempts", base, name)
+}
+
+// readyKey is the list of job IDs waiting for a worker, oldest first
+func readyKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:ready", base, name)
+}
+
+// claimedKey is the sorted set of claimed job IDs scored by when they become visible again
+func claimedKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:claimed", base, name)
+}
+
+// scheduledKey marks a job as queued for one interval so it is queued at most once in it
+func scheduledKey(base string, name string, id string, interval int64) string {
+	return fmt.Sprintf("%s:%s:scheduled:%s:%d", base, name, id, interval)
+}
@@ -0,0 +1,319 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// enqueueScript queues a job unless it is still waiting or was already queued in this interval
+var enqueueScript = radix.NewEvalScript(4, `
+if redis.call("HEXISTS", KEYS[1], ARGV[1]) == 1 then
+	return 0
+end
+if not redis.call("SET", KEYS[3], "1", "NX", "PX", ARGV[3]) then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+if ARGV[4] ~= "" then
+	redis.call("HSET", KEYS[4], ARGV[1], ARGV[4])
+end
+redis.call("RPUSH", KEYS[2], ARGV[1])
+return 1
+`)
+
+// claimScript takes the oldest ready job and hides it until the visibility deadline
+var claimScript = radix.NewEvalScript(5, `
+local id = redis.call("LPOP", KEYS[1])
+if not id then
+	return {}
+end
+redis.call("ZADD", KEYS[2], ARGV[1], id)
+local attempts = redis.call("HINCRBY", KEYS[4], id, 1)
+local payload = redis.call("HGET", KEYS[3], id) or ""
+local group = redis.call("HGET", KEYS[5], id) or ""
+return {id, payload, tostring(attempts), group}
+`)
+
+// leaseScript takes or renews a group's lease unless another instance holds it
+var leaseScript = radix.NewEvalScript(1, `
+local owner = redis.call("GET", KEYS[1])
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// releaseScript hands a claimed job back to the end of the ready list without counting the claim as an attempt
+var releaseScript = radix.NewEvalScript(3, `
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HINCRBY", KEYS[2], ARGV[1], -1)
+redis.call("RPUSH", KEYS[3], ARGV[1])
+return 1
+`)
+
+// extendScript pushes back a claimed job's visibility deadline, unless it has already been made ready again
+var extendScript = radix.NewEvalScript(1, `
+if not redis.call("ZSCORE", KEYS[1], ARGV[1]) then
+	return 0
+end
+redis.call("ZADD", KEYS[1], "XX", ARGV[2], ARGV[1])
+return 1
+`)
+
+// ackScript removes a finished job
+var ackScript = radix.NewEvalScript(4, `
+redis.call("ZREM", KEYS[1], ARGV[1])
+redis.call("HDEL", KEYS[2], ARGV[1])
+redis.call("HDEL", KEYS[3], ARGV[1])
+redis.call("HDEL", KEYS[4], ARGV[1])
+return 1
+`)
+
+// requeueScript makes claimed jobs whose deadline has passed ready again
+var requeueScript = radix.NewEvalScript(2, `
+local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call("ZREM", KEYS[1], id)
+	redis.call("RPUSH", KEYS[2], id)
+end
+return #ids
+`)
+
+// Enqueue queues a job for the current interval. It returns false without queueing the job if
+// the job is still waiting from an earlier interval or has already been queued in this one.
+// Intervals are aligned to the clock so every instance agrees on them.
+// Jobs sharing a non-empty group are run by one instance at a time.
+func (q *Queue) Enqueue(ctx context.Context, id string, group string, payload interface{}, interval time.Duration) (bool, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	value, mErr := json.Marshal(payload)
+	if mErr != nil {
+		return false, &Error{
+			Message: "Failed to marshal job",
+			Err:     mErr,
+		}
+	}
+
+	bucket := time.Now().UnixNano() / int64(interval)
+	scheduled := scheduledKey(q.Settings.Base, q.Name, id, bucket)
+
+	var queued int
+	err := q.Cache.Client.Do(enqueueScript.Cmd(&queued,
+		jobsKey(q.Settings.Base, q.Name),
+		readyKey(q.Settings.Base, q.Name),
+		scheduled,
+		groupsKey(q.Settings.Base, q.Name),
+		id,
+		string(value),
+		strconv.FormatInt((2*interval).Milliseconds(), 10),
+		group,
+	))
+	if err != nil {
+		return false, &Error{
+			Message: "Failed to enqueue job",
+			Err:     err,
+		}
+	}
+
+	return queued == 1, nil
+}
+
+// Claim takes the oldest ready job, or returns nil if there is none.
+// A job whose group is leased to another instance is handed back, and nil is returned so the caller waits before trying again.
+func (q *Queue) Claim(ctx context.Context) (*Job, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	deadline := time.Now().Add(q.Visibility).UnixNano() / int64(time.Millisecond)
+
+	var reply []string
+	err := q.Cache.Client.Do(claimScript.Cmd(&reply,
+		readyKey(q.Settings.Base, q.Name),
+		claimedKey(q.Settings.Base, q.Name),
+		jobsKey(q.Settings.Base, q.Name),
+		attemptsKey(q.Settings.Base, q.Name),
+		groupsKey(q.Settings.Base, q.Name),
+		strconv.FormatInt(deadline, 10),
+	))
+	if err != nil {
+		return nil, &Error{
+			Message: "Failed to claim job",
+			Err:     err,
+		}
+	}
+
+	if len(reply) < 4 {
+		return nil, nil
+	}
+
+	attempts, _ := strconv.Atoi(reply[2])
+
+	job := &Job{
+		ID:       reply[0],
+		Group:    reply[3],
+		Payload:  reply[1],
+		Attempts: attempts,
+	}
+
+	if job.Group == "" {
+		return job, nil
+	}
+
+	leased, lErr := q.lease(job.Group)
+	if lErr != nil {
+		// The job stays claimed and is made ready again once its visibility timeout passes
+		return nil, lErr
+	}
+
+	if leased {
+		return job, nil
+	}
+
+	err = q.Cache.Client.Do(releaseScript.Cmd(nil,
+		claimedKey(q.Settings.Base, q.Name),
+		attemptsKey(q.Settings.Base, q.Name),
+		readyKey(q.Settings.Base, q.Name),
+		job.ID,
+	))
+	if err != nil {
+		return nil, &Error{
+			Message: "Failed to hand back job leased to another instance",
+			Err:     err,
+		}
+	}
+
+	return nil, nil
+}
+
+// Extend keeps a running job hidden and its group leased for another visibility timeout.
+// It returns false if the job's timeout had already passed and it was made ready again.
+func (q *Queue) Extend(ctx context.Context, job *Job) (bool, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	deadline := time.Now().Add(q.Visibility).UnixNano() / int64(time.Millisecond)
+
+	var extended int
+	err := q.Cache.Client.Do(extendScript.Cmd(&extended,
+		claimedKey(q.Settings.Base, q.Name),
+		job.ID,
+		strconv.FormatInt(deadline, 10),
+	))
+	if err != nil {
+		return false, &Error{
+			Message: "Failed to extend job",
+			Err:     err,
+		}
+	}
+
+	if extended == 0 || job.Group == "" {
+		return extended == 1, nil
+	}
+
+	if _, lErr := q.lease(job.Group); lErr != nil {
+		return true, lErr
+	}
+
+	return true, nil
+}
+
+// lease takes or renews a group's lease for this instance, returning false if another instance holds it
+func (q *Queue) lease(group string) (bool, *Error) {
+	var leased int
+	err := q.Cache.Client.Do(leaseScript.Cmd(&leased,
+		leaseKey(q.Settings.Base, group),
+		q.Owner,
+		strconv.FormatInt(q.Visibility.Milliseconds(), 10),
+	))
+	if err != nil {
+		return false, &Error{
+			Message: "Failed to lease job group",
+			Err:     err,
+		}
+	}
+
+	return leased == 1, nil
+}
+
+// Ack removes a job that has finished or will not be tried again
+func (q *Queue) Ack(ctx context.Context, job *Job) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	err := q.Cache.Client.Do(ackScript.Cmd(nil,
+		claimedKey(q.Settings.Base, q.Name),
+		jobsKey(q.Settings.Base, q.Name),
+		attemptsKey(q.Settings.Base, q.Name),
+		groupsKey(q.Settings.Base, q.Name),
+		job.ID,
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to ack job",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Retry keeps a failed job hidden for delay, after which Requeue makes it ready again
+func (q *Queue) Retry(ctx context.Context, job *Job, delay time.Duration) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	visible := time.Now().Add(delay).UnixNano() / int64(time.Millisecond)
+
+	err := q.Cache.Client.Do(radix.Cmd(nil, "ZADD", claimedKey(q.Settings.Base, q.Name), "XX", strconv.FormatInt(visible, 10), job.ID))
+	if err != nil {
+		return &Error{
+			Message: "Failed to schedule job retry",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Requeue makes up to limit claimed jobs ready again once they are visible, returning how many it moved
+func (q *Queue) Requeue(ctx context.Context, limit int) (int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	var moved int
+	err := q.Cache.Client.Do(requeueScript.Cmd(&moved,
+		claimedKey(q.Settings.Base, q.Name),
+		readyKey(q.Settings.Base, q.Name),
+		strconv.FormatInt(now, 10),
+		strconv.Itoa(limit),
+	))
+	if err != nil {
+		return 0, &Error{
+			Message: "Failed to requeue jobs",
+			Err:     err,
+		}
+	}
+
+	return moved, nil
+}
+
+// Depth returns how many jobs are waiting for a worker
+func (q *Queue) Depth(ctx context.Context) (int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var depth int
+	if err := q.Cache.Client.Do(radix.Cmd(&depth, "LLEN", readyKey(q.Settings.Base, q.Name))); err != nil {
+		return 0, &Error{
+			Message: "Failed to get job queue depth",
+			Err:     err,
+		}
+	}
+
+	return depth, nil
+}
@@ -0,0 +1,209 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// acquireScript renews the lease if this replica holds it, or takes it if nobody does
+var acquireScript = radix.NewEvalScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript gives up the lease only if this replica still holds it
+var releaseScript = radix.NewEvalScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Elector holds a lease in Redis so only one replica runs the runners at a time.
+// Every replica keeps trying to take the lease, so a follower takes over within one renew interval of the lease expiring.
+// A leader that cannot renew its lease stops leading once the lease would have expired, even if Redis is unreachable.
+type Elector struct {
+	Cache         *cache.Cache
+	Key           string
+	ID            string
+	LeaseTTL      time.Duration
+	RenewInterval time.Duration
+
+	mu         sync.Mutex
+	leaseUntil time.Time
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitElector returns nil if leader election is disabled, in which case this replica always leads.
+// Replicas only compete with others using the same key.
+func InitElector(config *configs.Config, ca *cache.Cache, key string) *Elector {
+	if !config.LeaderElection.Enabled {
+		return nil
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &Elector{
+		Cache:         ca,
+		Key:           key,
+		ID:            fmt.Sprintf("%s:%s", hostname, uuid.New().String()),
+		LeaseTTL:      config.LeaderElection.LeaseTTL * time.Second,
+		RenewInterval: config.LeaderElection.RenewInterval * time.Second,
+	}
+}
+
+// Start tries to take or renew the lease straight away and then every renew interval
+func (e *Elector) Start(ctx context.Context) {
+	if e == nil {
+		return
+	}
+
+	ctx = logging.AddValues(ctx,
+		zap.String("scope", logging.GetFuncName()),
+		zap.String("leader_key", e.Key),
+		zap.String("replica_id", e.ID),
+	)
+
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+
+	recovery.Go(ctx, "leader_election", "", func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.RenewInterval)
+		defer ticker.Stop()
+
+		for {
+			e.campaign(ctx)
+
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// IsLeader reports whether this replica holds an unexpired lease
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return time.Now().Before(e.leaseUntil)
+}
+
+// Resign stops campaigning and releases the lease so a follower can take over without waiting for it to expire
+func (e *Elector) Resign(ctx context.Context) error {
+	if e == nil || e.stop == nil {
+		return nil
+	}
+
+	close(e.stop)
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	wasLeader := e.IsLeader()
+
+	e.mu.Lock()
+	e.leaseUntil = time.Time{}
+	e.mu.Unlock()
+	metrics.Leader.Set(0)
+
+	if !wasLeader {
+		return nil
+	}
+
+	var released int
+	if err := e.Cache.Client.Do(releaseScript.Cmd(&released, e.Key, e.ID)); err != nil {
+		return &Error{
+			Message: "Failed to release leader lease",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// campaign takes or renews the lease, logging any change in leadership
+func (e *Elector) campaign(ctx context.Context) {
+	wasLeader := e.IsLeader()
+
+	// The lease is counted from before the request so this replica never believes it leads for longer than Redis does
+	start := time.Now()
+
+	var acquired int
+	err := e.Cache.Client.Do(acquireScript.Cmd(&acquired, e.Key, e.ID, strconv.FormatInt(e.LeaseTTL.Milliseconds(), 10)))
+	if err != nil {
+		newCtx := logging.AddValues(ctx,
+			zap.NamedError("error", err),
+			zap.String("error_message", "Failed to renew leader lease"),
+			zap.Bool("leader", wasLeader),
+		)
+		logger := logging.Logger(newCtx)
+		logger.Error("error_log")
+	} else {
+		e.mu.Lock()
+		if acquired == 1 {
+			e.leaseUntil = start.Add(e.LeaseTTL)
+		} else {
+			e.leaseUntil = time.Time{}
+		}
+		e.mu.Unlock()
+	}
+
+	isLeader := e.IsLeader()
+	if isLeader {
+		metrics.Leader.Set(1)
+	} else {
+		metrics.Leader.Set(0)
+	}
+
+	if isLeader == wasLeader {
+		return
+	}
+
+	logger := logging.Logger(ctx)
+	if isLeader {
+		logger.Info("Elected leader")
+	} else {
+		logger.Info("Lost leadership")
+	}
+}
@@ -0,0 +1,79 @@
+package livefeed
+
+import (
+	"sync"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// recentEventCount is how many event IDs are remembered per guild to skip logs fetched twice
+const recentEventCount = 2048
+
+// Hub fans game events out to the live feed connections of each guild.
+// Guilds without connections cost nothing; events for them are dropped straight away.
+// With fan out enabled events are published through Redis and every replica's hub delivers them,
+// since the replica that polled a server is rarely the one holding the guild's connections.
+type Hub struct {
+	BufferSize     int
+	MaxSubscribers int
+	Cache          *cache.Cache
+	Settings       configs.CacheSetting
+
+	mu           sync.Mutex
+	guilds       map[string]*guildFeed
+	subscription *cache.Subscription
+	closed       bool
+}
+
+// guildFeed holds a guild's connections and the events recently sent to them
+type guildFeed struct {
+	subscribers map[*Subscriber]bool
+	seen        map[string]bool
+	recent      []string
+	next        int
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitHub sets up the live feed, returning nil when it is disabled
+func InitHub(config *configs.Config, ca *cache.Cache) *Hub {
+	if !config.LiveFeed.Enabled {
+		return nil
+	}
+
+	return &Hub{
+		BufferSize:     config.LiveFeed.BufferSize,
+		MaxSubscribers: config.LiveFeed.MaxSubscribers,
+		Cache:          ca,
+		Settings:       config.CacheSettings.LiveFeed,
+		guilds:         make(map[string]*guildFeed),
+	}
+}
+
+// markSeen records an event ID and reports whether it was already sent
+func (gf *guildFeed) markSeen(id string) bool {
+	if gf.seen[id] {
+		return true
+	}
+
+	if len(gf.recent) < recentEventCount {
+		gf.recent = append(gf.recent, id)
+	} else {
+		delete(gf.seen, gf.recent[gf.next])
+		gf.recent[gf.next] = id
+		gf.next = (gf.next + 1) % recentEventCount
+	}
+	gf.seen[id] = true
+
+	return false
+}
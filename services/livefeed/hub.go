@@ -0,0 +1,265 @@
+package livefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Subscriber is a single live feed connection.
+// Messages is closed when the subscriber is removed, either by Unsubscribe or because it fell too far behind.
+type Subscriber struct {
+	GuildID  string
+	Messages chan []byte
+
+	servers   map[int64]bool
+	events    map[string]bool
+	overflow  bool
+	goingAway bool
+}
+
+// Subscribe adds a connection for a guild. Empty server or event filters match everything.
+func (h *Hub) Subscribe(guildID string, servers []int64, events []string) (*Subscriber, *Error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, &Error{
+			Message: "Live feed is shutting down",
+			Err:     errors.New("live feed closed"),
+		}
+	}
+
+	gf, ok := h.guilds[guildID]
+	if !ok {
+		gf = &guildFeed{
+			subscribers: make(map[*Subscriber]bool),
+			seen:        make(map[string]bool),
+		}
+		h.guilds[guildID] = gf
+	}
+
+	if len(gf.subscribers) >= h.MaxSubscribers {
+		if len(gf.subscribers) == 0 {
+			delete(h.guilds, guildID)
+		}
+
+		return nil, &Error{
+			Message: fmt.Sprintf("Guild already has the maximum of %d live feed connections", h.MaxSubscribers),
+			Err:     errors.New("too many subscribers"),
+		}
+	}
+
+	sub := &Subscriber{
+		GuildID:  guildID,
+		Messages: make(chan []byte, h.BufferSize),
+		servers:  make(map[int64]bool),
+		events:   make(map[string]bool),
+	}
+	for _, serverID := range servers {
+		sub.servers[serverID] = true
+	}
+	for _, event := range events {
+		sub.events[event] = true
+	}
+
+	gf.subscribers[sub] = true
+
+	return sub, nil
+}
+
+// Unsubscribe removes a connection and closes its messages
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// fanOut is the message published through Redis for each guild's events
+type fanOut struct {
+	GuildID string             `json:"guild_id"`
+	Events  []models.GameEvent `json:"events"`
+}
+
+// Start subscribes the hub to the events published by every replica. It does nothing unless fan out is enabled.
+func (h *Hub) Start(ctx context.Context) *Error {
+	if h == nil || !h.Settings.Enabled {
+		return nil
+	}
+
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	subscription, sErr := h.Cache.Subscribe(ctx, h.Settings.Base, func(message []byte) {
+		var fo fanOut
+		if uErr := json.Unmarshal(message, &fo); uErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", uErr), zap.String("error_message", "Failed to decode live feed events"))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+			return
+		}
+
+		if dErr := h.deliver(fo.GuildID, fo.Events); dErr != nil {
+			newCtx := logging.AddValues(ctx, zap.NamedError("error", dErr.Err), zap.String("error_message", dErr.Message))
+			logger := logging.Logger(newCtx)
+			logger.Error("error_log")
+		}
+	})
+	if sErr != nil {
+		return &Error{
+			Message: sErr.Message,
+			Err:     sErr.Err,
+		}
+	}
+
+	h.mu.Lock()
+	h.subscription = subscription
+	h.mu.Unlock()
+
+	return nil
+}
+
+// Close stops receiving events from other replicas and ends every connection,
+// which the HTTP server's shutdown leaves open since they have been hijacked
+func (h *Hub) Close(ctx context.Context) error {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	subscription := h.subscription
+	h.subscription = nil
+	h.closed = true
+	for _, gf := range h.guilds {
+		for sub := range gf.subscribers {
+			sub.goingAway = true
+			h.remove(sub)
+		}
+	}
+	h.mu.Unlock()
+
+	return subscription.Close()
+}
+
+// Publish sends events to every matching connection of the guild on every replica.
+// Without fan out, or if Redis can't be reached, only this replica's connections get them.
+func (h *Hub) Publish(ctx context.Context, guildID string, events []models.GameEvent) *Error {
+	h.mu.Lock()
+	fanningOut := h.subscription != nil
+	h.mu.Unlock()
+
+	if !fanningOut {
+		return h.deliver(guildID, events)
+	}
+
+	message, mErr := json.Marshal(fanOut{
+		GuildID: guildID,
+		Events:  events,
+	})
+	if mErr != nil {
+		return &Error{
+			Message: "Failed to marshal live feed events",
+			Err:     mErr,
+		}
+	}
+
+	// This replica's own subscription delivers the events to its connections
+	if pErr := h.Cache.Publish(ctx, h.Settings.Base, string(message)); pErr != nil {
+		if dErr := h.deliver(guildID, events); dErr != nil {
+			return dErr
+		}
+
+		return &Error{
+			Message: pErr.Message,
+			Err:     pErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// deliver sends events to every matching connection of the guild on this replica, skipping events it has already sent.
+// A connection whose buffer is full is removed instead of blocking the runners.
+func (h *Hub) deliver(guildID string, events []models.GameEvent) *Error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	gf, ok := h.guilds[guildID]
+	if !ok {
+		return nil
+	}
+
+	for _, event := range events {
+		if gf.markSeen(event.ID) {
+			continue
+		}
+
+		message, mErr := json.Marshal(event)
+		if mErr != nil {
+			return &Error{
+				Message: "Failed to marshal live feed event",
+				Err:     mErr,
+			}
+		}
+
+		for sub := range gf.subscribers {
+			if !sub.Wants(event) {
+				continue
+			}
+
+			select {
+			case sub.Messages <- message:
+			default:
+				sub.overflow = true
+				h.remove(sub)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Wants reports whether an event matches the subscriber's filters
+func (sub *Subscriber) Wants(event models.GameEvent) bool {
+	if len(sub.servers) > 0 && !sub.servers[event.ServerID] {
+		return false
+	}
+
+	if len(sub.events) > 0 && !sub.events[event.Type] {
+		return false
+	}
+
+	return true
+}
+
+// Overflowed reports whether the subscriber was removed for falling behind.
+// It is only meaningful once Messages has been closed.
+func (sub *Subscriber) Overflowed() bool {
+	return sub.overflow
+}
+
+// GoingAway reports whether the subscriber was removed because the live feed is shutting down.
+// It is only meaningful once Messages has been closed.
+func (sub *Subscriber) GoingAway() bool {
+	return sub.goingAway
+}
+
+// remove drops a subscriber and its guild once the guild has none left. The caller must hold the lock.
+func (h *Hub) remove(sub *Subscriber) {
+	gf, ok := h.guilds[sub.GuildID]
+	if !ok || !gf.subscribers[sub] {
+		return
+	}
+
+	delete(gf.subscribers, sub)
+	close(sub.Messages)
+
+	if len(gf.subscribers) == 0 {
+		delete(h.guilds, sub.GuildID)
+	}
+}
@@ -0,0 +1,267 @@
+package logarchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// searchChunk is how many entries a text search reads from Redis at a time
+const searchChunk = 500
+
+// pruneScript drops a sorted set's entries older than the cutoff and forgets the set once it is empty,
+// in one step so an entry stored in between is never left out of the index
+var pruneScript = radix.NewEvalScript(2, `
+local removed = redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+if redis.call("ZCARD", KEYS[1]) == 0 then
+	redis.call("SREM", KEYS[2], KEYS[1])
+end
+return removed
+`)
+
+// Query struct
+type Query struct {
+	GuildID string
+	Type    string
+	Player  string
+	Text    string
+	Since   time.Time
+	Limit   int
+	Offset  int
+}
+
+// Store writes all entries from a logs response to the archive.
+// An entry is stored as its JSON encoding, so archiving the same log twice leaves a single copy.
+func (la *LogArchive) Store(ctx context.Context, guildID string, serverID int64, logs nsv2.GetLogsResponse) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var entries []Entry
+	for _, log := range logs.PlayerLogs {
+		entries = append(entries, Entry{
+			Type:      TypeChat,
+			Players:   []string{log.Gamertag},
+			Text:      fmt.Sprintf("%s (%s): %s", log.Gamertag, log.Name, log.Message),
+			Timestamp: log.Timestamp,
+		})
+	}
+
+	for _, log := range logs.AdminLogs {
+		entries = append(entries, Entry{
+			Type:      TypeAdmin,
+			Players:   []string{log.Name},
+			Text:      log.Command,
+			Timestamp: log.Timestamp,
+		})
+	}
+
+	for _, log := range logs.KillLogs {
+		entries = append(entries, Entry{
+			Type:      TypeKills,
+			Players:   []string{log.KilledName, log.KillerName},
+			Text:      fmt.Sprintf("%s (%s) was killed by %s (%s)", log.KilledName, log.KilledTribe, log.KillerName, log.KillerTribe),
+			Timestamp: log.Timestamp,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var cmds []radix.CmdAction
+	keys := make(map[string]bool)
+	for _, entry := range entries {
+		entry.GuildID = guildID
+		entry.ServerID = serverID
+		if entry.Timestamp == 0 {
+			entry.Timestamp = time.Now().Unix()
+		}
+
+		value, mErr := json.Marshal(entry)
+		if mErr != nil {
+			return &Error{
+				Message: "Failed to encode archive entry",
+				Err:     mErr,
+			}
+		}
+
+		entryKeys := []string{entriesKey(la.Settings.Base, entry.Type, guildID)}
+		for _, player := range entry.Players {
+			if strings.TrimSpace(player) != "" {
+				entryKeys = append(entryKeys, playerKey(la.Settings.Base, entry.Type, guildID, player))
+			}
+		}
+
+		score := strconv.FormatInt(entry.Timestamp, 10)
+		for _, key := range entryKeys {
+			cmds = append(cmds, radix.Cmd(nil, "ZADD", key, score, string(value)))
+			keys[key] = true
+		}
+	}
+
+	args := []string{indexKey(la.Settings.Base)}
+	for key := range keys {
+		args = append(args, key)
+	}
+	cmds = append(cmds, radix.Cmd(nil, "SADD", args...))
+
+	if err := la.Cache.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to archive logs",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Search returns archived entries matching the query, newest first, along with the total number of matches
+func (la *LogArchive) Search(ctx context.Context, query Query) ([]Entry, int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	key := entriesKey(la.Settings.Base, query.Type, query.GuildID)
+	if query.Player != "" {
+		key = playerKey(la.Settings.Base, query.Type, query.GuildID, query.Player)
+	}
+
+	min := "-inf"
+	if !query.Since.IsZero() {
+		min = strconv.FormatInt(query.Since.Unix(), 10)
+	}
+
+	if query.Text == "" {
+		return la.searchRange(key, min, query)
+	}
+
+	var results []Entry
+	total := 0
+	text := strings.ToLower(query.Text)
+
+	for start := 0; ; start += searchChunk {
+		var values []string
+		if err := la.Cache.Client.Do(radix.Cmd(&values, "ZREVRANGEBYSCORE", key, "+inf", min, "LIMIT", strconv.Itoa(start), strconv.Itoa(searchChunk))); err != nil {
+			return nil, 0, &Error{
+				Message: "Failed to search log archive",
+				Err:     err,
+			}
+		}
+
+		for _, value := range values {
+			var entry Entry
+			if uErr := json.Unmarshal([]byte(value), &entry); uErr != nil {
+				return nil, 0, &Error{
+					Message: "Failed to decode archive entry",
+					Err:     uErr,
+				}
+			}
+
+			if !strings.Contains(strings.ToLower(entry.Text), text) {
+				continue
+			}
+
+			total++
+			if total <= query.Offset {
+				continue
+			}
+
+			if query.Limit > 0 && len(results) >= query.Limit {
+				continue
+			}
+
+			results = append(results, entry)
+		}
+
+		if len(values) < searchChunk {
+			break
+		}
+	}
+
+	return results, total, nil
+}
+
+// searchRange pages through a sorted set directly when there is no text to filter on
+func (la *LogArchive) searchRange(key string, min string, query Query) ([]Entry, int, *Error) {
+	var total int
+	if err := la.Cache.Client.Do(radix.Cmd(&total, "ZCOUNT", key, min, "+inf")); err != nil {
+		return nil, 0, &Error{
+			Message: "Failed to search log archive",
+			Err:     err,
+		}
+	}
+
+	if total <= query.Offset {
+		return nil, total, nil
+	}
+
+	limit := total - query.Offset
+	if query.Limit > 0 && query.Limit < limit {
+		limit = query.Limit
+	}
+
+	var values []string
+	if err := la.Cache.Client.Do(radix.Cmd(&values, "ZREVRANGEBYSCORE", key, "+inf", min, "LIMIT", strconv.Itoa(query.Offset), strconv.Itoa(limit))); err != nil {
+		return nil, 0, &Error{
+			Message: "Failed to search log archive",
+			Err:     err,
+		}
+	}
+
+	var results []Entry
+	for _, value := range values {
+		var entry Entry
+		if uErr := json.Unmarshal([]byte(value), &entry); uErr != nil {
+			return nil, 0, &Error{
+				Message: "Failed to decode archive entry",
+				Err:     uErr,
+			}
+		}
+
+		results = append(results, entry)
+	}
+
+	return results, total, nil
+}
+
+// Prune deletes entries older than the retention period
+func (la *LogArchive) Prune(ctx context.Context) (int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if la.Retention <= 0 {
+		return 0, nil
+	}
+
+	var keys []string
+	if err := la.Cache.Client.Do(radix.Cmd(&keys, "SMEMBERS", indexKey(la.Settings.Base))); err != nil {
+		return 0, &Error{
+			Message: "Failed to list log archive keys",
+			Err:     err,
+		}
+	}
+
+	cutoff := "(" + strconv.FormatInt(time.Now().Add(-la.Retention).Unix(), 10)
+	deleted := 0
+
+	for _, key := range keys {
+		var removed int
+		if err := la.Cache.Client.Do(pruneScript.Cmd(&removed, key, indexKey(la.Settings.Base), cutoff)); err != nil {
+			return deleted, &Error{
+				Message: "Failed to prune log archive",
+				Err:     err,
+			}
+		}
+
+		// Player sets hold copies of the guild-wide entries, so only the guild-wide sets are counted
+		if !strings.Contains(key, ":player:") {
+			deleted += removed
+		}
+	}
+
+	return deleted, nil
+}
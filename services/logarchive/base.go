@@ -0,0 +1,78 @@
+package logarchive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Log types
+const (
+	TypeChat  = "chat"
+	TypeAdmin = "admin"
+	TypeKills = "kills"
+)
+
+// LogArchive struct
+type LogArchive struct {
+	Cache     *cache.Cache
+	Settings  configs.CacheSetting
+	Retention time.Duration
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Entry struct
+type Entry struct {
+	Type      string   `json:"type"`
+	GuildID   string   `json:"guild_id"`
+	ServerID  int64    `json:"server_id"`
+	Players   []string `json:"players"`
+	Text      string   `json:"text"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// InitArchive sets up the log archive shared by every replica through Redis
+func InitArchive(ctx context.Context, config *configs.Config, ca *cache.Cache) *LogArchive {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !config.LogArchive.Enabled || !config.CacheSettings.LogArchive.Enabled {
+		return nil
+	}
+
+	return &LogArchive{
+		Cache:     ca,
+		Settings:  config.CacheSettings.LogArchive,
+		Retention: config.LogArchive.Retention * time.Hour,
+	}
+}
+
+// entriesKey is a sorted set of a guild's entries of one type, scored by timestamp
+func entriesKey(base string, logType string, guildID string) string {
+	return fmt.Sprintf("%s:%s:%s", base, logType, guildID)
+}
+
+// playerKey is a sorted set of the entries of one type that name a player
+func playerKey(base string, logType string, guildID string, player string) string {
+	return fmt.Sprintf("%s:%s:%s:player:%s", base, logType, guildID, strings.ToLower(player))
+}
+
+// indexKey is a set of every sorted set key the archive has written, walked by Prune
+func indexKey(base string) string {
+	return fmt.Sprintf("%s:keys", base)
+}
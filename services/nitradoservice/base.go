@@ -2,6 +2,7 @@ package nitradoservice
 
 import (
 	"context"
+	"time"
 
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
@@ -9,9 +10,30 @@ import (
 	"go.uber.org/zap"
 )
 
-// NitradoService struct
+// NitradoService wraps the Nitrado Service V2 client with deadlines, retries for reads,
+// a limit on requests in flight per Nitrado token and a circuit breaker per Nitrado token
 type NitradoService struct {
 	Client *nsv2.Client
+
+	Timeout          time.Duration
+	Retries          int
+	RetryDelay       time.Duration
+	MaxInFlight      int
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	accounts *accounts
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
 }
 
 // InitService func
@@ -29,7 +51,17 @@ func InitService(ctx context.Context, config *configs.Config, serviceToken strin
 		logger.Fatal("error_log")
 	}
 
+	// Requests the client can no longer report back still hold their slot until the client gives up on them
+	nsv2.Timeout = time.Duration(config.NitradoService.Timeout)
+
 	return &NitradoService{
-		Client: client,
+		Client:           client,
+		Timeout:          time.Duration(config.NitradoService.Timeout) * time.Second,
+		Retries:          config.NitradoService.Retries,
+		RetryDelay:       time.Duration(config.NitradoService.RetryDelay) * time.Millisecond,
+		MaxInFlight:      config.NitradoService.MaxInFlight,
+		BreakerThreshold: config.NitradoService.BreakerThreshold,
+		BreakerCooldown:  time.Duration(config.NitradoService.BreakerCooldown) * time.Second,
+		accounts:         newAccounts(),
 	}
 }
@@ -0,0 +1,91 @@
+package nitradoservice
+
+import (
+	"sync"
+	"time"
+)
+
+// accounts holds the in flight limit and circuit breaker for each Nitrado token
+type accounts struct {
+	mu      sync.Mutex
+	byToken map[string]*account
+}
+
+// account struct
+type account struct {
+	slots     chan struct{}
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newAccounts func
+func newAccounts() *accounts {
+	return &accounts{
+		byToken: make(map[string]*account),
+	}
+}
+
+// get returns the account for a Nitrado token, creating it if needed
+func (a *accounts) get(token string, maxInFlight int) *account {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	acc, ok := a.byToken[token]
+	if !ok {
+		acc = &account{
+			slots: make(chan struct{}, maxInFlight),
+		}
+		a.byToken[token] = acc
+	}
+
+	return acc
+}
+
+// allow reports whether a request may be sent, and whether it is the probe. Once the cooldown has passed an open
+// breaker lets a single probe through, and stays open for everything else until that probe finishes.
+func (a *accounts) allow(acc *account) (bool, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if acc.openUntil.IsZero() {
+		return true, false
+	}
+
+	if time.Now().Before(acc.openUntil) || acc.probing {
+		return false, false
+	}
+
+	acc.probing = true
+	return true, true
+}
+
+// record closes the breaker after a success and opens it once failures reach the threshold.
+// While the breaker is open only the probe settles it; requests that were sent before it opened don't.
+// An attempt that never got an in flight slot says nothing about Nitrado and only gives up the probe.
+func (a *accounts) record(acc *account, probe bool, result outcome, threshold int, cooldown time.Duration) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if probe {
+		acc.probing = false
+	}
+
+	if result == busy || (!probe && !acc.openUntil.IsZero()) {
+		return false
+	}
+
+	if result == succeeded {
+		acc.failures = 0
+		acc.openUntil = time.Time{}
+		return false
+	}
+
+	acc.failures++
+	if probe || acc.failures >= threshold {
+		acc.openUntil = time.Now().Add(cooldown)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package nitradoservice
+
+import (
+	"context"
+
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+)
+
+// GetLogs gets chat, admin and kill logs for a gameserver
+func (ns *NitradoService) GetLogs(ctx context.Context, token string, serverID string, chatLogs bool, adminLogs bool, killLogs bool, cached bool) (nsv2.GetLogsResponse, *Error) {
+	val, err := ns.request(ctx, "GetLogs", token, retryAnswered, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.GetLogs(token, serverID, chatLogs, adminLogs, killLogs, cached)
+	})
+	if err != nil {
+		return nsv2.GetLogsResponse{}, err
+	}
+
+	return val.(nsv2.GetLogsResponse), nil
+}
+
+// GetPlayers gets the players of a gameserver
+func (ns *NitradoService) GetPlayers(ctx context.Context, token string, serverID string, online bool, cached bool) (nsv2.GetPlayersResponse, *Error) {
+	val, err := ns.request(ctx, "GetPlayers", token, retryTransient, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.GetPlayers(token, serverID, online, cached)
+	})
+	if err != nil {
+		return nsv2.GetPlayersResponse{}, err
+	}
+
+	return val.(nsv2.GetPlayersResponse), nil
+}
+
+// SearchPlayers searches the players of a gameserver by name
+func (ns *NitradoService) SearchPlayers(ctx context.Context, token string, serverID string, playerName string, exactMatch bool, online bool, cached bool) (nsv2.SearchPlayersResponse, *Error) {
+	val, err := ns.request(ctx, "SearchPlayers", token, retryTransient, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.SearchPlayers(token, serverID, playerName, exactMatch, online, cached)
+	})
+	if err != nil {
+		return nsv2.SearchPlayersResponse{}, err
+	}
+
+	return val.(nsv2.SearchPlayersResponse), nil
+}
+
+// GetWhitelist gets the whitelist of a gameserver
+func (ns *NitradoService) GetWhitelist(ctx context.Context, token string, serverID string, cached bool) (nsv2.GetWhitelistResponse, *Error) {
+	val, err := ns.request(ctx, "GetWhitelist", token, retryTransient, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.GetWhitelist(token, serverID, cached)
+	})
+	if err != nil {
+		return nsv2.GetWhitelistResponse{}, err
+	}
+
+	return val.(nsv2.GetWhitelistResponse), nil
+}
+
+// GetBanlist gets the banlist of a gameserver
+func (ns *NitradoService) GetBanlist(ctx context.Context, token string, serverID string, cached bool) (nsv2.GetBanlistResponse, *Error) {
+	val, err := ns.request(ctx, "GetBanlist", token, retryTransient, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.GetBanlist(token, serverID, cached)
+	})
+	if err != nil {
+		return nsv2.GetBanlistResponse{}, err
+	}
+
+	return val.(nsv2.GetBanlistResponse), nil
+}
+
+// BanPlayer bans a player on a gameserver
+func (ns *NitradoService) BanPlayer(ctx context.Context, token string, serverID string, playerID string) (nsv2.BanPlayerResponse, *Error) {
+	val, err := ns.request(ctx, "BanPlayer", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.BanPlayer(token, serverID, playerID)
+	})
+	if err != nil {
+		return nsv2.BanPlayerResponse{}, err
+	}
+
+	return val.(nsv2.BanPlayerResponse), nil
+}
+
+// UnbanPlayer unbans a player on a gameserver
+func (ns *NitradoService) UnbanPlayer(ctx context.Context, token string, serverID string, playerID string) (nsv2.UnbanPlayerResponse, *Error) {
+	val, err := ns.request(ctx, "UnbanPlayer", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.UnbanPlayer(token, serverID, playerID)
+	})
+	if err != nil {
+		return nsv2.UnbanPlayerResponse{}, err
+	}
+
+	return val.(nsv2.UnbanPlayerResponse), nil
+}
+
+// WhitelistPlayer whitelists a player on a gameserver
+func (ns *NitradoService) WhitelistPlayer(ctx context.Context, token string, serverID string, playerID string) (nsv2.WhitelistPlayerResponse, *Error) {
+	val, err := ns.request(ctx, "WhitelistPlayer", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.WhitelistPlayer(token, serverID, playerID)
+	})
+	if err != nil {
+		return nsv2.WhitelistPlayerResponse{}, err
+	}
+
+	return val.(nsv2.WhitelistPlayerResponse), nil
+}
+
+// UnwhitelistPlayer removes a player from the whitelist of a gameserver
+func (ns *NitradoService) UnwhitelistPlayer(ctx context.Context, token string, serverID string, playerID string) (nsv2.UnwhitelistPlayerResponse, *Error) {
+	val, err := ns.request(ctx, "UnwhitelistPlayer", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.UnwhitelistPlayer(token, serverID, playerID)
+	})
+	if err != nil {
+		return nsv2.UnwhitelistPlayerResponse{}, err
+	}
+
+	return val.(nsv2.UnwhitelistPlayerResponse), nil
+}
+
+// StopGameserver stops a gameserver
+func (ns *NitradoService) StopGameserver(ctx context.Context, token string, serverID string, message string, stopMessage string) (nsv2.StopGameserverResponse, *Error) {
+	val, err := ns.request(ctx, "StopGameserver", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.StopGameserver(token, serverID, message, stopMessage)
+	})
+	if err != nil {
+		return nsv2.StopGameserverResponse{}, err
+	}
+
+	return val.(nsv2.StopGameserverResponse), nil
+}
+
+// RestartGameserver restarts a gameserver
+func (ns *NitradoService) RestartGameserver(ctx context.Context, token string, serverID string, message string, restartMessage string) (nsv2.RestartGameserverResponse, *Error) {
+	val, err := ns.request(ctx, "RestartGameserver", token, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.RestartGameserver(token, serverID, message, restartMessage)
+	})
+	if err != nil {
+		return nsv2.RestartGameserverResponse{}, err
+	}
+
+	return val.(nsv2.RestartGameserverResponse), nil
+}
+
+// UpdateNitradoToken adds a Nitrado token and the gameservers it can manage
+func (ns *NitradoService) UpdateNitradoToken(ctx context.Context, body nsv2.UpdateNitradoTokenRequest) (nsv2.UpdateNitradoTokenResponse, *Error) {
+	val, err := ns.request(ctx, "UpdateNitradoToken", body.NitradoToken, noRetry, func() (interface{}, *nsv2.ClientError) {
+		return ns.Client.UpdateNitradoToken(body)
+	})
+	if err != nil {
+		return nsv2.UpdateNitradoTokenResponse{}, err
+	}
+
+	return val.(nsv2.UpdateNitradoTokenResponse), nil
+}
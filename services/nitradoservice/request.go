@@ -0,0 +1,173 @@
+package nitradoservice
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// DegradedMessage is shown to users while the circuit breaker for their Nitrado token is open
+const DegradedMessage = "Nitrado API degraded"
+
+// ErrCircuitOpen is returned without sending a request while the circuit breaker is open
+var ErrCircuitOpen = errors.New("too many recent failures from Nitrado, try again in a few minutes")
+
+// retryPolicy says which failed attempts of a request are sent again
+type retryPolicy int
+
+const (
+	// noRetry sends a request once, for anything that changes a server
+	noRetry retryPolicy = iota
+	// retryTransient sends a read again after any transient failure
+	retryTransient
+	// retryAnswered sends a read again only after Nitrado answered with a transient error. GetLogs uses it since a
+	// request abandoned at its deadline may still be answered upstream, and nothing guarantees the entries it returned
+	// would come back to the next request.
+	retryAnswered
+)
+
+// outcome is how a single attempt ended
+type outcome int
+
+const (
+	// succeeded means Nitrado answered, or answered with an error sending again won't fix
+	succeeded outcome = iota
+	// unavailable means Nitrado answered with a transient error
+	unavailable
+	// abandoned means the deadline passed with the request still in flight
+	abandoned
+	// busy means no in flight slot for the token freed up before the deadline, so nothing was sent
+	busy
+)
+
+// ErrBusy is returned without sending a request when this process already has the most requests in flight for a token
+var ErrBusy = errors.New("too many Nitrado requests in progress")
+
+// request sends fn for a Nitrado token, retrying it with jitter as its policy allows.
+// method names the request in metrics.
+// fn runs on its own goroutine so the caller can stop waiting once the deadline passes.
+// A request that never got an in flight slot is neither retried nor counted against Nitrado by the breaker.
+func (ns *NitradoService) request(ctx context.Context, method string, token string, policy retryPolicy, fn func() (interface{}, *nsv2.ClientError)) (interface{}, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	acc := ns.accounts.get(token, ns.MaxInFlight)
+
+	attempts := 1
+	if policy != noRetry {
+		attempts += ns.Retries
+	}
+
+	var val interface{}
+	var err *Error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := ns.RetryDelay << uint(attempt-1)
+			delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(delay):
+			}
+		}
+
+		allowed, probe := ns.accounts.allow(acc)
+		if !allowed {
+			return nil, &Error{
+				Message: DegradedMessage,
+				Err:     ErrCircuitOpen,
+			}
+		}
+
+		var result outcome
+		val, err, result = ns.attempt(ctx, method, acc, fn)
+
+		if ns.accounts.record(acc, probe, result, ns.BreakerThreshold, ns.BreakerCooldown) {
+			ctx = logging.AddValues(ctx, zap.NamedError("error", err.Err), zap.String("error_message", "Opened Nitrado circuit breaker"))
+			logger := logging.Logger(ctx)
+			logger.Error("error_log")
+		}
+
+		retry := (result == unavailable && policy != noRetry) || (result == abandoned && policy == retryTransient)
+		if !retry {
+			return val, err
+		}
+	}
+
+	return nil, err
+}
+
+// result struct
+type result struct {
+	val interface{}
+	err *nsv2.ClientError
+}
+
+// attempt sends fn once within the timeout and reports how it ended
+func (ns *NitradoService) attempt(ctx context.Context, method string, acc *account, fn func() (interface{}, *nsv2.ClientError)) (interface{}, *Error, outcome) {
+	ctx, cancel := context.WithTimeout(ctx, ns.Timeout)
+	defer cancel()
+
+	select {
+	case acc.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, &Error{
+			Message: "Too many Nitrado requests in progress",
+			Err:     ErrBusy,
+		}, busy
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-acc.slots }()
+		defer recovery.Recover(ctx, "nitrado:"+method, "")
+		start := time.Now()
+		val, cErr := fn()
+		metrics.ObserveLatency(metrics.NitradoLatency, method, start, cErr != nil)
+		done <- result{val, cErr}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err == nil {
+			return res.val, nil, succeeded
+		}
+
+		status := succeeded
+		if isTransient(res.err) {
+			status = unavailable
+		}
+
+		return nil, &Error{
+			Message: res.err.Message(),
+			Err:     res.err,
+		}, status
+	case <-ctx.Done():
+		return nil, &Error{
+			Message: "Nitrado took too long to respond",
+			Err:     ctx.Err(),
+		}, abandoned
+	}
+}
+
+// isTransient reports whether a Nitrado failure came from the connection or the API being unavailable
+func isTransient(cErr *nsv2.ClientError) bool {
+	switch status := cErr.Status(); {
+	case status == http.StatusFailedDependency:
+		return true
+	case status == http.StatusTooManyRequests:
+		return true
+	case status >= http.StatusInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
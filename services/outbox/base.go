@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Message struct
+// EditMessageID edits an existing message instead of sending a new one.
+// Supersede marks messages that only the newest copy of should be kept in a channel's queue.
+// Receipt names a set the message's ID is added to once it is delivered, so whoever queued it can tell it went out.
+// ServerID and ServerName are set for server output, which is posted through the channel's webhook when it has one.
+type Message struct {
+	ID            string                 `json:"id"`
+	GuildID       string                 `json:"guild_id"`
+	ChannelID     string                 `json:"channel_id"`
+	ServerID      uint64                 `json:"server_id,omitempty"`
+	ServerName    string                 `json:"server_name,omitempty"`
+	EditMessageID string                 `json:"edit_message_id,omitempty"`
+	Supersede     string                 `json:"supersede,omitempty"`
+	Receipt       string                 `json:"receipt,omitempty"`
+	Embed         discordgo.MessageEmbed `json:"embed"`
+	Attempts      int                    `json:"attempts"`
+	LastError     string                 `json:"last_error,omitempty"`
+	CreatedAt     int64                  `json:"created_at"`
+	FailedAt      int64                  `json:"failed_at,omitempty"`
+}
+
+// ChannelDepth struct
+type ChannelDepth struct {
+	ChannelID   string
+	Depth       int
+	NextAttempt time.Time
+}
+
+// Backoff returns the delay before the next attempt, doubling from base up to max
+func Backoff(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
+
+// messagesKey is the hash of all queued messages by ID
+func messagesKey(base string) string {
+	return fmt.Sprintf("%s:messages", base)
+}
+
+// scheduleKey is the sorted set of channels scored by their next attempt time
+func scheduleKey(base string) string {
+	return fmt.Sprintf("%s:schedule", base)
+}
+
+// channelKey is the FIFO list of message IDs for a channel
+func channelKey(base string, channelID string) string {
+	return fmt.Sprintf("%s:channel:%s", base, channelID)
+}
+
+// guildChannelsKey is the set of channels with queued messages for a guild
+func guildChannelsKey(base string, guildID string) string {
+	return fmt.Sprintf("%s:guild:%s:channels", base, guildID)
+}
+
+// deadKey is the list of dead-lettered messages for a guild, newest first
+func deadKey(base string, guildID string) string {
+	return fmt.Sprintf("%s:dead:%s", base, guildID)
+}
+
+// receiptKey is the set of delivered message IDs for a receipt
+func receiptKey(base string, receipt string) string {
+	return fmt.Sprintf("%s:receipt:%s", base, receipt)
+}
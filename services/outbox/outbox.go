@@ -0,0 +1,498 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Enqueue adds a message to the end of its channel's queue
+func Enqueue(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, msg Message) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if msg.Supersede != "" {
+		if dErr := Discard(ctx, ca, settings, msg.ChannelID, msg.Supersede); dErr != nil {
+			return dErr
+		}
+	}
+
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	if msg.CreatedAt == 0 {
+		msg.CreatedAt = time.Now().Unix()
+	}
+
+	value, mErr := json.Marshal(msg)
+	if mErr != nil {
+		return &Error{
+			Message: "Failed to marshal outbox message",
+			Err:     mErr,
+		}
+	}
+
+	err := ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "HSET", messagesKey(settings.Base), msg.ID, string(value)),
+		radix.Cmd(nil, "RPUSH", channelKey(settings.Base, msg.ChannelID), msg.ID),
+		radix.Cmd(nil, "SADD", guildChannelsKey(settings.Base, msg.GuildID), msg.ChannelID),
+		radix.Cmd(nil, "ZADD", scheduleKey(settings.Base), "NX", strconv.FormatInt(time.Now().Unix(), 10), msg.ChannelID),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to enqueue outbox message",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Discard removes queued messages in a channel that share a supersede key
+func Discard(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, channelID string, supersede string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	messages, gErr := getChannelMessages(ca, settings, channelID)
+	if gErr != nil {
+		return gErr
+	}
+
+	for _, msg := range messages {
+		if msg.Supersede != supersede {
+			continue
+		}
+
+		if rErr := removeMessage(ca, settings, msg); rErr != nil {
+			return rErr
+		}
+	}
+
+	return nil
+}
+
+// Due returns up to limit channels whose next attempt time has passed
+func Due(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, now time.Time, limit int) ([]string, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var channelIDs []string
+	err := ca.Client.Do(radix.Cmd(&channelIDs, "ZRANGEBYSCORE", scheduleKey(settings.Base), "-inf", strconv.FormatInt(now.Unix(), 10), "LIMIT", "0", strconv.Itoa(limit)))
+	if err != nil {
+		return nil, &Error{
+			Message: "Failed to get due outbox channels",
+			Err:     err,
+		}
+	}
+
+	return channelIDs, nil
+}
+
+// Head returns the oldest queued message for a channel, or nil if the channel's queue is empty
+func Head(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, channelID string) (*Message, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	for {
+		var id string
+		if err := ca.Client.Do(radix.Cmd(&id, "LINDEX", channelKey(settings.Base, channelID), "0")); err != nil {
+			return nil, &Error{
+				Message: "Failed to get outbox channel head",
+				Err:     err,
+			}
+		}
+
+		if id == "" {
+			if err := ca.Client.Do(radix.Cmd(nil, "ZREM", scheduleKey(settings.Base), channelID)); err != nil {
+				return nil, &Error{
+					Message: "Failed to unschedule empty outbox channel",
+					Err:     err,
+				}
+			}
+			return nil, nil
+		}
+
+		var value string
+		if err := ca.Client.Do(radix.Cmd(&value, "HGET", messagesKey(settings.Base), id)); err != nil {
+			return nil, &Error{
+				Message: "Failed to get outbox message",
+				Err:     err,
+			}
+		}
+
+		// The message was removed without its list entry, drop it and look at the next one
+		if value == "" {
+			if err := ca.Client.Do(radix.Cmd(nil, "LREM", channelKey(settings.Base, channelID), "1", id)); err != nil {
+				return nil, &Error{
+					Message: "Failed to remove orphaned outbox entry",
+					Err:     err,
+				}
+			}
+			continue
+		}
+
+		var msg Message
+		if uErr := json.Unmarshal([]byte(value), &msg); uErr != nil {
+			return nil, &Error{
+				Message: "Failed to unmarshal outbox message",
+				Err:     uErr,
+			}
+		}
+
+		return &msg, nil
+	}
+}
+
+// Ack removes a delivered message from its channel's queue, adding it to its receipt first
+func Ack(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, msg Message) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if msg.Receipt != "" {
+		key := receiptKey(settings.Base, msg.Receipt)
+		cmds := []radix.CmdAction{
+			radix.Cmd(nil, "SADD", key, msg.ID),
+		}
+
+		if settings.TTL != "" {
+			cmds = append(cmds, radix.Cmd(nil, "EXPIRE", key, settings.TTL))
+		}
+
+		if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+			return &Error{
+				Message: "Failed to record outbox receipt",
+				Err:     err,
+			}
+		}
+	}
+
+	return removeMessage(ca, settings, msg)
+}
+
+// Receipts returns the IDs of the messages delivered under a receipt that have not been cleared
+func Receipts(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, receipt string) ([]string, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var ids []string
+	if err := ca.Client.Do(radix.Cmd(&ids, "SMEMBERS", receiptKey(settings.Base, receipt))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get outbox receipts",
+			Err:     err,
+		}
+	}
+
+	return ids, nil
+}
+
+// ClearReceipts forgets delivered message IDs once they have been handled
+func ClearReceipts(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, receipt string, ids []string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := ca.Client.Do(radix.Cmd(nil, "SREM", append([]string{receiptKey(settings.Base, receipt)}, ids...)...)); err != nil {
+		return &Error{
+			Message: "Failed to clear outbox receipts",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Queued reports whether a message is still waiting in its channel's queue
+func Queued(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, id string) (bool, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var exists int
+	if err := ca.Client.Do(radix.Cmd(&exists, "HEXISTS", messagesKey(settings.Base), id)); err != nil {
+		return false, &Error{
+			Message: "Failed to check outbox message",
+			Err:     err,
+		}
+	}
+
+	return exists == 1, nil
+}
+
+// Remove takes a queued message out of its channel's queue without delivering it
+func Remove(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, channelID string, id string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	return removeMessage(ca, settings, Message{
+		ID:        id,
+		GuildID:   guildID,
+		ChannelID: channelID,
+	})
+}
+
+// Clear drops every message queued for a channel without delivering them, for a channel that no longer exists
+func Clear(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, channelID string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var ids []string
+	if err := ca.Client.Do(radix.Cmd(&ids, "LRANGE", channelKey(settings.Base, channelID), "0", "-1")); err != nil {
+		return &Error{
+			Message: "Failed to get outbox channel queue",
+			Err:     err,
+		}
+	}
+
+	cmds := []radix.CmdAction{
+		radix.Cmd(nil, "DEL", channelKey(settings.Base, channelID)),
+		radix.Cmd(nil, "ZREM", scheduleKey(settings.Base), channelID),
+		radix.Cmd(nil, "SREM", guildChannelsKey(settings.Base, guildID), channelID),
+	}
+
+	if len(ids) > 0 {
+		cmds = append(cmds, radix.Cmd(nil, "HDEL", append([]string{messagesKey(settings.Base)}, ids...)...))
+	}
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to clear outbox channel",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Retry records a failed attempt and holds the channel's queue until next
+func Retry(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, msg Message, next time.Time) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	value, mErr := json.Marshal(msg)
+	if mErr != nil {
+		return &Error{
+			Message: "Failed to marshal outbox message",
+			Err:     mErr,
+		}
+	}
+
+	err := ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "HSET", messagesKey(settings.Base), msg.ID, string(value)),
+		radix.Cmd(nil, "ZADD", scheduleKey(settings.Base), strconv.FormatInt(next.Unix(), 10), msg.ChannelID),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to reschedule outbox message",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DeadLetter moves a message out of its channel's queue and into the guild's dead letters
+func DeadLetter(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, msg Message, maxDeadLetters int) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	msg.FailedAt = time.Now().Unix()
+	value, mErr := json.Marshal(msg)
+	if mErr != nil {
+		return &Error{
+			Message: "Failed to marshal outbox message",
+			Err:     mErr,
+		}
+	}
+
+	key := deadKey(settings.Base, msg.GuildID)
+	cmds := []radix.CmdAction{
+		radix.Cmd(nil, "LPUSH", key, string(value)),
+		radix.Cmd(nil, "LTRIM", key, "0", strconv.Itoa(maxDeadLetters-1)),
+	}
+
+	if settings.TTL != "" {
+		cmds = append(cmds, radix.Cmd(nil, "EXPIRE", key, settings.TTL))
+	}
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to dead letter outbox message",
+			Err:     err,
+		}
+	}
+
+	return removeMessage(ca, settings, msg)
+}
+
+// GetDepths returns the queue depth of every channel in a guild with queued messages
+func GetDepths(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string) ([]ChannelDepth, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var channelIDs []string
+	if err := ca.Client.Do(radix.Cmd(&channelIDs, "SMEMBERS", guildChannelsKey(settings.Base, guildID))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get outbox channels",
+			Err:     err,
+		}
+	}
+
+	var depths []ChannelDepth
+	for _, channelID := range channelIDs {
+		var depth int
+		var score string
+		err := ca.Client.Do(radix.Pipeline(
+			radix.Cmd(&depth, "LLEN", channelKey(settings.Base, channelID)),
+			radix.Cmd(&score, "ZSCORE", scheduleKey(settings.Base), channelID),
+		))
+		if err != nil {
+			return nil, &Error{
+				Message: "Failed to get outbox channel depth",
+				Err:     err,
+			}
+		}
+
+		if depth == 0 {
+			continue
+		}
+
+		channelDepth := ChannelDepth{
+			ChannelID: channelID,
+			Depth:     depth,
+		}
+
+		if next, pErr := strconv.ParseFloat(score, 64); pErr == nil {
+			channelDepth.NextAttempt = time.Unix(int64(next), 0)
+		}
+
+		depths = append(depths, channelDepth)
+	}
+
+	return depths, nil
+}
+
+// GetDeadLetters returns up to limit of a guild's most recent dead letters
+func GetDeadLetters(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, limit int) ([]Message, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var values []string
+	if err := ca.Client.Do(radix.Cmd(&values, "LRANGE", deadKey(settings.Base, guildID), "0", strconv.Itoa(limit-1))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get outbox dead letters",
+			Err:     err,
+		}
+	}
+
+	var messages []Message
+	for _, value := range values {
+		var msg Message
+		if uErr := json.Unmarshal([]byte(value), &msg); uErr != nil {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// CountDeadLetters returns how many dead letters a guild has
+func CountDeadLetters(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string) (int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var count int
+	if err := ca.Client.Do(radix.Cmd(&count, "LLEN", deadKey(settings.Base, guildID))); err != nil {
+		return 0, &Error{
+			Message: "Failed to count outbox dead letters",
+			Err:     err,
+		}
+	}
+
+	return count, nil
+}
+
+// ClearDeadLetters deletes all of a guild's dead letters
+func ClearDeadLetters(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if err := ca.Client.Do(radix.Cmd(nil, "DEL", deadKey(settings.Base, guildID))); err != nil {
+		return &Error{
+			Message: "Failed to clear outbox dead letters",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// getChannelMessages returns every queued message for a channel in order
+func getChannelMessages(ca *cache.Cache, settings configs.CacheSetting, channelID string) ([]Message, *Error) {
+	var ids []string
+	if err := ca.Client.Do(radix.Cmd(&ids, "LRANGE", channelKey(settings.Base, channelID), "0", "-1")); err != nil {
+		return nil, &Error{
+			Message: "Failed to get outbox channel queue",
+			Err:     err,
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var values []string
+	if err := ca.Client.Do(radix.Cmd(&values, "HMGET", append([]string{messagesKey(settings.Base)}, ids...)...)); err != nil {
+		return nil, &Error{
+			Message: "Failed to get outbox messages",
+			Err:     err,
+		}
+	}
+
+	var messages []Message
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		var msg Message
+		if uErr := json.Unmarshal([]byte(value), &msg); uErr != nil {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// removeMessage deletes a message and unschedules its channel once the queue is empty
+func removeMessage(ca *cache.Cache, settings configs.CacheSetting, msg Message) *Error {
+	var remaining int
+	err := ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "LREM", channelKey(settings.Base, msg.ChannelID), "1", msg.ID),
+		radix.Cmd(nil, "HDEL", messagesKey(settings.Base), msg.ID),
+		radix.Cmd(&remaining, "LLEN", channelKey(settings.Base, msg.ChannelID)),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to remove outbox message",
+			Err:     err,
+		}
+	}
+
+	if remaining > 0 {
+		return nil
+	}
+
+	err = ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "ZREM", scheduleKey(settings.Base), msg.ChannelID),
+		radix.Cmd(nil, "SREM", guildChannelsKey(settings.Base, msg.GuildID), msg.ChannelID),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to unschedule outbox channel",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
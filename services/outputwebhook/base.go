@@ -0,0 +1,28 @@
+package outputwebhook
+
+import "fmt"
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Discord error code for a webhook that no longer exists
+const unknownWebhookCode = 10015
+
+// Discord error codes for a channel the bot cannot list webhooks in
+const (
+	missingAccessCode      = 50001
+	missingPermissionsCode = 50013
+)
+
+// avatarKey is the avatar a server's output is posted with through webhooks
+func avatarKey(base string, serverID uint64) string {
+	return fmt.Sprintf("%s:avatar:%d", base, serverID)
+}
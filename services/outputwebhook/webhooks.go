@@ -0,0 +1,215 @@
+package outputwebhook
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/services/discordapi"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// Get returns the webhook stored for an output channel, or nil if there is none
+func Get(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, channelID string) (*models.OutputWebhook, *Error) {
+	var webhook *models.OutputWebhook
+	cacheKey := webhook.CacheKey(settings.Base, channelID)
+	if gsErr := ca.GetStruct(ctx, cacheKey, &webhook); gsErr != nil {
+		return nil, &Error{
+			Message: gsErr.Message,
+			Err:     gsErr.Err,
+		}
+	}
+
+	return webhook, nil
+}
+
+// Create stores the bot's webhook in an output channel as enabled, replacing any record stored before.
+// Discord keeps the only durable copy of the webhook, so one the bot already made in the channel is reused
+// instead of leaving it behind when the record was lost, and any extra copies are deleted.
+func Create(ctx context.Context, session *discordgo.Session, ca *cache.Cache, settings configs.CacheSetting, guildID string, channelID string, name string) (*models.OutputWebhook, *Error) {
+	existing, fErr := find(session, channelID)
+	if fErr != nil {
+		return nil, fErr
+	}
+
+	if existing == nil {
+		created, cwErr := discordapi.CreateWebhook(session, channelID, name)
+		if cwErr != nil {
+			return nil, &Error{
+				Message: cwErr.Message,
+				Err:     cwErr.Err,
+			}
+		}
+		existing = created
+	}
+
+	webhook := &models.OutputWebhook{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		WebhookID: existing.ID,
+		Token:     existing.Token,
+		Enabled:   true,
+	}
+
+	if sErr := set(ctx, ca, settings, webhook); sErr != nil {
+		return nil, sErr
+	}
+
+	return webhook, nil
+}
+
+// Recover rebuilds the record of an output channel that has none from the webhooks in the channel.
+// The channel is stored as enabled if the bot still has a webhook there and as disabled otherwise,
+// so the channel is only looked up on Discord once.
+func Recover(ctx context.Context, session *discordgo.Session, ca *cache.Cache, settings configs.CacheSetting, guildID string, channelID string) (*models.OutputWebhook, *Error) {
+	existing, fErr := find(session, channelID)
+	if fErr != nil {
+		return nil, fErr
+	}
+
+	webhook := &models.OutputWebhook{
+		GuildID:   guildID,
+		ChannelID: channelID,
+	}
+
+	if existing != nil {
+		webhook.WebhookID = existing.ID
+		webhook.Token = existing.Token
+		webhook.Enabled = true
+	}
+
+	if sErr := set(ctx, ca, settings, webhook); sErr != nil {
+		return nil, sErr
+	}
+
+	return webhook, nil
+}
+
+// find returns the bot's webhook in a channel, deleting any others it made there, or nil if it has none.
+// A channel the bot cannot manage webhooks in has none.
+func find(session *discordgo.Session, channelID string) (*discordgo.Webhook, *Error) {
+	webhooks, gwErr := discordapi.GetChannelWebhooks(session, channelID)
+	if gwErr != nil {
+		if gwErr.Code == missingAccessCode || gwErr.Code == missingPermissionsCode {
+			return nil, nil
+		}
+
+		return nil, &Error{
+			Message: gwErr.Message,
+			Err:     gwErr.Err,
+		}
+	}
+
+	var found *discordgo.Webhook
+	for _, webhook := range webhooks {
+		if webhook.User == nil || webhook.User.ID != session.State.User.ID || webhook.Token == "" {
+			continue
+		}
+
+		if found == nil {
+			found = webhook
+			continue
+		}
+
+		if dwErr := discordapi.DeleteWebhook(session, webhook.ID); dwErr != nil && dwErr.Code != unknownWebhookCode {
+			return nil, &Error{
+				Message: dwErr.Message,
+				Err:     dwErr.Err,
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// Disable deletes an output channel's webhook from Discord and stops it from being re-created
+func Disable(ctx context.Context, session *discordgo.Session, ca *cache.Cache, settings configs.CacheSetting, guildID string, channelID string) *Error {
+	webhook, gErr := Get(ctx, ca, settings, channelID)
+	if gErr != nil {
+		return gErr
+	}
+
+	if webhook != nil && webhook.WebhookID != "" {
+		if dwErr := discordapi.DeleteWebhook(session, webhook.WebhookID); dwErr != nil && dwErr.Code != unknownWebhookCode {
+			return &Error{
+				Message: dwErr.Message,
+				Err:     dwErr.Err,
+			}
+		}
+	}
+
+	return set(ctx, ca, settings, &models.OutputWebhook{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Enabled:   false,
+	})
+}
+
+// Forget deletes the record of an output channel's webhook, for a channel that no longer exists
+func Forget(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, channelID string) *Error {
+	var webhook *models.OutputWebhook
+	if dErr := ca.Delete(ctx, webhook.CacheKey(settings.Base, channelID)); dErr != nil {
+		return &Error{
+			Message: dErr.Message,
+			Err:     dErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// GetAvatar returns the avatar URL set for a server's webhook posts, or an empty string if there is none
+func GetAvatar(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, serverID uint64) (string, *Error) {
+	avatarURL, gErr := ca.Get(ctx, avatarKey(settings.Base, serverID))
+	if gErr != nil {
+		return "", &Error{
+			Message: gErr.Message,
+			Err:     gErr.Err,
+		}
+	}
+
+	return avatarURL, nil
+}
+
+// SetAvatar sets the avatar URL a server's output is posted with through webhooks. An empty URL removes it.
+func SetAvatar(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, serverID uint64, avatarURL string) *Error {
+	key := avatarKey(settings.Base, serverID)
+
+	if avatarURL == "" {
+		if dErr := ca.Delete(ctx, key); dErr != nil {
+			return &Error{
+				Message: dErr.Message,
+				Err:     dErr.Err,
+			}
+		}
+		return nil
+	}
+
+	if sErr := ca.Set(ctx, key, avatarURL, settings.TTL); sErr != nil {
+		return &Error{
+			Message: sErr.Message,
+			Err:     sErr.Err,
+		}
+	}
+
+	return nil
+}
+
+// IsUnknownWebhook reports whether a Discord error means the webhook was deleted
+func IsUnknownWebhook(err *discordapi.Error) bool {
+	return err != nil && err.Code == unknownWebhookCode
+}
+
+// set func
+func set(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, webhook *models.OutputWebhook) *Error {
+	cacheKey := webhook.CacheKey(settings.Base, webhook.ChannelID)
+	if ssErr := ca.SetStruct(ctx, cacheKey, webhook, settings.TTL); ssErr != nil {
+		return &Error{
+			Message: ssErr.Message,
+			Err:     ssErr.Err,
+		}
+	}
+
+	return nil
+}
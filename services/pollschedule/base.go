@@ -0,0 +1,89 @@
+package pollschedule
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+)
+
+// Schedule tracks when each server is next due to be polled by a runner.
+// Servers start spread evenly across the interval and each poll sets the next one an interval later plus jitter,
+// so polls arrive steadily instead of all at once. A server whose polls fail or run slow is polled less often
+// until it recovers, and no Nitrado token has more than MaxPerToken polls running at once.
+// The schedule lives in Redis so a new leader carries on where the last one stopped.
+type Schedule struct {
+	Cache           *cache.Cache
+	Settings        configs.CacheSetting
+	Name            string
+	Interval        time.Duration
+	Jitter          float64
+	MaxBackoff      int
+	SlowAfter       time.Duration
+	InFlightTimeout time.Duration
+	MaxPerToken     int
+	RetryDelay      time.Duration
+}
+
+// State is a server's place in the schedule
+type State struct {
+	ServerID     string
+	NextDue      time.Time
+	Backoff      int
+	InFlight     bool
+	LastStarted  time.Time
+	LastFinished time.Time
+	LastLag      time.Duration
+	LastDuration time.Duration
+	LastFailed   bool
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitSchedule returns nil if the poll schedule is disabled, in which case the runner polls every server on each tick
+func InitSchedule(config *configs.Config, ca *cache.Cache, name string, interval time.Duration) *Schedule {
+	if !config.CacheSettings.PollSchedule.Enabled {
+		return nil
+	}
+
+	return &Schedule{
+		Cache:           ca,
+		Settings:        config.CacheSettings.PollSchedule,
+		Name:            name,
+		Interval:        interval,
+		Jitter:          config.PollSchedule.Jitter,
+		MaxBackoff:      config.PollSchedule.MaxBackoff,
+		SlowAfter:       config.PollSchedule.SlowAfter * time.Second,
+		InFlightTimeout: config.PollSchedule.InFlightTimeout * time.Second,
+		MaxPerToken:     config.PollSchedule.MaxInFlightPerToken,
+		RetryDelay:      config.PollSchedule.Tick * time.Second,
+	}
+}
+
+// dueKey is the sorted set of server IDs scored by when they are next due, in milliseconds
+func dueKey(base string, name string) string {
+	return fmt.Sprintf("%s:%s:due", base, name)
+}
+
+// stateKey is the hash holding a server's backoff and its last poll
+func stateKey(base string, name string, serverID string) string {
+	return fmt.Sprintf("%s:%s:state:%s", base, name, serverID)
+}
+
+// tokenKey counts the polls running for a Nitrado token, which is hashed so it is never stored in Redis
+func tokenKey(base string, name string, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%s:%s:token:%s", base, name, hex.EncodeToString(sum[:8]))
+}
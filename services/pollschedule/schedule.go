@@ -0,0 +1,311 @@
+package pollschedule
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// StartResult is the outcome of trying to start a due server's poll
+type StartResult int
+
+const (
+	// Started means the poll may run and the server's next poll has been scheduled
+	Started StartResult = 1
+	// StillRunning means the server's last poll has not finished, so it is checked again after the retry delay
+	StillRunning StartResult = 0
+	// TokenBusy means the server's Nitrado token already has its most polls running, so it is checked again after the retry delay
+	TokenBusy StartResult = -1
+)
+
+// startScript starts a server's poll unless its last one is still running or its token is busy,
+// scheduling its next poll an interval later, doubled for each level of backoff, plus jitter
+var startScript = radix.NewEvalScript(3, `
+local now = tonumber(ARGV[2])
+local timeout = tonumber(ARGV[6])
+local started = tonumber(redis.call("HGET", KEYS[2], "started_at") or "0")
+if started + timeout > now then
+	redis.call("ZADD", KEYS[1], "XX", now + tonumber(ARGV[8]), ARGV[1])
+	return {0, 0}
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[3], "-inf", now - timeout)
+if redis.call("ZCARD", KEYS[3]) >= tonumber(ARGV[7]) then
+	redis.call("ZADD", KEYS[1], "XX", now + tonumber(ARGV[8]), ARGV[1])
+	return {-1, 0}
+end
+local lag = math.max(now - tonumber(redis.call("ZSCORE", KEYS[1], ARGV[1]) or ARGV[2]), 0)
+local backoff = math.min(tonumber(redis.call("HGET", KEYS[2], "backoff") or "0"), tonumber(ARGV[5]))
+local delay = tonumber(ARGV[3]) * math.pow(2, backoff) + tonumber(ARGV[4])
+redis.call("ZADD", KEYS[3], now, ARGV[1])
+redis.call("PEXPIRE", KEYS[3], timeout)
+redis.call("HSET", KEYS[2], "started_at", now, "lag_ms", lag)
+redis.call("ZADD", KEYS[1], "XX", now + delay, ARGV[1])
+return {1, lag}
+`)
+
+// finishScript records a finished poll, backing the server off one more level if the poll failed or ran slow
+// and clearing its backoff otherwise. A poll of a server removed from the schedule while it ran is ignored.
+var finishScript = radix.NewEvalScript(2, `
+local started = redis.call("HGET", KEYS[1], "started_at")
+if not started then
+	return 0
+end
+local backoff = tonumber(redis.call("HGET", KEYS[1], "backoff") or "0")
+if ARGV[4] == "1" or tonumber(ARGV[3]) > tonumber(ARGV[5]) then
+	backoff = math.min(backoff + 1, tonumber(ARGV[6]))
+else
+	backoff = 0
+end
+redis.call("HDEL", KEYS[1], "started_at")
+redis.call("HSET", KEYS[1], "backoff", backoff, "last_started_at", started, "finished_at", ARGV[2], "duration_ms", ARGV[3], "failed", ARGV[4])
+redis.call("ZREM", KEYS[2], ARGV[1])
+return 1
+`)
+
+// releaseScript ends a started poll that never ran without recording it, so the server's backoff and last poll are kept
+var releaseScript = radix.NewEvalScript(2, `
+redis.call("HDEL", KEYS[1], "started_at")
+redis.call("ZREM", KEYS[2], ARGV[1])
+return 1
+`)
+
+// Sync adds the given servers to the schedule and, if prune is set, removes any others along with their state.
+// A new server is first due at a fixed offset into the interval taken from its ID, so servers are spread evenly
+// and keep their place across restarts.
+func (s *Schedule) Sync(ctx context.Context, serverIDs []string, prune bool) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var scheduled []string
+	if err := s.Cache.Client.Do(radix.Cmd(&scheduled, "ZRANGE", dueKey(s.Settings.Base, s.Name), "0", "-1")); err != nil {
+		return &Error{
+			Message: "Failed to get scheduled servers",
+			Err:     err,
+		}
+	}
+
+	wanted := make(map[string]bool, len(serverIDs))
+	for _, id := range serverIDs {
+		wanted[id] = true
+	}
+
+	existing := make(map[string]bool, len(scheduled))
+	var cmds []radix.CmdAction
+	for _, id := range scheduled {
+		existing[id] = true
+		if prune && !wanted[id] {
+			cmds = append(cmds,
+				radix.Cmd(nil, "ZREM", dueKey(s.Settings.Base, s.Name), id),
+				radix.Cmd(nil, "DEL", stateKey(s.Settings.Base, s.Name, id)),
+			)
+		}
+	}
+
+	now := time.Now()
+	for _, id := range serverIDs {
+		if existing[id] {
+			continue
+		}
+		due := now.Add(s.offset(id))
+		cmds = append(cmds, radix.Cmd(nil, "ZADD", dueKey(s.Settings.Base, s.Name), "NX", strconv.FormatInt(millis(due), 10), id))
+	}
+
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	if err := s.Cache.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to update scheduled servers",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Due returns up to limit servers whose next poll is due, most overdue first
+func (s *Schedule) Due(ctx context.Context, limit int) ([]string, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var due []string
+	err := s.Cache.Client.Do(radix.Cmd(&due, "ZRANGEBYSCORE", dueKey(s.Settings.Base, s.Name),
+		"-inf", strconv.FormatInt(millis(time.Now()), 10),
+		"LIMIT", "0", strconv.Itoa(limit),
+	))
+	if err != nil {
+		return nil, &Error{
+			Message: "Failed to get due servers",
+			Err:     err,
+		}
+	}
+
+	return due, nil
+}
+
+// Start tries to start a due server's poll, returning how late it started if it did.
+// Every started poll must be followed by Finish so its token slot is freed and its backoff updated.
+func (s *Schedule) Start(ctx context.Context, serverID string, token string) (StartResult, time.Duration, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	jitter := time.Duration(rand.Float64() * s.Jitter * float64(s.Interval))
+
+	var reply []int64
+	err := s.Cache.Client.Do(startScript.Cmd(&reply,
+		dueKey(s.Settings.Base, s.Name),
+		stateKey(s.Settings.Base, s.Name, serverID),
+		tokenKey(s.Settings.Base, s.Name, token),
+		serverID,
+		strconv.FormatInt(millis(time.Now()), 10),
+		strconv.FormatInt(s.Interval.Milliseconds(), 10),
+		strconv.FormatInt(jitter.Milliseconds(), 10),
+		strconv.Itoa(s.MaxBackoff),
+		strconv.FormatInt(s.InFlightTimeout.Milliseconds(), 10),
+		strconv.Itoa(s.MaxPerToken),
+		strconv.FormatInt(s.RetryDelay.Milliseconds(), 10),
+	))
+	if err != nil {
+		return StillRunning, 0, &Error{
+			Message: "Failed to start server poll",
+			Err:     err,
+		}
+	}
+
+	if len(reply) < 2 {
+		return StillRunning, 0, nil
+	}
+
+	return StartResult(reply[0]), time.Duration(reply[1]) * time.Millisecond, nil
+}
+
+// Finish records how long a started poll took and whether it failed
+func (s *Schedule) Finish(ctx context.Context, serverID string, token string, duration time.Duration, failed bool) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	failedArg := "0"
+	if failed {
+		failedArg = "1"
+	}
+
+	err := s.Cache.Client.Do(finishScript.Cmd(nil,
+		stateKey(s.Settings.Base, s.Name, serverID),
+		tokenKey(s.Settings.Base, s.Name, token),
+		serverID,
+		strconv.FormatInt(millis(time.Now()), 10),
+		strconv.FormatInt(duration.Milliseconds(), 10),
+		failedArg,
+		strconv.FormatInt(s.SlowAfter.Milliseconds(), 10),
+		strconv.Itoa(s.MaxBackoff),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to finish server poll",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Release frees a started poll's in-flight flag and token slot when the poll was never run, such as when its job was not queued.
+// Unlike Finish it leaves the server's backoff and last poll as they were.
+func (s *Schedule) Release(ctx context.Context, serverID string, token string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	err := s.Cache.Client.Do(releaseScript.Cmd(nil,
+		stateKey(s.Settings.Base, s.Name, serverID),
+		tokenKey(s.Settings.Base, s.Name, token),
+		serverID,
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to release server poll",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// offset spreads servers across the interval by their ID
+func (s *Schedule) offset(serverID string) time.Duration {
+	sum := sha256.Sum256([]byte(serverID))
+	return time.Duration(float64(binary.BigEndian.Uint32(sum[:4])) / (1 << 32) * float64(s.Interval))
+}
+
+// GetStates returns every server in a runner's schedule, soonest due first
+func GetStates(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, name string) ([]State, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var scheduled []string
+	if err := ca.Client.Do(radix.Cmd(&scheduled, "ZRANGE", dueKey(settings.Base, name), "0", "-1", "WITHSCORES")); err != nil {
+		return nil, &Error{
+			Message: "Failed to get scheduled servers",
+			Err:     err,
+		}
+	}
+
+	states := make([]State, 0, len(scheduled)/2)
+	fields := make([]map[string]string, len(scheduled)/2)
+	var cmds []radix.CmdAction
+	for i := 0; i+1 < len(scheduled); i += 2 {
+		due, _ := strconv.ParseFloat(scheduled[i+1], 64)
+		states = append(states, State{
+			ServerID: scheduled[i],
+			NextDue:  time.Unix(0, int64(due)*int64(time.Millisecond)),
+		})
+		cmds = append(cmds, radix.Cmd(&fields[i/2], "HGETALL", stateKey(settings.Base, name, scheduled[i])))
+	}
+
+	if len(cmds) == 0 {
+		return states, nil
+	}
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return nil, &Error{
+			Message: "Failed to get server poll states",
+			Err:     err,
+		}
+	}
+
+	for i := range states {
+		f := fields[i]
+		startedAt := f["started_at"]
+		states[i].InFlight = startedAt != ""
+		if startedAt == "" {
+			startedAt = f["last_started_at"]
+		}
+		states[i].LastStarted = fromMillis(startedAt)
+		states[i].LastFinished = fromMillis(f["finished_at"])
+		states[i].Backoff, _ = strconv.Atoi(f["backoff"])
+		lag, _ := strconv.ParseInt(f["lag_ms"], 10, 64)
+		states[i].LastLag = time.Duration(lag) * time.Millisecond
+		duration, _ := strconv.ParseInt(f["duration_ms"], 10, 64)
+		states[i].LastDuration = time.Duration(duration) * time.Millisecond
+		states[i].LastFailed = f["failed"] == "1"
+	}
+
+	return states, nil
+}
+
+// millis func
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// fromMillis returns the zero time for an empty or invalid value
+func fromMillis(value string) time.Time {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
@@ -0,0 +1,95 @@
+package pvpstats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Entity types
+const (
+	EntityPlayers = "players"
+	EntityTribes  = "tribes"
+)
+
+// Periods
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodAll   = "all"
+)
+
+// ScopeAllServers is used in place of a server ID for guild-wide stats
+const ScopeAllServers int64 = 0
+
+// Stats struct
+type Stats struct {
+	Name       string `json:"name"`
+	Kills      int    `json:"kills"`
+	Deaths     int    `json:"deaths"`
+	TamedKills int    `json:"tamed_kills"`
+	WildDeaths int    `json:"wild_deaths"`
+}
+
+// KD returns the kill/death ratio
+func (s Stats) KD() float64 {
+	if s.Deaths == 0 {
+		return float64(s.Kills)
+	}
+
+	return float64(s.Kills) / float64(s.Deaths)
+}
+
+// IsPeriod checks if a string is a valid period
+func IsPeriod(period string) bool {
+	switch period {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodAll:
+		return true
+	}
+
+	return false
+}
+
+// PeriodKey returns the bucket name for a period containing the given time
+func PeriodKey(period string, t time.Time) string {
+	t = t.UTC()
+
+	switch period {
+	case PeriodDay:
+		return "d" + t.Format("20060102")
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("w%d-%02d", year, week)
+	case PeriodMonth:
+		return "m" + t.Format("200601")
+	}
+
+	return PeriodAll
+}
+
+// memberKey normalizes a player or tribe name for use in keys
+func memberKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// rankingKey func
+func rankingKey(base string, guildID string, serverID int64, periodKey string, entity string) string {
+	return fmt.Sprintf("%s:%s:%d:%s:%s", base, guildID, serverID, periodKey, entity)
+}
+
+// statsKey func
+func statsKey(base string, guildID string, serverID int64, periodKey string, entity string, name string) string {
+	return fmt.Sprintf("%s:%s:%d:%s:%s:%s", base, guildID, serverID, periodKey, entity, memberKey(name))
+}
@@ -0,0 +1,180 @@
+package pvpstats
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	nsv2 "gitlab.com/BIC_Dev/nitrado-service-v2-client"
+	"go.uber.org/zap"
+)
+
+// periods that every kill is recorded into
+var periods []string = []string{
+	PeriodDay,
+	PeriodWeek,
+	PeriodMonth,
+	PeriodAll,
+}
+
+// RecordKills aggregates kill logs into per-player and per-tribe stats for a server and its guild
+func RecordKills(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, killLogs []nsv2.KillLog) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	var cmds []radix.CmdAction
+	for _, kill := range killLogs {
+		timestamp := time.Unix(kill.Timestamp, 0)
+		if kill.Timestamp == 0 {
+			timestamp = time.Now()
+		}
+
+		for _, scope := range []int64{serverID, ScopeAllServers} {
+			for _, period := range periods {
+				periodKey := PeriodKey(period, timestamp)
+				ttl := settings.TTL
+				if period == PeriodAll {
+					ttl = ""
+				}
+
+				cmds = append(cmds, killCmds(settings.Base, guildID, scope, periodKey, ttl, kill)...)
+			}
+		}
+	}
+
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to record PvP stats",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// killCmds builds the Redis commands needed to record a single kill in one bucket
+func killCmds(base string, guildID string, serverID int64, periodKey string, ttl string, kill nsv2.KillLog) []radix.CmdAction {
+	var cmds []radix.CmdAction
+
+	incr := func(entity string, name string, field string, rank bool) {
+		if memberKey(name) == "" {
+			return
+		}
+
+		key := statsKey(base, guildID, serverID, periodKey, entity, name)
+		ranking := rankingKey(base, guildID, serverID, periodKey, entity)
+
+		cmds = append(cmds,
+			radix.Cmd(nil, "HSET", key, "name", name),
+			radix.Cmd(nil, "HINCRBY", key, field, "1"),
+		)
+
+		if ttl != "" {
+			cmds = append(cmds, radix.Cmd(nil, "EXPIRE", key, ttl))
+		}
+
+		if !rank {
+			return
+		}
+
+		cmds = append(cmds, radix.Cmd(nil, "ZINCRBY", ranking, "1", memberKey(name)))
+		if ttl != "" {
+			cmds = append(cmds, radix.Cmd(nil, "EXPIRE", ranking, ttl))
+		}
+	}
+
+	if kill.PvEKill {
+		if kill.KilledDinoType == "" {
+			incr(EntityPlayers, kill.KilledName, "wild_deaths", false)
+		}
+		incr(EntityTribes, kill.KilledTribe, "wild_deaths", false)
+		return cmds
+	}
+
+	if kill.KillerDinoType != "" {
+		incr(EntityTribes, kill.KillerTribe, "tamed_kills", false)
+	} else {
+		incr(EntityPlayers, kill.KillerName, "kills", true)
+		incr(EntityTribes, kill.KillerTribe, "kills", true)
+	}
+
+	if kill.KilledDinoType == "" {
+		incr(EntityPlayers, kill.KilledName, "deaths", false)
+		incr(EntityTribes, kill.KilledTribe, "deaths", false)
+	}
+
+	return cmds
+}
+
+// GetLeaderboard returns the top players or tribes by kills for a server (or ScopeAllServers) and period
+func GetLeaderboard(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, entity string, periodKey string, limit int) ([]Stats, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var members []string
+	ranking := rankingKey(settings.Base, guildID, serverID, periodKey, entity)
+	if err := ca.Client.Do(radix.Cmd(&members, "ZREVRANGE", ranking, "0", strconv.Itoa(limit-1))); err != nil {
+		return nil, &Error{
+			Message: "Failed to get leaderboard",
+			Err:     err,
+		}
+	}
+
+	var stats []Stats
+	for _, member := range members {
+		aStats, gsErr := getStats(ca, statsKey(settings.Base, guildID, serverID, periodKey, entity, member))
+		if gsErr != nil {
+			return nil, gsErr
+		}
+
+		if aStats == nil {
+			continue
+		}
+
+		stats = append(stats, *aStats)
+	}
+
+	return stats, nil
+}
+
+// GetStats returns stats for a single player or tribe, or nil if none have been recorded
+func GetStats(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, entity string, periodKey string, name string) (*Stats, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	return getStats(ca, statsKey(settings.Base, guildID, serverID, periodKey, entity, name))
+}
+
+// getStats func
+func getStats(ca *cache.Cache, key string) (*Stats, *Error) {
+	var values map[string]string
+	if err := ca.Client.Do(radix.Cmd(&values, "HGETALL", key)); err != nil {
+		return nil, &Error{
+			Message: "Failed to get PvP stats",
+			Err:     err,
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	stats := Stats{
+		Name: values["name"],
+	}
+	stats.Kills, _ = strconv.Atoi(values["kills"])
+	stats.Deaths, _ = strconv.Atoi(values["deaths"])
+	stats.TamedKills, _ = strconv.Atoi(values["tamed_kills"])
+	stats.WildDeaths, _ = strconv.Atoi(values["wild_deaths"])
+
+	return &stats, nil
+}
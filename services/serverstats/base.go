@@ -0,0 +1,74 @@
+package serverstats
+
+import (
+	"fmt"
+	"time"
+)
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Moderation actions
+const (
+	ActionBan         = "bans"
+	ActionUnban       = "unbans"
+	ActionWhitelist   = "whitelists"
+	ActionUnwhitelist = "unwhitelists"
+)
+
+// Summary struct
+type Summary struct {
+	Polls         int
+	OnlinePolls   int
+	PeakPlayers   int
+	TotalPlayers  int
+	UniquePlayers int
+	ChatMessages  int
+	AdminCount    int
+	Kills         int
+	Bans          int
+	Unbans        int
+	Whitelists    int
+	Unwhitelists  int
+}
+
+// Uptime returns the percent of player polls where the server responded
+func (s Summary) Uptime() float64 {
+	if s.Polls == 0 {
+		return 0
+	}
+
+	return float64(s.OnlinePolls) / float64(s.Polls) * 100
+}
+
+// AveragePlayers returns the average online player count across successful polls
+func (s Summary) AveragePlayers() float64 {
+	if s.OnlinePolls == 0 {
+		return 0
+	}
+
+	return float64(s.TotalPlayers) / float64(s.OnlinePolls)
+}
+
+// hourKey returns the hourly bucket for a time
+func hourKey(t time.Time) string {
+	return t.UTC().Format("2006010215")
+}
+
+// bucketKey func
+func bucketKey(base string, guildID string, serverID int64, hour string) string {
+	return fmt.Sprintf("%s:%s:%d:%s", base, guildID, serverID, hour)
+}
+
+// playersKey func
+func playersKey(base string, guildID string, serverID int64, hour string) string {
+	return bucketKey(base, guildID, serverID, hour) + ":players"
+}
@@ -0,0 +1,181 @@
+package serverstats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// peakScript sets a hash field to a value only if it is greater than the current value
+var peakScript = radix.NewEvalScript(1, `
+local current = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or "0")
+if tonumber(ARGV[2]) > current then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+end
+return 0
+`)
+
+// RecordLogs adds log volume counts to the current hourly bucket
+func RecordLogs(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, chat int, admin int, kills int) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	key := bucketKey(settings.Base, guildID, serverID, hourKey(time.Now()))
+	err := ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "HINCRBY", key, "chat", strconv.Itoa(chat)),
+		radix.Cmd(nil, "HINCRBY", key, "admin", strconv.Itoa(admin)),
+		radix.Cmd(nil, "HINCRBY", key, "kills", strconv.Itoa(kills)),
+		radix.Cmd(nil, "EXPIRE", key, settings.TTL),
+	))
+	if err != nil {
+		return &Error{
+			Message: "Failed to record log stats",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// RecordPlayers records the result of a single online players poll
+func RecordPlayers(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, online bool, players []string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	hour := hourKey(time.Now())
+	key := bucketKey(settings.Base, guildID, serverID, hour)
+
+	cmds := []radix.CmdAction{
+		radix.Cmd(nil, "HINCRBY", key, "polls", "1"),
+	}
+
+	if online {
+		cmds = append(cmds,
+			radix.Cmd(nil, "HINCRBY", key, "online_polls", "1"),
+			radix.Cmd(nil, "HINCRBY", key, "total_players", strconv.Itoa(len(players))),
+		)
+
+		if len(players) > 0 {
+			pKey := playersKey(settings.Base, guildID, serverID, hour)
+			args := []string{pKey}
+			for _, player := range players {
+				args = append(args, strings.ToLower(player))
+			}
+			cmds = append(cmds,
+				radix.Cmd(nil, "SADD", args...),
+				radix.Cmd(nil, "EXPIRE", pKey, settings.TTL),
+			)
+		}
+	}
+
+	cmds = append(cmds, radix.Cmd(nil, "EXPIRE", key, settings.TTL))
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to record player stats",
+			Err:     err,
+		}
+	}
+
+	if online {
+		if err := ca.Client.Do(peakScript.Cmd(nil, key, "peak_players", strconv.Itoa(len(players)))); err != nil {
+			return &Error{
+				Message: "Failed to record peak players",
+				Err:     err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordModeration records a ban, unban, whitelist or unwhitelist issued through the bot
+func RecordModeration(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, action string) *Error {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	key := bucketKey(settings.Base, guildID, serverID, hourKey(time.Now()))
+	err := ca.Client.Do(radix.Pipeline(
+		radix.Cmd(nil, "HINCRBY", key, action, "1"),
+		radix.Cmd(nil, "EXPIRE", key, settings.TTL),
+	))
+	if err != nil {
+		return &Error{
+			Message: fmt.Sprintf("Failed to record %s", action),
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// GetSummary aggregates the hourly buckets for the given number of hours ending at end
+func GetSummary(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, guildID string, serverID int64, end time.Time, hours int) (*Summary, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var summary Summary
+	var pKeys []string
+
+	for i := 0; i < hours; i++ {
+		hour := hourKey(end.Add(time.Duration(-i) * time.Hour))
+
+		var values map[string]string
+		if err := ca.Client.Do(radix.Cmd(&values, "HGETALL", bucketKey(settings.Base, guildID, serverID, hour))); err != nil {
+			return nil, &Error{
+				Message: "Failed to get server stats",
+				Err:     err,
+			}
+		}
+
+		summary.Polls += atoi(values["polls"])
+		summary.OnlinePolls += atoi(values["online_polls"])
+		summary.TotalPlayers += atoi(values["total_players"])
+		summary.ChatMessages += atoi(values["chat"])
+		summary.AdminCount += atoi(values["admin"])
+		summary.Kills += atoi(values["kills"])
+		summary.Bans += atoi(values[ActionBan])
+		summary.Unbans += atoi(values[ActionUnban])
+		summary.Whitelists += atoi(values[ActionWhitelist])
+		summary.Unwhitelists += atoi(values[ActionUnwhitelist])
+
+		if peak := atoi(values["peak_players"]); peak > summary.PeakPlayers {
+			summary.PeakPlayers = peak
+		}
+
+		pKeys = append(pKeys, playersKey(settings.Base, guildID, serverID, hour))
+	}
+
+	var uniquePlayers []string
+	if err := ca.Client.Do(radix.Cmd(&uniquePlayers, "SUNION", pKeys...)); err != nil {
+		return nil, &Error{
+			Message: "Failed to get unique players",
+			Err:     err,
+		}
+	}
+	summary.UniquePlayers = len(uniquePlayers)
+
+	return &summary, nil
+}
+
+// atoi func
+func atoi(val string) int {
+	i, _ := strconv.Atoi(val)
+	return i
+}
@@ -0,0 +1,193 @@
+package shards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/cache"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+	"go.uber.org/zap"
+)
+
+// Guild summarizes a guild in a shard's state
+type Guild struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	OwnerID     string `json:"owner_id"`
+	MemberCount int    `json:"member_count"`
+	Shard       int    `json:"shard"`
+}
+
+// LocalGuilds summarizes the guilds in this process's sessions' state
+func (m *Manager) LocalGuilds() ([]Guild, *Error) {
+	var guilds []Guild
+	for _, session := range m.Sessions {
+		shardGuilds, err := sessionGuilds(session)
+		if err != nil {
+			return nil, err
+		}
+		guilds = append(guilds, shardGuilds...)
+	}
+
+	return guilds, nil
+}
+
+// sessionGuilds summarizes the guilds in one session's state
+func sessionGuilds(session *discordgo.Session) ([]Guild, *Error) {
+	if !session.StateEnabled || session.State == nil {
+		return nil, &Error{
+			Message: "Discord state is not enabled",
+			Err:     errors.New("discord state not enabled"),
+		}
+	}
+
+	session.State.RLock()
+	defer session.State.RUnlock()
+
+	guilds := make([]Guild, 0, len(session.State.Guilds))
+	for _, guild := range session.State.Guilds {
+		guilds = append(guilds, Guild{
+			ID:          guild.ID,
+			Name:        guild.Name,
+			OwnerID:     guild.OwnerID,
+			MemberCount: guild.MemberCount,
+			Shard:       session.ShardID,
+		})
+	}
+
+	return guilds, nil
+}
+
+// StartPublishing writes each of this process's shards' guilds to Redis now and then every interval until Close,
+// so any replica can list the guilds of every shard. Nothing is published when this process runs every shard.
+func (m *Manager) StartPublishing(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting, interval time.Duration) {
+	if len(m.Sessions) == m.Count {
+		return
+	}
+
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	recovery.Go(ctx, "shards:publish", "", func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if pErr := m.publish(ca, settings); pErr != nil {
+				newCtx := logging.AddValues(ctx,
+					zap.NamedError("error", pErr.Err),
+					zap.String("error_message", pErr.Message),
+				)
+				logger := logging.Logger(newCtx)
+				logger.Error("error_log")
+			}
+
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// publish writes each shard's guilds under its own key
+func (m *Manager) publish(ca *cache.Cache, settings configs.CacheSetting) *Error {
+	var cmds []radix.CmdAction
+	for _, session := range m.Sessions {
+		guilds, err := sessionGuilds(session)
+		if err != nil {
+			return err
+		}
+
+		value, mErr := json.Marshal(guilds)
+		if mErr != nil {
+			return &Error{
+				Message: "Failed to marshal shard guilds",
+				Err:     mErr,
+			}
+		}
+
+		cmds = append(cmds, radix.Cmd(nil, "SET", guildsKey(settings.Base, session.ShardID, m.Count), string(value), "EX", settings.TTL))
+	}
+
+	if err := ca.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return &Error{
+			Message: "Failed to publish shard guilds",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// AllGuilds returns the guilds of every shard, reading this process's shards from their state and the others from Redis.
+// It also returns the shards whose guilds are unknown because their replica has not published them recently.
+func (m *Manager) AllGuilds(ctx context.Context, ca *cache.Cache, settings configs.CacheSetting) ([]Guild, []int, *Error) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	guilds, err := m.LocalGuilds()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	local := make(map[int]bool, len(m.Sessions))
+	for _, session := range m.Sessions {
+		local[session.ShardID] = true
+	}
+
+	var remote []int
+	var keys []string
+	for shard := 0; shard < m.Count; shard++ {
+		if local[shard] {
+			continue
+		}
+		remote = append(remote, shard)
+		keys = append(keys, guildsKey(settings.Base, shard, m.Count))
+	}
+
+	if len(keys) == 0 {
+		return guilds, nil, nil
+	}
+
+	values := make([]string, len(keys))
+	if err := ca.Client.Do(radix.Cmd(&values, "MGET", keys...)); err != nil {
+		return nil, nil, &Error{
+			Message: "Failed to get shard guilds",
+			Err:     err,
+		}
+	}
+
+	var missing []int
+	for i, value := range values {
+		if value == "" {
+			missing = append(missing, remote[i])
+			continue
+		}
+
+		var shardGuilds []Guild
+		if uErr := json.Unmarshal([]byte(value), &shardGuilds); uErr != nil {
+			missing = append(missing, remote[i])
+			continue
+		}
+		guilds = append(guilds, shardGuilds...)
+	}
+
+	return guilds, missing, nil
+}
+
+// guildsKey holds one shard's guilds. The shard count is part of the key so a resharded bot ignores the old layout.
+func guildsKey(base string, shard int, count int) string {
+	return fmt.Sprintf("%s:%d-of-%d", base, shard, count)
+}
@@ -0,0 +1,210 @@
+package shards
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+)
+
+// Manager runs the Discord gateway sessions of the shards this process owns.
+// It runs either one configured shard, so a sharded deployment runs one replica per shard,
+// or every shard Discord recommends for the bot in auto mode.
+// The first session is also used for REST requests, which are not tied to a shard.
+type Manager struct {
+	Sessions         []*discordgo.Session
+	Count            int
+	IdentifyInterval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Error struct
+type Error struct {
+	Message string `json:"message"`
+	Err     error  `json:"error"`
+}
+
+// Error func
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// InitManager creates a session for each shard this process runs.
+// A shardID or shardCount below zero leaves the configured value in place.
+func InitManager(config *configs.Config, token string, shardID int, shardCount int) (*Manager, *Error) {
+	primary, err := newSession(token)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		IdentifyInterval: config.Sharding.IdentifyInterval * time.Second,
+	}
+
+	if config.Sharding.Auto {
+		gateway, gErr := primary.GatewayBot()
+		if gErr != nil {
+			return nil, &Error{
+				Message: "Failed to get recommended shard count",
+				Err:     gErr,
+			}
+		}
+
+		m.Count = gateway.Shards
+		if m.Count < 1 {
+			m.Count = 1
+		}
+
+		for id := 0; id < m.Count; id++ {
+			session := primary
+			if id > 0 {
+				if session, err = newSession(token); err != nil {
+					return nil, err
+				}
+			}
+			session.ShardID = id
+			session.ShardCount = m.Count
+			m.Sessions = append(m.Sessions, session)
+		}
+
+		return m, nil
+	}
+
+	if shardID < 0 {
+		shardID = config.Sharding.ShardID
+	}
+
+	m.Count = config.Sharding.ShardCount
+	if shardCount >= 0 {
+		m.Count = shardCount
+	}
+	if m.Count < 1 {
+		m.Count = 1
+	}
+
+	if shardID >= m.Count {
+		return nil, &Error{
+			Message: "Invalid shard configuration",
+			Err:     fmt.Errorf("shard ID %d is not below shard count %d", shardID, m.Count),
+		}
+	}
+
+	primary.ShardID = shardID
+	primary.ShardCount = m.Count
+	m.Sessions = []*discordgo.Session{primary}
+
+	return m, nil
+}
+
+// newSession func
+func newSession(token string) (*discordgo.Session, *Error) {
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, &Error{
+			Message: "Failed to create Discord client",
+			Err:     err,
+		}
+	}
+
+	return session, nil
+}
+
+// Open connects each session to the gateway in turn, waiting the identify interval between them
+// since Discord rejects a bot identifying more than once every few seconds
+func (m *Manager) Open() *Error {
+	for i, session := range m.Sessions {
+		if i > 0 {
+			time.Sleep(m.IdentifyInterval)
+		}
+
+		if err := session.Open(); err != nil {
+			return &Error{
+				Message: fmt.Sprintf("Failed to open Discord web socket for shard %d", session.ShardID),
+				Err:     err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close stops publishing guilds and disconnects every session, returning the first error
+func (m *Manager) Close(ctx context.Context) error {
+	if m.stop != nil {
+		close(m.stop)
+		select {
+		case <-m.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var first error
+	for _, session := range m.Sessions {
+		if err := session.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Primary returns the session used for REST requests
+func (m *Manager) Primary() *discordgo.Session {
+	return m.Sessions[0]
+}
+
+// AddHandler adds an event handler to every session
+func (m *Manager) AddHandler(handler interface{}) {
+	for _, session := range m.Sessions {
+		session.AddHandler(handler)
+	}
+}
+
+// ShardFor returns the shard that receives a guild's events
+func (m *Manager) ShardFor(guildID string) int {
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int((id >> 22) % uint64(m.Count))
+}
+
+// Owns reports whether one of this process's sessions receives a guild's events
+func (m *Manager) Owns(guildID string) bool {
+	return m.SessionFor(guildID) != nil
+}
+
+// SessionFor returns the session that receives a guild's events, or nil if another process owns the guild
+func (m *Manager) SessionFor(guildID string) *discordgo.Session {
+	shard := m.ShardFor(guildID)
+	for _, session := range m.Sessions {
+		if session.ShardID == shard {
+			return session
+		}
+	}
+
+	return nil
+}
+
+// Scope qualifies a name shared through Redis with this process's shards, so replicas running other shards keep their own.
+// An unsharded bot's names are left as they are.
+func (m *Manager) Scope(name string) string {
+	if m.Count == 1 {
+		return name
+	}
+
+	ids := make([]string, len(m.Sessions))
+	for i, session := range m.Sessions {
+		ids[i] = strconv.Itoa(session.ShardID)
+	}
+
+	return fmt.Sprintf("%s:shard-%s-of-%d", name, strings.Join(ids, "-"), m.Count)
+}
@@ -0,0 +1,104 @@
+package arkcommands
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+//go:embed blueprints.json
+var blueprintsJSON []byte
+
+// blueprints maps normalized blueprint class names to friendly names
+var blueprints map[string]string
+
+// blueprintPrefixes are stripped from unknown blueprint classes before humanizing
+var blueprintPrefixes []string = []string{
+	"primalitemresource_",
+	"primalitemconsumable_",
+	"primalitemarmor_",
+	"primalitemstructure_",
+	"primalitemammo_",
+	"primalitemcostume_",
+	"primalitemskin_",
+	"primalitemdye_",
+	"primalitem_",
+}
+
+// blueprintSuffixes are stripped from unknown blueprint classes before humanizing
+var blueprintSuffixes []string = []string{
+	"_character_bp",
+	"_bp",
+}
+
+var camelCaseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+func init() {
+	blueprints = make(map[string]string)
+	if err := json.Unmarshal(blueprintsJSON, &blueprints); err != nil {
+		panic("arkcommands: invalid embedded blueprints.json: " + err.Error())
+	}
+}
+
+// FriendlyName converts a blueprint path or class name into a friendly name
+func FriendlyName(blueprint string) string {
+	class := normalizeBlueprint(blueprint)
+	if class == "" {
+		return blueprint
+	}
+
+	if name, ok := blueprints[strings.ToLower(class)]; ok {
+		return name
+	}
+
+	return humanize(class)
+}
+
+// normalizeBlueprint reduces a blueprint path like
+// Blueprint'/Game/PrimalEarth/Dinos/Rex/Rex_Character_BP.Rex_Character_BP'
+// down to its class name, Rex_Character_BP
+func normalizeBlueprint(blueprint string) string {
+	class := strings.TrimSpace(blueprint)
+	class = strings.TrimPrefix(class, "Blueprint'")
+	class = strings.TrimSuffix(class, "'")
+
+	if idx := strings.LastIndex(class, "/"); idx >= 0 {
+		class = class[idx+1:]
+	}
+
+	if idx := strings.Index(class, "."); idx >= 0 {
+		class = class[:idx]
+	}
+
+	if strings.HasSuffix(strings.ToLower(class), "_c") {
+		class = class[:len(class)-2]
+	}
+
+	return class
+}
+
+// humanize turns an unknown class name into something readable
+func humanize(class string) string {
+	lower := strings.ToLower(class)
+
+	for _, prefix := range blueprintPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			class = class[len(prefix):]
+			lower = lower[len(prefix):]
+			break
+		}
+	}
+
+	for _, suffix := range blueprintSuffixes {
+		if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix) {
+			class = class[:len(class)-len(suffix)]
+			break
+		}
+	}
+
+	class = camelCaseBoundary.ReplaceAllString(class, "$1 $2")
+	class = strings.Replace(class, "_", " ", -1)
+
+	return strings.Join(strings.Fields(class), " ")
+}
@@ -0,0 +1,433 @@
+package arkcommands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Command struct
+type Command struct {
+	Raw      string
+	Verb     string
+	Target   string
+	Quantity int
+	Args     []string
+	Known    bool
+}
+
+// verb struct
+type verb struct {
+	name     string
+	describe func(args []string) (target string, quantity int, readable string)
+}
+
+// verbs maps lowercase ARK admin command names to their parsers
+var verbs map[string]verb = map[string]verb{
+	"summon": {
+		name: "Summon",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			return target, 1, fmt.Sprintf("Summoned %s", target)
+		},
+	},
+	"summontamed": {
+		name: "SummonTamed",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			return target, 1, fmt.Sprintf("Summoned tamed %s", target)
+		},
+	},
+	"gmsummon": {
+		name: "GMSummon",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			if level := argInt(args, 1, 0); level > 0 {
+				return target, 1, fmt.Sprintf("Summoned tamed %s (Lvl %d)", target, level)
+			}
+			return target, 1, fmt.Sprintf("Summoned tamed %s", target)
+		},
+	},
+	"spawndino": {
+		name: "SpawnDino",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			if level := argInt(args, 4, 0); level > 0 {
+				return target, 1, fmt.Sprintf("Spawned %s (Lvl %d)", target, level)
+			}
+			return target, 1, fmt.Sprintf("Spawned %s", target)
+		},
+	},
+	"giveitem": {
+		name: "GiveItem",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			quantity := argInt(args, 1, 1)
+			return target, quantity, fmt.Sprintf("Gave %dx %s", quantity, target)
+		},
+	},
+	"gfi": {
+		name: "GFI",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			quantity := argInt(args, 1, 1)
+			return target, quantity, fmt.Sprintf("Gave %dx %s", quantity, target)
+		},
+	},
+	"giveitemnum": {
+		name: "GiveItemNum",
+		describe: func(args []string) (string, int, string) {
+			target := fmt.Sprintf("item #%s", argString(args, 0))
+			quantity := argInt(args, 1, 1)
+			return target, quantity, fmt.Sprintf("Gave %dx %s", quantity, target)
+		},
+	},
+	"giveitemtoplayer": {
+		name: "GiveItemToPlayer",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 1)
+			quantity := argInt(args, 2, 1)
+			return target, quantity, fmt.Sprintf("Gave %dx %s to player %s", quantity, target, argString(args, 0))
+		},
+	},
+	"giveexptoplayer": {
+		name: "GiveExpToPlayer",
+		describe: func(args []string) (string, int, string) {
+			quantity := argInt(args, 1, 0)
+			return argString(args, 0), quantity, fmt.Sprintf("Gave %d XP to player %s", quantity, argString(args, 0))
+		},
+	},
+	"addexperience": {
+		name: "AddExperience",
+		describe: func(args []string) (string, int, string) {
+			quantity := argInt(args, 0, 0)
+			return "", quantity, fmt.Sprintf("Added %d XP", quantity)
+		},
+	},
+	"setplayerpos": {
+		name: "SetPlayerPos",
+		describe: func(args []string) (string, int, string) {
+			target := strings.Join(args, ", ")
+			return target, 0, fmt.Sprintf("Teleported to %s", target)
+		},
+	},
+	"teleportplayeridtome": {
+		name: "TeleportPlayerIDToMe",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Teleported player %s to self", argString(args, 0))
+		},
+	},
+	"teleportplayernametome": {
+		name: "TeleportPlayerNameToMe",
+		describe: func(args []string) (string, int, string) {
+			target := strings.Join(args, " ")
+			return target, 0, fmt.Sprintf("Teleported %s to self", target)
+		},
+	},
+	"teleporttoplayer": {
+		name: "TeleportToPlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Teleported to player %s", argString(args, 0))
+		},
+	},
+	"teleporttoplayername": {
+		name: "TeleportToPlayerName",
+		describe: func(args []string) (string, int, string) {
+			target := strings.Join(args, " ")
+			return target, 0, fmt.Sprintf("Teleported to %s", target)
+		},
+	},
+	"destroywilddinos": {
+		name: "DestroyWildDinos",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Destroyed all wild dinos"
+		},
+	},
+	"destroyall": {
+		name: "DestroyAll",
+		describe: func(args []string) (string, int, string) {
+			target := argFriendlyName(args, 0)
+			return target, 0, fmt.Sprintf("Destroyed all %s", target)
+		},
+	},
+	"destroytribedinos": {
+		name: "DestroyTribeDinos",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Destroyed targeted tribe's dinos"
+		},
+	},
+	"destroytribestructures": {
+		name: "DestroyTribeStructures",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Destroyed targeted tribe's structures"
+		},
+	},
+	"destroymytarget": {
+		name: "DestroyMyTarget",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Destroyed target"
+		},
+	},
+	"destroystructures": {
+		name: "DestroyStructures",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Destroyed all structures"
+		},
+	},
+	"killplayer": {
+		name: "KillPlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Killed player %s", argString(args, 0))
+		},
+	},
+	"forcetame": {
+		name: "ForceTame",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Force tamed targeted dino"
+		},
+	},
+	"dotame": {
+		name: "DoTame",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Tamed targeted dino"
+		},
+	},
+	"god": {
+		name: "God",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Toggled god mode"
+		},
+	},
+	"fly": {
+		name: "Fly",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Enabled fly mode"
+		},
+	},
+	"ghost": {
+		name: "Ghost",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Enabled ghost mode"
+		},
+	},
+	"walk": {
+		name: "Walk",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Disabled fly/ghost mode"
+		},
+	},
+	"infinitestats": {
+		name: "InfiniteStats",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Toggled infinite stats"
+		},
+	},
+	"giveengrams": {
+		name: "GiveEngrams",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Unlocked all engrams"
+		},
+	},
+	"giveresources": {
+		name: "GiveResources",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Gave resources"
+		},
+	},
+	"settimeofday": {
+		name: "SetTimeOfDay",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Set time of day to %s", argString(args, 0))
+		},
+	},
+	"slomo": {
+		name: "Slomo",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Set game speed to %s", argString(args, 0))
+		},
+	},
+	"banplayer": {
+		name: "BanPlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Banned player %s", argString(args, 0))
+		},
+	},
+	"unbanplayer": {
+		name: "UnbanPlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Unbanned player %s", argString(args, 0))
+		},
+	},
+	"kickplayer": {
+		name: "KickPlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Kicked player %s", argString(args, 0))
+		},
+	},
+	"clearplayerinventory": {
+		name: "ClearPlayerInventory",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Cleared inventory of player %s", argString(args, 0))
+		},
+	},
+	"renameplayer": {
+		name: "RenamePlayer",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Renamed player %s to %s", argString(args, 0), argString(args, 1))
+		},
+	},
+	"renametribe": {
+		name: "RenameTribe",
+		describe: func(args []string) (string, int, string) {
+			return argString(args, 0), 0, fmt.Sprintf("Renamed tribe %s to %s", argString(args, 0), argString(args, 1))
+		},
+	},
+	"broadcast": {
+		name: "Broadcast",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Broadcast a message"
+		},
+	},
+	"serverchat": {
+		name: "ServerChat",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Sent a server chat message"
+		},
+	},
+	"saveworld": {
+		name: "SaveWorld",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Saved the world"
+		},
+	},
+	"enemyinvisible": {
+		name: "EnemyInvisible",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Toggled enemy invisibility"
+		},
+	},
+	"leavemealone": {
+		name: "LeaveMeAlone",
+		describe: func(args []string) (string, int, string) {
+			return "", 0, "Enabled god mode, infinite stats and enemy invisibility"
+		},
+	},
+}
+
+// Parse parses a raw ARK admin command string into a Command
+func Parse(raw string) Command {
+	command := Command{
+		Raw: raw,
+	}
+
+	tokens := tokenize(raw)
+	if len(tokens) > 0 {
+		switch strings.ToLower(tokens[0]) {
+		case "admincheat", "cheat":
+			tokens = tokens[1:]
+		}
+	}
+
+	if len(tokens) == 0 {
+		return command
+	}
+
+	command.Verb = tokens[0]
+	command.Args = tokens[1:]
+
+	if v, ok := verbs[strings.ToLower(command.Verb)]; ok {
+		command.Verb = v.name
+		command.Target, command.Quantity, _ = v.describe(command.Args)
+		command.Known = true
+	}
+
+	return command
+}
+
+// Readable returns a human readable description of the command
+func (c Command) Readable() string {
+	if v, ok := verbs[strings.ToLower(c.Verb)]; ok {
+		_, _, readable := v.describe(c.Args)
+		return readable
+	}
+
+	if c.Verb == "" {
+		return "Unknown command"
+	}
+
+	if len(c.Args) == 0 {
+		return c.Verb
+	}
+
+	return fmt.Sprintf("%s %s", c.Verb, strings.Join(c.Args, " "))
+}
+
+// IsFlagged checks whether the command verb is in a list of flagged verbs
+func (c Command) IsFlagged(flagged []string) bool {
+	for _, f := range flagged {
+		if strings.EqualFold(f, c.Verb) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tokenize splits a command on whitespace while keeping double quoted arguments together
+func tokenize(raw string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// argString func
+func argString(args []string, index int) string {
+	if index >= len(args) {
+		return "?"
+	}
+
+	return args[index]
+}
+
+// argInt func
+func argInt(args []string, index int, fallback int) int {
+	if index >= len(args) {
+		return fallback
+	}
+
+	val, err := strconv.Atoi(args[index])
+	if err != nil {
+		return fallback
+	}
+
+	return val
+}
+
+// argFriendlyName func
+func argFriendlyName(args []string, index int) string {
+	if index >= len(args) {
+		return "unknown"
+	}
+
+	return FriendlyName(args[index])
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/mediocregopher/radix/v3"
 	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
 	"go.uber.org/zap"
 )
 
 // Cache struct
+// Addr is the Redis address, used to open the dedicated connections subscriptions need.
 type Cache struct {
 	Client *radix.Pool
+	Addr   string
 }
 
 // GetClient instantiates and returns a connection pool
@@ -46,6 +50,8 @@ func (c *Cache) Get(ctx context.Context, key string) (string, *CacheError) {
 		}
 	}
 
+	metrics.CacheLookup(strings.SplitN(key, ":", 2)[0], getVal != "")
+
 	return getVal, nil
 }
 
@@ -102,6 +108,82 @@ func (c *Cache) Set(ctx context.Context, key, value string, ttl string) *CacheEr
 	return nil
 }
 
+// SetNX sets a key:value pair only if the key does not already exist
+func (c *Cache) SetNX(ctx context.Context, key, value string, ttl string) (bool, *CacheError) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	var set string
+	args := []string{key, value, "NX"}
+	if ttl != "" {
+		args = append(args, "EX", ttl)
+	}
+
+	err := c.Client.Do(radix.Cmd(&set, "SET", args...))
+	if err != nil {
+		return false, &CacheError{
+			Err:     err,
+			Message: "Unable to set key:value pair in Redis",
+		}
+	}
+
+	return set == "OK", nil
+}
+
+// SetNXAll sets each key that does not already exist in one round trip and reports which of them were set
+func (c *Cache) SetNXAll(ctx context.Context, keys []string, value string, ttl string) ([]bool, *CacheError) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	results := make([]radix.MaybeNil, len(keys))
+	replies := make([]string, len(keys))
+
+	var cmds []radix.CmdAction
+	for i, key := range keys {
+		args := []string{key, value, "NX"}
+		if ttl != "" {
+			args = append(args, "EX", ttl)
+		}
+
+		results[i] = radix.MaybeNil{Rcv: &replies[i]}
+		cmds = append(cmds, radix.Cmd(&results[i], "SET", args...))
+	}
+
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+
+	if err := c.Client.Do(radix.Pipeline(cmds...)); err != nil {
+		return nil, &CacheError{
+			Err:     err,
+			Message: "Unable to set key:value pairs in Redis",
+		}
+	}
+
+	set := make([]bool, len(keys))
+	for i := range results {
+		set[i] = !results[i].Nil
+	}
+
+	return set, nil
+}
+
+// Delete removes keys
+func (c *Cache) Delete(ctx context.Context, keys ...string) *CacheError {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := c.Client.Do(radix.Cmd(nil, "DEL", keys...)); err != nil {
+		return &CacheError{
+			Err:     err,
+			Message: "Unable to delete keys",
+		}
+	}
+
+	return nil
+}
+
 // SetStruct sets a key:value pair
 func (c *Cache) SetStruct(ctx context.Context, key string, val interface{}, ttl string) *CacheError {
 	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mediocregopher/radix/v3"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/recovery"
+)
+
+// Subscription receives the messages published to a Redis channel on its own connection
+type Subscription struct {
+	conn radix.PubSubConn
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// Publish sends a message to every process subscribed to a channel
+func (c *Cache) Publish(ctx context.Context, channel string, message string) *CacheError {
+	if err := c.Client.Do(radix.Cmd(nil, "PUBLISH", channel, message)); err != nil {
+		return &CacheError{
+			Err:     err,
+			Message: "Unable to publish message to Redis channel: " + channel,
+		}
+	}
+
+	return nil
+}
+
+// Subscribe calls handle with every message published to a channel until the subscription is closed.
+// The connection is reconnected and resubscribed by itself after a network error; messages published meanwhile are missed.
+func (c *Cache) Subscribe(ctx context.Context, channel string, handle func(message []byte)) (*Subscription, *CacheError) {
+	conn, err := radix.PersistentPubSubWithOpts("tcp", c.Addr)
+	if err != nil {
+		return nil, &CacheError{
+			Err:     err,
+			Message: "Unable to connect to Redis for subscription",
+		}
+	}
+
+	messages := make(chan radix.PubSubMessage, 64)
+	if err := conn.Subscribe(messages, channel); err != nil {
+		conn.Close()
+		return nil, &CacheError{
+			Err:     err,
+			Message: "Unable to subscribe to Redis channel: " + channel,
+		}
+	}
+
+	sub := &Subscription{
+		conn: conn,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(sub.done)
+
+		for {
+			select {
+			case message := <-messages:
+				deliver(ctx, channel, message.Message, handle)
+			case <-sub.stop:
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// deliver runs a subscription's handler for one message, recovering its panics so the subscription keeps going
+func deliver(ctx context.Context, channel string, message []byte, handle func(message []byte)) {
+	defer recovery.Recover(ctx, "redis_subscription:"+channel, "")
+
+	handle(message)
+}
+
+// Close stops the subscription and closes its connection
+func (s *Subscription) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	var err error
+	s.once.Do(func() {
+		// The messages must keep being read until the connection is closed
+		err = s.conn.Close()
+		close(s.stop)
+		<-s.done
+	})
+
+	return err
+}
@@ -0,0 +1,83 @@
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Manager waits for SIGTERM or SIGINT and then runs the registered shutdown steps in order.
+// All steps share one deadline; a step that misses it is logged and the remaining steps still run
+// so the HTTP server and Discord session are always closed.
+type Manager struct {
+	Timeout time.Duration
+
+	steps []step
+}
+
+// step struct
+type step struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewManager func
+func NewManager(config *configs.Config) *Manager {
+	return &Manager{
+		Timeout: config.Shutdown.Timeout * time.Second,
+	}
+}
+
+// OnShutdown registers a step to run on shutdown, after the steps registered before it
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.steps = append(m.steps, step{
+		name: name,
+		fn:   fn,
+	})
+}
+
+// Wait blocks until the process is asked to stop and then shuts down
+func (m *Manager) Wait(ctx context.Context) {
+	ctx = logging.AddValues(ctx, zap.String("scope", logging.GetFuncName()))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	sig := <-signals
+	signal.Stop(signals)
+
+	logger := logging.Logger(ctx)
+	logger.Info("Shutting down", zap.String("signal", sig.String()), zap.Duration("timeout", m.Timeout))
+
+	m.Shutdown(ctx)
+}
+
+// Shutdown runs every step in order within the timeout
+func (m *Manager) Shutdown(ctx context.Context) {
+	sCtx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+
+	for _, s := range m.steps {
+		start := time.Now()
+		err := s.fn(sCtx)
+
+		stepCtx := logging.AddValues(ctx,
+			zap.String("shutdown_step", s.name),
+			zap.Duration("duration", time.Since(start)),
+		)
+		if err != nil {
+			stepCtx = logging.AddValues(stepCtx, zap.NamedError("error", err), zap.String("error_message", "Shutdown step did not finish cleanly"))
+			logger := logging.Logger(stepCtx)
+			logger.Error("error_log")
+			continue
+		}
+
+		logger := logging.Logger(stepCtx)
+		logger.Info("Finished shutdown step")
+	}
+}
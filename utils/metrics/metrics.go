@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "nsm"
+
+// Outcomes recorded for commands and reactions
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+var (
+	// Commands counts commands run, by command name and outcome
+	Commands = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_total",
+		Help:      "Commands run, by command name and outcome.",
+	}, []string{"command", "outcome"})
+
+	// Reactions counts reaction confirmations, by reaction name and outcome
+	Reactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reaction_confirmations_total",
+		Help:      "Reaction confirmations handled, by reaction name and outcome.",
+	}, []string{"reaction", "outcome"})
+
+	// RunnerTickDuration times runner ticks from the start of the tick until every job it queued has finished
+	RunnerTickDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "runner_tick_duration_seconds",
+		Help:      "Time from the start of a runner tick until every job it queued has finished.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"runner"})
+
+	// RunnerSkippedTicks counts ticks skipped because the previous tick's queue was not empty
+	RunnerSkippedTicks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runner_skipped_ticks_total",
+		Help:      "Runner ticks skipped because the previous tick's queue was not empty.",
+	}, []string{"runner"})
+
+	// PollLag times how late scheduled server polls start after they were due
+	PollLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "poll_lag_seconds",
+		Help:      "Time from when a scheduled server poll was due until it started.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"runner"})
+
+	// PollsDeferred counts due server polls put off because the server's last poll was still running or its token was busy
+	PollsDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "polls_deferred_total",
+		Help:      "Due server polls put off, by runner and reason (still_running or token_busy).",
+	}, []string{"runner", "reason"})
+
+	// PollDuration times scheduled server polls, by runner and result
+	PollDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "poll_duration_seconds",
+		Help:      "Time taken by scheduled server polls, by runner and result (ok or failed).",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"runner", "result"})
+
+	// WorkerpoolQueueDepth is the number of jobs waiting in a runner's workerpool once a tick has queued its work
+	WorkerpoolQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workerpool_queue_depth",
+		Help:      "Jobs waiting in a runner's workerpool once a tick has queued its work.",
+	}, []string{"runner"})
+
+	// DiscordErrors counts Discord API errors, by Discord error code and HTTP status
+	DiscordErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discord_api_errors_total",
+		Help:      "Discord API errors, by Discord error code and HTTP status.",
+	}, []string{"code", "status"})
+
+	// NitradoLatency times Nitrado service requests, by method and outcome
+	NitradoLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "nitrado_request_duration_seconds",
+		Help:      "Nitrado service request latency, by method and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "outcome"})
+
+	// GuildConfigLatency times guild config service requests, by operation and outcome
+	GuildConfigLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "guild_config_request_duration_seconds",
+		Help:      "Guild config service request latency, by operation and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "outcome"})
+
+	// CacheLookups counts cache reads, by cache and whether they hit
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache reads, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	// Panics counts panics recovered in Discord handlers and background goroutines, by location
+	Panics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "panics_recovered_total",
+		Help:      "Panics recovered in Discord handlers and background goroutines, by location.",
+	}, []string{"location"})
+
+	// JobQueueDepth is the number of runner jobs waiting for a worker, by queue
+	JobQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_queue_depth",
+		Help:      "Runner jobs waiting for a worker, by queue.",
+	}, []string{"queue"})
+
+	// Jobs counts runner jobs, by queue and result
+	Jobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_total",
+		Help:      "Runner jobs, by queue and result (queued, skipped, done, retried, failed or dropped).",
+	}, []string{"queue", "result"})
+
+	// Leader is 1 while this replica holds the runner lease
+	Leader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader",
+		Help:      "1 while this replica holds the runner lease, otherwise 0.",
+	})
+
+	// EventBusDelivered counts event batches handled by each event bus subscriber
+	EventBusDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_bus_delivered_total",
+		Help:      "Event batches handled by each event bus subscriber.",
+	}, []string{"subscriber"})
+
+	// EventBusDropped counts event batches dropped because a subscriber's queue was full
+	EventBusDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_bus_dropped_total",
+		Help:      "Event batches dropped because the event bus subscriber's queue was full.",
+	}, []string{"subscriber"})
+
+	// EventBusQueueDepth is the number of event batches waiting for each event bus subscriber
+	EventBusQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "event_bus_queue_depth",
+		Help:      "Event batches waiting for each event bus subscriber.",
+	}, []string{"subscriber"})
+)
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Outcome returns the outcome label for an error
+func Outcome(failed bool) string {
+	if failed {
+		return OutcomeError
+	}
+
+	return OutcomeSuccess
+}
+
+// ObserveLatency records the time since start in a latency histogram
+func ObserveLatency(histogram *prometheus.HistogramVec, name string, start time.Time, failed bool) {
+	histogram.WithLabelValues(name, Outcome(failed)).Observe(time.Since(start).Seconds())
+}
+
+// CacheLookup records a cache read
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	CacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// trackerKey string
+type trackerKey string
+
+const outcomeTracker = trackerKey("metrics-outcome")
+
+// tracker records whether a command or reaction reported an error
+type tracker struct {
+	failed int32
+}
+
+// TrackOutcome returns a context that remembers whether the command or reaction run with it reports an error
+func TrackOutcome(ctx context.Context) context.Context {
+	return context.WithValue(ctx, outcomeTracker, &tracker{})
+}
+
+// MarkFailed marks the command or reaction run with ctx as failed
+func MarkFailed(ctx context.Context) {
+	if t, ok := ctx.Value(outcomeTracker).(*tracker); ok {
+		atomic.StoreInt32(&t.failed, 1)
+	}
+}
+
+// Failed reports whether the command or reaction run with ctx was marked as failed
+func Failed(ctx context.Context) bool {
+	if t, ok := ctx.Value(outcomeTracker).(*tracker); ok {
+		return atomic.LoadInt32(&t.failed) == 1
+	}
+
+	return false
+}
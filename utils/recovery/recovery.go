@@ -0,0 +1,141 @@
+package recovery
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/configs"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/logging"
+	"gitlab.com/BIC_Dev/nitrado-server-manager-v3/utils/metrics"
+	"go.uber.org/zap"
+)
+
+// Reporter shows recovered panics in Discord
+type Reporter interface {
+	// NotifyUser tells the user in a channel that what they ran failed, without exposing the panic
+	NotifyUser(ctx context.Context, channelID string)
+	// ReportPanic describes a panic in the operator channel
+	ReportPanic(ctx context.Context, channelID string, report Report)
+}
+
+// Report describes a recovered panic for operators
+type Report struct {
+	Location string
+	Value    interface{}
+	Since    uint64
+	Total    uint64
+}
+
+// reporter sends recovered panics to the operator channel.
+// Each location is reported at most once per interval, with a count of the panics since its last report.
+var reporter = struct {
+	mu        sync.Mutex
+	reporter  Reporter
+	channelID string
+	interval  time.Duration
+	locations map[string]*location
+}{
+	locations: make(map[string]*location),
+}
+
+// location struct
+type location struct {
+	total        uint64
+	unreported   uint64
+	lastReported time.Time
+}
+
+// Init sets the reporter used to show errors to users and report panics to operators.
+// Until it is called panics are only logged.
+func Init(config *configs.Config, r Reporter) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	reporter.reporter = r
+	reporter.channelID = config.Panics.OperatorChannelID
+	reporter.interval = config.Panics.ReportInterval * time.Second
+}
+
+// Recover stops a panic from crashing the bot. It must be deferred directly, as in
+// defer recovery.Recover(ctx, "command", channelID)
+// The panic is logged with its stack, counted and reported to the operator channel,
+// and a command or reaction run with ctx is marked as failed.
+// If channelID is set the user is shown a generic error there.
+func Recover(ctx context.Context, where string, channelID string) {
+	val := recover()
+	if val == nil {
+		return
+	}
+
+	handle(ctx, where, channelID, val, debug.Stack())
+}
+
+// Go runs fn on a new goroutine that recovers its panics, showing the user a generic error in channelID if it is set
+func Go(ctx context.Context, where string, channelID string, fn func()) {
+	go func() {
+		defer Recover(ctx, where, channelID)
+		fn()
+	}()
+}
+
+// Wrap returns fn with its panics recovered, for jobs submitted to a workerpool
+func Wrap(ctx context.Context, where string, fn func()) func() {
+	return func() {
+		defer Recover(ctx, where, "")
+		fn()
+	}
+}
+
+// handle logs, counts and reports a recovered panic
+func handle(ctx context.Context, where string, channelID string, val interface{}, stack []byte) {
+	metrics.Panics.WithLabelValues(where).Inc()
+	metrics.MarkFailed(ctx)
+
+	ctx = logging.AddValues(ctx,
+		zap.String("panic_location", where),
+		zap.Any("error", val),
+		zap.String("trace", string(stack)),
+	)
+	logger := logging.Logger(ctx)
+	logger.Error("panic_log")
+
+	reporter.mu.Lock()
+	r := reporter.reporter
+	operatorChannelID := reporter.channelID
+
+	loc, ok := reporter.locations[where]
+	if !ok {
+		loc = &location{}
+		reporter.locations[where] = loc
+	}
+	loc.total++
+	loc.unreported++
+
+	report := operatorChannelID != "" && time.Since(loc.lastReported) >= reporter.interval
+	var since, total uint64
+	if report {
+		since, total = loc.unreported, loc.total
+		loc.unreported = 0
+		loc.lastReported = time.Now()
+	}
+	reporter.mu.Unlock()
+
+	if r == nil {
+		return
+	}
+
+	if channelID != "" {
+		r.NotifyUser(ctx, channelID)
+	}
+
+	if report {
+		r.ReportPanic(ctx, operatorChannelID, Report{
+			Location: where,
+			Value:    val,
+			Since:    since,
+			Total:    total,
+		})
+	}
+}
@@ -2,9 +2,10 @@ package viewmodels
 
 // GetAllGuildsResponse struct
 type GetAllGuildsResponse struct {
-	Message string        `json:"message"`
-	Count   int           `json:"count"`
-	Guilds  []*SmallGuild `json:"guilds"`
+	Message       string        `json:"message"`
+	Count         int           `json:"count"`
+	Guilds        []*SmallGuild `json:"guilds"`
+	MissingShards []int         `json:"missing_shards,omitempty"`
 }
 
 type SmallGuild struct {
@@ -12,6 +13,7 @@ type SmallGuild struct {
 	Name        string `json:"name"`
 	OwnerID     string `json:"owner_id"`
 	MemberCount int    `json:"member_count"`
+	Shard       int    `json:"shard"`
 }
 
 // VerifySubscriberGuildsResponse struct
@@ -21,6 +23,7 @@ type VerifySubscriberGuildsResponse struct {
 	VerifiedCount   int                   `json:"verified_count"`
 	UnverifiedCount int                   `json:"unverified_count"`
 	Guilds          []*VerifiedSmallGuild `json:"guilds"`
+	MissingShards   []int                 `json:"missing_shards,omitempty"`
 }
 
 type VerifiedSmallGuild struct {
@@ -28,5 +31,6 @@ type VerifiedSmallGuild struct {
 	Name          string `json:"name"`
 	OwnerID       string `json:"owner_id"`
 	MemberCount   int    `json:"member_count"`
+	Shard         int    `json:"shard"`
 	NSMSubscriber bool   `json:"nsm_subscriber"`
 }
@@ -0,0 +1,15 @@
+package viewmodels
+
+// GetEventBusStatsResponse struct
+type GetEventBusStatsResponse struct {
+	Message     string               `json:"message"`
+	Subscribers []EventBusSubscriber `json:"subscribers"`
+}
+
+// EventBusSubscriber struct
+type EventBusSubscriber struct {
+	Name      string `json:"name"`
+	Queued    int    `json:"queued"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+}
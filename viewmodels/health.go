@@ -0,0 +1,21 @@
+package viewmodels
+
+// GetHealthResponse struct
+type GetHealthResponse struct {
+	Message string `json:"message"`
+}
+
+// GetReadinessResponse struct
+type GetReadinessResponse struct {
+	Message string           `json:"message"`
+	Ready   bool             `json:"ready"`
+	Checks  []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck struct
+type ReadinessCheck struct {
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,9 @@
+package viewmodels
+
+import "gitlab.com/BIC_Dev/nitrado-server-manager-v3/models"
+
+// CreateLiveFeedTokenResponse struct
+type CreateLiveFeedTokenResponse struct {
+	Message       string               `json:"message"`
+	LiveFeedToken models.LiveFeedToken `json:"live_feed_token"`
+}
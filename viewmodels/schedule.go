@@ -0,0 +1,22 @@
+package viewmodels
+
+// GetRunnerScheduleResponse struct
+type GetRunnerScheduleResponse struct {
+	Message string            `json:"message"`
+	Runner  string            `json:"runner"`
+	Servers []ScheduledServer `json:"servers"`
+}
+
+// ScheduledServer struct
+type ScheduledServer struct {
+	ServerID       string `json:"server_id"`
+	NextDue        int64  `json:"next_due"`
+	OverdueMS      int64  `json:"overdue_ms"`
+	Backoff        int    `json:"backoff"`
+	InFlight       bool   `json:"in_flight"`
+	LastStarted    int64  `json:"last_started,omitempty"`
+	LastFinished   int64  `json:"last_finished,omitempty"`
+	LastLagMS      int64  `json:"last_lag_ms"`
+	LastDurationMS int64  `json:"last_duration_ms"`
+	LastFailed     bool   `json:"last_failed"`
+}